@@ -0,0 +1,108 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Group is a set of errors sharing a fingerprint, as kept by an Aggregator.
+type Group struct {
+	Fingerprint string
+	Count       int
+	FirstSeen   time.Time
+	LastSeen    time.Time
+	// Exemplar is the first error of the group, or the first one carrying a
+	// stack trace if the first did not.
+	Exemplar error
+	Stack    StackTrace
+}
+
+// Aggregator groups errors by Fingerprint, counting them and keeping one of
+// them per group, for batch jobs producing thousands of similar failures.
+// The zero Aggregator is ready to use.
+type Aggregator struct {
+	mu     sync.Mutex
+	groups map[string]*Group
+}
+
+// NewAggregator returns an empty Aggregator.
+func NewAggregator() *Aggregator {
+	return &Aggregator{}
+}
+
+// Add adds err to the group of its fingerprint.
+// If err is nil, Add does nothing.
+func (a *Aggregator) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	fp, now := Fingerprint(err), time.Now()
+	st, hasStack := StackOf(err)
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if a.groups == nil {
+		a.groups = make(map[string]*Group)
+	}
+
+	g, ok := a.groups[fp]
+	if !ok {
+		g = &Group{Fingerprint: fp, FirstSeen: now}
+		a.groups[fp] = g
+	}
+
+	g.Count++
+	g.LastSeen = now
+
+	if g.Exemplar == nil || (g.Stack == nil && hasStack) {
+		g.Exemplar, g.Stack = err, st
+	}
+}
+
+// Len returns the number of groups.
+func (a *Aggregator) Len() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return len(a.groups)
+}
+
+// Groups returns the groups, most frequent first, the ones of equal count in
+// the order they were first seen.
+func (a *Aggregator) Groups() []Group {
+	a.mu.Lock()
+
+	groups := make([]Group, 0, len(a.groups))
+	for _, g := range a.groups {
+		groups = append(groups, *g)
+	}
+
+	a.mu.Unlock()
+
+	sort.Slice(groups, func(i, j int) bool {
+		if groups[i].Count != groups[j].Count {
+			return groups[i].Count > groups[j].Count
+		}
+
+		return groups[i].FirstSeen.Before(groups[j].FirstSeen)
+	})
+
+	return groups
+}
+
+// Summary writes a line per group to w, most frequent first, with the count,
+// the fingerprint and the message of the exemplar of the group.
+func (a *Aggregator) Summary(w io.Writer) error {
+	for _, g := range a.Groups() {
+		if _, err := fmt.Fprintf(w, "%d\t%s\t%s\n", g.Count, g.Fingerprint, g.Exemplar); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAggregator(t *testing.T) {
+	var a Aggregator
+
+	load := func(id int) error { return Wrapf(io.EOF, "loading user %d", id) }
+
+	a.Add(WithMessage(io.ErrUnexpectedEOF, "parsing"))
+
+	for i := 0; i < 3; i++ {
+		a.Add(load(i))
+	}
+
+	a.Add(nil)
+
+	assert.Equal(t, 2, a.Len())
+
+	groups := a.Groups()
+	if assert.Len(t, groups, 2) {
+		assert.Equal(t, 3, groups[0].Count)
+		assert.Equal(t, "loading user 0: EOF", groups[0].Exemplar.Error())
+		assert.Equal(t, stacksCompiled, len(groups[0].Stack) > 0)
+		assert.False(t, groups[0].LastSeen.Before(groups[0].FirstSeen))
+
+		assert.Equal(t, 1, groups[1].Count)
+		assert.Empty(t, groups[1].Stack)
+	}
+
+	var b strings.Builder
+
+	assert.NoError(t, a.Summary(&b))
+	assert.Equal(t, fmt.Sprintf("3\t%s\tloading user 0: EOF\n1\t%s\tparsing: unexpected EOF\n",
+		groups[0].Fingerprint, groups[1].Fingerprint), b.String())
+}
+
+func TestAggregatorExemplarStack(t *testing.T) {
+	requireStacks(t)
+
+	a := NewAggregator()
+
+	a.Add(io.EOF)
+	a.Add(WithStack(io.EOF))
+
+	// Stack traces are part of fingerprints, so these are different groups.
+	assert.Equal(t, 2, a.Len())
+
+	a = NewAggregator()
+
+	a.Add(WithMessage(io.EOF, "read"))
+	a.Add(WithMessage(io.EOF, "read"))
+
+	groups := a.Groups()
+	if assert.Len(t, groups, 1) {
+		assert.Equal(t, 2, groups[0].Count)
+		assert.Empty(t, groups[0].Stack)
+	}
+}
@@ -0,0 +1,59 @@
+package errors
+
+import "sync/atomic"
+
+// AtomicError holds the error of a pipeline where many goroutines may fail
+// but only one error matters, without locking:
+//
+//     var first errors.AtomicError
+//
+//     for _, job := range jobs {
+//            go func(job Job) {
+//                   first.Set(job.Run())
+//            }(job)
+//     }
+//
+//     ...
+//
+//     return first.Load()
+//
+// By default the first error set wins; see LastWins.
+// The zero AtomicError is ready to use and must not be copied after first use.
+type AtomicError struct {
+	err      atomic.Pointer[error]
+	lastWins bool
+}
+
+// LastWins configures e to keep the last error set instead of the first one.
+// LastWins must be called before e is shared between goroutines.
+func (e *AtomicError) LastWins() *AtomicError {
+	e.lastWins = true
+
+	return e
+}
+
+// Set stores err, unless an error was already stored and the first one wins,
+// and reports whether it did.
+// If err is nil, Set does nothing and returns false.
+func (e *AtomicError) Set(err error) bool {
+	if err == nil {
+		return false
+	}
+
+	if e.lastWins {
+		e.err.Store(&err)
+
+		return true
+	}
+
+	return e.err.CompareAndSwap(nil, &err)
+}
+
+// Load returns the stored error, or nil if none was set.
+func (e *AtomicError) Load() error {
+	if p := e.err.Load(); p != nil {
+		return *p
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAtomicError(t *testing.T) {
+	var e AtomicError
+
+	assert.Nil(t, e.Load())
+	assert.False(t, e.Set(nil))
+	assert.True(t, e.Set(io.EOF))
+	assert.False(t, e.Set(io.ErrUnexpectedEOF))
+	assert.Equal(t, io.EOF, e.Load())
+}
+
+func TestAtomicErrorLastWins(t *testing.T) {
+	e := new(AtomicError).LastWins()
+
+	assert.True(t, e.Set(io.EOF))
+	assert.False(t, e.Set(nil))
+	assert.True(t, e.Set(io.ErrUnexpectedEOF))
+	assert.Equal(t, io.ErrUnexpectedEOF, e.Load())
+}
+
+func TestAtomicErrorConcurrent(t *testing.T) {
+	var (
+		e   AtomicError
+		won int32
+		wg  sync.WaitGroup
+	)
+
+	const goroutines = 16
+
+	for i := 0; i < goroutines; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			if e.Set(Errorf("worker %d", i)) {
+				atomic.AddInt32(&won, 1)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, int32(1), won)
+	assert.NotNil(t, e.Load())
+}
@@ -0,0 +1,159 @@
+// Package awsx enriches the errors returned by the AWS SDK for Go v2.
+//
+// The service error code, request ID and fault of the API error found in the
+// chain are recorded as fields, and the error gets the kind matching its
+// nature, so that throttling and server faults are reported by
+// errors.IsRetryable:
+//
+//     out, err := client.GetObject(ctx, input)
+//     if err != nil {
+//         return nil, awsx.Annotate(err)
+//     }
+//
+// It lives in its own module so that the core package does not depend on the
+// smithy runtime.
+package awsx
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/aws/smithy-go"
+	"github.com/hexbee-net/errors"
+	"github.com/hexbee-net/errors/httpx"
+)
+
+// Fields added by Annotate.
+const (
+	ErrorCodeField = "aws.error_code"
+	RequestIDField = "aws.request_id"
+	FaultField     = "aws.fault"
+	StatusField    = "aws.status"
+	ServiceField   = "aws.service"
+	OperationField = "aws.operation"
+)
+
+// throttlingCodes are the error codes the services use to reject requests
+// exceeding their rate limits.
+//nolint:gochecknoglobals
+var throttlingCodes = map[string]bool{
+	"Throttling":                             true,
+	"ThrottlingException":                    true,
+	"ThrottledException":                     true,
+	"RequestThrottledException":              true,
+	"TooManyRequestsException":               true,
+	"ProvisionedThroughputExceededException": true,
+	"TransactionInProgressException":         true,
+	"RequestLimitExceeded":                   true,
+	"BandwidthLimitExceeded":                 true,
+	"LimitExceededException":                 true,
+	"RequestThrottled":                       true,
+	"SlowDown":                               true,
+	"PriorRequestNotComplete":                true,
+	"EC2ThrottledException":                  true,
+}
+
+// Annotate annotates err with the fields describing the API error found in
+// its chain and with the kind returned by Kind.
+// The service and operation are recorded as well when the chain holds the
+// operation error wrapping every failure of the SDK clients.
+// If err holds no API error, it is returned as is.
+// If err is nil, Annotate returns nil.
+func Annotate(err error) error {
+	var apiErr smithy.APIError
+	if err == nil || !stderrors.As(err, &apiErr) {
+		return err
+	}
+
+	status := statusCode(err)
+	err = errors.WithFields(errors.WithStack(err), Fields(err))
+
+	if code := apiErr.ErrorCode(); code != "" {
+		err = errors.WithCode(err, code)
+	}
+
+	return errors.WithKind(err, Kind(apiErr.ErrorCode(), apiErr.ErrorFault(), status))
+}
+
+// Fields returns the fields describing the API error found in the chain of
+// err: its code, fault, request ID, HTTP status, and the service and operation
+// that failed.
+// Only the details found along the chain are returned.
+func Fields(err error) errors.Fields {
+	type requestIDer interface {
+		ServiceRequestID() string
+	}
+
+	type operationer interface {
+		Service() string
+		Operation() string
+	}
+
+	fields := make(errors.Fields)
+
+	var apiErr smithy.APIError
+	if stderrors.As(err, &apiErr) {
+		setField(fields, ErrorCodeField, apiErr.ErrorCode())
+
+		if fault := apiErr.ErrorFault(); fault != smithy.FaultUnknown {
+			fields[FaultField] = fault.String()
+		}
+	}
+
+	var r requestIDer
+	if stderrors.As(err, &r) {
+		setField(fields, RequestIDField, r.ServiceRequestID())
+	}
+
+	if status := statusCode(err); status != 0 {
+		fields[StatusField] = status
+	}
+
+	var op operationer
+	if stderrors.As(err, &op) {
+		setField(fields, ServiceField, op.Service())
+		setField(fields, OperationField, op.Operation())
+	}
+
+	return fields
+}
+
+// statusCode returns the status of the HTTP response found in the chain of
+// err, or 0 if there is none.
+func statusCode(err error) int {
+	type statusCoder interface {
+		HTTPStatusCode() int
+	}
+
+	var s statusCoder
+	if stderrors.As(err, &s) {
+		return s.HTTPStatusCode()
+	}
+
+	return 0
+}
+
+func setField(fields errors.Fields, key, value string) {
+	if value != "" {
+		fields[key] = value
+	}
+}
+
+// Kind returns the kind of the API error with the given code, fault and HTTP
+// status.
+// Throttling errors are KindResourceExhausted and server faults are
+// KindUnavailable, both being retryable; the other errors get the kind
+// matching their HTTP status.
+// The status is ignored when it is 0.
+func Kind(code string, fault smithy.ErrorFault, status int) errors.Kind {
+	switch {
+	case throttlingCodes[code] || status == http.StatusTooManyRequests:
+		return errors.KindResourceExhausted
+	case fault == smithy.FaultServer || status >= http.StatusInternalServerError:
+		return errors.KindUnavailable
+	case status != 0:
+		return httpx.Kind(status)
+	default:
+		return errors.KindUnknown
+	}
+}
@@ -0,0 +1,87 @@
+package awsx
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/aws/smithy-go"
+	"github.com/hexbee-net/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// responseError mimics the response error of the SDK, carrying the request ID
+// and HTTP status of a failed call.
+type responseError struct {
+	status    int
+	requestID string
+	err       error
+}
+
+func (e *responseError) Error() string            { return fmt.Sprintf("https response error StatusCode: %d, %v", e.status, e.err) }
+func (e *responseError) Unwrap() error            { return e.err }
+func (e *responseError) HTTPStatusCode() int      { return e.status }
+func (e *responseError) ServiceRequestID() string { return e.requestID }
+
+func operationError(status int, code string, fault smithy.ErrorFault) error {
+	return &smithy.OperationError{
+		ServiceID:     "S3",
+		OperationName: "GetObject",
+		Err: &responseError{
+			status:    status,
+			requestID: "req-1",
+			err:       &smithy.GenericAPIError{Code: code, Message: "failed", Fault: fault},
+		},
+	}
+}
+
+func TestAnnotate(t *testing.T) {
+	assert.Nil(t, Annotate(nil))
+	assert.Equal(t, io.EOF, Annotate(io.EOF))
+
+	err := Annotate(errors.Wrap(operationError(http.StatusServiceUnavailable, "SlowDown", smithy.FaultServer), "reading object"))
+
+	assert.Equal(t, errors.KindResourceExhausted, errors.GetKind(err))
+	assert.Equal(t, "SlowDown", errors.GetCode(err))
+	assert.True(t, errors.IsRetryable(err))
+	assert.Equal(t, errors.Fields{
+		ErrorCodeField: "SlowDown",
+		FaultField:     "server",
+		RequestIDField: "req-1",
+		StatusField:    http.StatusServiceUnavailable,
+		ServiceField:   "S3",
+		OperationField: "GetObject",
+	}, errors.GetFields(err))
+
+	err = Annotate(operationError(http.StatusNotFound, "NoSuchKey", smithy.FaultClient))
+
+	assert.Equal(t, errors.KindNotFound, errors.GetKind(err))
+	assert.False(t, errors.IsRetryable(err))
+}
+
+func TestFields(t *testing.T) {
+	assert.Empty(t, Fields(io.EOF))
+	assert.Equal(t, errors.Fields{ErrorCodeField: "Oops"}, Fields(&smithy.GenericAPIError{Code: "Oops"}))
+}
+
+func TestKind(t *testing.T) {
+	tests := []struct {
+		code   string
+		fault  smithy.ErrorFault
+		status int
+		want   errors.Kind
+	}{
+		{"ThrottlingException", smithy.FaultClient, http.StatusBadRequest, errors.KindResourceExhausted},
+		{"Whatever", smithy.FaultUnknown, http.StatusTooManyRequests, errors.KindResourceExhausted},
+		{"InternalError", smithy.FaultServer, 0, errors.KindUnavailable},
+		{"InternalError", smithy.FaultUnknown, http.StatusInternalServerError, errors.KindUnavailable},
+		{"AccessDenied", smithy.FaultClient, http.StatusForbidden, errors.KindPermissionDenied},
+		{"ValidationException", smithy.FaultClient, http.StatusBadRequest, errors.KindInvalidArgument},
+		{"Unknown", smithy.FaultUnknown, 0, errors.KindUnknown},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Kind(tt.code, tt.fault, tt.status), tt.code)
+	}
+}
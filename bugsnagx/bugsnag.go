@@ -0,0 +1,99 @@
+// Package bugsnagx converts errors into the shapes expected by the Bugsnag notifier.
+//
+// The adapter satisfies the ErrorWithCallers interface of
+// github.com/bugsnag/bugsnag-go/v2/errors without importing the notifier:
+//
+//     bugsnag.Notify(
+//         bugsnagx.Error(err),
+//         bugsnag.ErrorClass{Name: bugsnagx.ErrorClass(err)},
+//         bugsnag.MetaData(bugsnagx.MetaData(err)),
+//     )
+package bugsnagx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hexbee-net/errors"
+)
+
+// DefaultTab is the metadata tab used for fields whose key has no tab prefix.
+const DefaultTab = "fields"
+
+type notifiable struct {
+	error
+	callers []uintptr
+}
+
+// Error returns an error reporting the stack trace recorded closest to the
+// origin of the chain, so that Bugsnag shows where the failure happened rather
+// than where it was last wrapped.
+// If err is nil, Error returns nil.
+func Error(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &notifiable{
+		error:   err,
+		callers: Callers(err),
+	}
+}
+
+// Callers implements Bugsnag's ErrorWithCallers interface.
+func (n *notifiable) Callers() []uintptr {
+	return n.callers
+}
+
+func (n *notifiable) Cause() error {
+	return n.error
+}
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (n *notifiable) Unwrap() error {
+	return n.error
+}
+
+// Callers returns the program counters of the deepest stack trace in the chain.
+// If no layer of the chain carries a stack trace, Callers returns nil.
+func Callers(err error) []uintptr {
+	st, ok := errors.StackOf(err)
+	if !ok {
+		return nil
+	}
+
+	return st.PCs()
+}
+
+// ErrorClass returns the name Bugsnag should group the error under:
+// the type of the underlying cause.
+func ErrorClass(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	return fmt.Sprintf("%T", errors.Cause(err))
+}
+
+// MetaData returns the fields of the chain grouped into Bugsnag metadata tabs.
+// A field key of the form "tab.key" is placed in the named tab, any other key
+// lands in DefaultTab.
+func MetaData(err error) map[string]map[string]interface{} {
+	md := make(map[string]map[string]interface{})
+
+	for k, v := range errors.GetFields(err) {
+		tab, key := DefaultTab, k
+
+		if i := strings.Index(k, "."); i > 0 && i < len(k)-1 {
+			tab, key = k[:i], k[i+1:]
+		}
+
+		if md[tab] == nil {
+			md[tab] = make(map[string]interface{})
+		}
+
+		md[tab][key] = v
+	}
+
+	return md
+}
@@ -0,0 +1,99 @@
+package bugsnagx
+
+import (
+	"io"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestErrorNil(t *testing.T) {
+	assert.Nil(t, Error(nil))
+}
+
+func TestCallers(t *testing.T) {
+	inner := errors.New("inner")
+	outer := errors.Wrap(inner, "outer")
+	wrapped := errors.Wrap(io.EOF, "read error")
+
+	tests := []struct {
+		err  error
+		want []uintptr
+	}{
+		{nil, nil},
+		{io.EOF, nil},
+		{inner, pcs(inner)},
+		{outer, pcs(inner)},
+		{errors.WithField(outer, "key", "value"), pcs(inner)},
+		{wrapped, pcs(wrapped)},
+	}
+
+	for i, tt := range tests {
+		assert.Equal(t, tt.want, Callers(tt.err), "test %d", i+1)
+	}
+}
+
+func TestError(t *testing.T) {
+	inner := errors.New("inner")
+	err := Error(errors.Wrap(inner, "outer"))
+
+	type callers interface {
+		Callers() []uintptr
+	}
+
+	c, ok := err.(callers)
+	assert.True(t, ok)
+	assert.Equal(t, pcs(inner), c.Callers())
+	assert.Equal(t, "outer: inner", err.Error())
+	assert.Equal(t, inner, errors.Cause(err))
+}
+
+func TestErrorClass(t *testing.T) {
+	assert.Equal(t, "", ErrorClass(nil))
+	assert.Equal(t, "*errors.errorString", ErrorClass(errors.Wrap(io.EOF, "read error")))
+}
+
+func TestMetaData(t *testing.T) {
+	tests := []struct {
+		err  error
+		want map[string]map[string]interface{}
+	}{
+		{
+			err:  io.EOF,
+			want: map[string]map[string]interface{}{},
+		},
+		{
+			err: errors.WithFields(io.EOF, errors.Fields{
+				"key":        "value",
+				"request.id": 42,
+				".hidden":    true,
+				"trailing.":  false,
+			}),
+			want: map[string]map[string]interface{}{
+				DefaultTab: {"key": "value", ".hidden": true, "trailing.": false},
+				"request":  {"id": 42},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, MetaData(tt.err))
+	}
+}
+
+func pcs(err error) []uintptr {
+	trace := err.(interface{ StackTrace() pkgerrors.StackTrace }).StackTrace()
+	if len(trace) == 0 {
+		return nil
+	}
+
+	out := make([]uintptr, len(trace))
+
+	for i, f := range trace {
+		out[i] = uintptr(f)
+	}
+
+	return out
+}
@@ -0,0 +1,41 @@
+package errors
+
+// NewWithCaller returns an error with the supplied message.
+// NewWithCaller records only the location it was called from, which is much
+// cheaper than the stack trace recorded by New, for hot paths.
+func NewWithCaller(message string) error {
+	err := runHooks(&fundamental{
+		msg:   message,
+		stack: caller(),
+	}, nil)
+
+	publishCreated(err, nil)
+
+	return err
+}
+
+// WithCaller annotates err with the location WithCaller is called from.
+// It is a cheaper alternative to WithStack for hot paths, the location being
+// rendered by %+v and in the structured representations as a stack trace of
+// a single frame.
+// If err is nil, WithCaller returns nil.
+func WithCaller(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return withStackOn(err, err, caller())
+}
+
+// GetCaller returns the location the error was created at: the top frame of
+// the deepest stack trace of the chain, whether recorded by WithCaller or as
+// a complete stack trace.
+// If the chain carries no stack trace, GetCaller returns false.
+func GetCaller(err error) (StackFrame, bool) {
+	st := deepestStack(err)
+	if len(st) == 0 {
+		return StackFrame{}, false
+	}
+
+	return resolve(st[:1])[0], true
+}
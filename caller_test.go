@@ -0,0 +1,47 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"regexp"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewWithCaller(t *testing.T) {
+	requireStacks(t)
+
+	err := NewWithCaller("boom")
+
+	assert.Equal(t, "boom", err.Error())
+	assert.Regexp(t, regexp.MustCompile(`^boom\ngithub.com/hexbee-net/errors.TestNewWithCaller\n\t.+/caller_test.go:\d+$`), fmt.Sprintf("%+v", err))
+
+	frame, ok := GetCaller(err)
+	assert.True(t, ok)
+	assert.Equal(t, "github.com/hexbee-net/errors.TestNewWithCaller", frame.Function)
+	assert.Regexp(t, `/caller_test.go$`, frame.File)
+}
+
+func TestWithCaller(t *testing.T) {
+	requireStacks(t)
+
+	assert.Nil(t, WithCaller(nil))
+
+	err := WithCaller(io.EOF)
+
+	assert.Equal(t, "EOF", err.Error())
+	assert.Regexp(t, regexp.MustCompile(`^EOF\ngithub.com/hexbee-net/errors.TestWithCaller\n\t.+/caller_test.go:\d+$`), fmt.Sprintf("%+v", err))
+	assert.Len(t, NewDocument(err).Layers[0].Stack, 1)
+}
+
+func TestGetCaller(t *testing.T) {
+	requireStacks(t)
+
+	_, ok := GetCaller(io.EOF)
+	assert.False(t, ok)
+
+	frame, ok := GetCaller(Wrap(New("boom"), "saving"))
+	assert.True(t, ok)
+	assert.Equal(t, "github.com/hexbee-net/errors.TestGetCaller", frame.Function)
+}
@@ -0,0 +1,298 @@
+//go:build !nostack
+
+package errors
+
+import (
+	"fmt"
+	"io"
+	"runtime"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// stack is the stack trace carried by the errors, referencing the captured
+// call stack.
+// It is nil for the errors built without stack trace, such as the zero
+// MultiError.
+type stack struct {
+	*callStack
+}
+
+// callStack represents a stack of program counters.
+// Stacks decoded from another process carry resolved frames instead.
+// The wall time the stack was captured at is only recorded when enabled
+// with RecordTimes, or by WithTime.
+type callStack struct {
+	pcs      []uintptr
+	frames   []StackFrame
+	at       time.Time
+	sampling int8
+
+	// The frames resolved from pcs, only when first needed by an encoding.
+	resolveOnce sync.Once
+	resolvedPCs []StackFrame
+
+	rendered atomic.Pointer[renderedStack]
+}
+
+func callers() stack {
+	return callersSkip(1)
+}
+
+// callersSkip returns the stack of the caller of the exported function
+// calling callersSkip, skip frames up.
+func callersSkip(skip int) stack {
+	if !capturingStacks() {
+		return emptyStack()
+	}
+
+	if s := getStackSampling(); s != (StackSampling{}) {
+		return sampledCallers(s, skip)
+	}
+
+	return fullCallers(skip)
+}
+
+// caller returns a stack holding only the frame of the caller of the
+// function calling caller.
+func caller() stack {
+	if !capturingStacks() {
+		return emptyStack()
+	}
+
+	pcs := make([]uintptr, 1)
+
+	n := runtime.Callers(skipCallers, pcs)
+
+	s := &callStack{pcs: pcs[0:n]}
+	if recordingTimes() {
+		s.at = time.Now()
+	}
+
+	return stack{s}
+}
+
+// decodedStack returns the stack holding frames, resolved by another process.
+func decodedStack(frames []StackFrame) stack {
+	return stack{&callStack{frames: frames}}
+}
+
+// isZero reports whether s references no call stack.
+func (s stack) isZero() bool {
+	return s.callStack == nil
+}
+
+// timed returns s, recording the wall time it was captured at if it was not.
+func (s stack) timed() stack {
+	if s.callStack == noStack {
+		s.callStack = &callStack{}
+	}
+
+	if s.at.IsZero() {
+		s.at = time.Now()
+	}
+
+	return s
+}
+
+func (s *callStack) Format(st fmt.State, verb rune) {
+	if verb == 'v' && st.Flag('+') {
+		_, _ = io.WriteString(st, s.render(0))
+	}
+}
+
+// formatElided prints the stack like Format does with the %+v verb, leaving
+// out the outermost frames it shares with inner, the stack printed before it
+// by an inner layer of the chain.
+func (s *callStack) formatElided(st fmt.State, inner errors.StackTrace) {
+	common := 0
+	for common < len(s.pcs) && common < len(inner) &&
+		s.pcs[len(s.pcs)-1-common] == uintptr(inner[len(inner)-1-common]) {
+		common++
+	}
+
+	// Keep at least the frame the stack was recorded at.
+	if common == len(s.pcs) && common > 0 {
+		common--
+	}
+
+	_, _ = io.WriteString(st, s.render(common))
+}
+
+// renderedStack is the rendering of a stack by the %+v verb.
+type renderedStack struct {
+	generation uint64
+	common     int
+	text       string
+}
+
+// render returns the stack as printed by the %+v verb, without its common
+// outermost frames.
+// The rendering is kept for the next calls, as the same error is often
+// formatted several times, such as for logging and reporting, until the
+// formatting options change.
+func (s *callStack) render(common int) string {
+	gen := atomic.LoadUint64(&formatGeneration)
+	if r := s.rendered.Load(); r != nil && r.generation == gen && r.common == common {
+		return r.text
+	}
+
+	var b strings.Builder
+
+	for _, pc := range s.pcs[:len(s.pcs)-common] {
+		formatFrame(&b, Frame(pc))
+	}
+
+	for _, f := range s.frames {
+		formatLocation(&b, f.Function, f.File, f.Line)
+	}
+
+	if common > 0 {
+		_, _ = fmt.Fprintf(&b, "\n... %d common frames omitted", common)
+	}
+
+	s.rendered.Store(&renderedStack{generation: gen, common: common, text: b.String()})
+
+	return b.String()
+}
+
+func (s *callStack) StackTrace() errors.StackTrace {
+	f := make([]errors.Frame, len(s.pcs))
+	for i := 0; i < len(f); i++ {
+		f[i] = errors.Frame(s.pcs[i])
+	}
+
+	return f
+}
+
+// resolved returns the frames of the stack.
+// The program counters are only symbolized on the first call, the frames
+// being kept for the next ones.
+func (s *callStack) resolved() []StackFrame {
+	if s.frames != nil {
+		return s.frames
+	}
+
+	s.resolveOnce.Do(func() {
+		s.resolvedPCs = resolve(s.StackTrace())
+	})
+
+	return s.resolvedPCs
+}
+
+// Time returns the wall time the stack was captured at, or the zero time if
+// it was not recorded.
+func (s *callStack) Time() time.Time {
+	return s.at
+}
+
+// fields returns the fields recording the sampling decision of the stack.
+func (s *callStack) fields() Fields {
+	if s == nil || s.sampling == notSampled {
+		return nil
+	}
+
+	return Fields{StackSampledField: s.sampling == sampleKept}
+}
+
+// stackDepth is the maximum number of frames of the captured stacks.
+const stackDepth = 32
+
+//nolint:gochecknoglobals // buffers reused across captures.
+var pcsPool = sync.Pool{
+	New: func() interface{} { return new([stackDepth]uintptr) },
+}
+
+// fullCallers captures the stack like callersSkip, skip frames up.
+// The program counters are captured in a pooled buffer, only the frames
+// actually captured being copied out of it.
+func fullCallers(skip int) stack {
+	buf, _ := pcsPool.Get().(*[stackDepth]uintptr)
+
+	n := runtime.Callers(skipCallers+skip+1, buf[:])
+
+	s := &callStack{pcs: make([]uintptr, n)}
+	copy(s.pcs, buf[:n])
+	pcsPool.Put(buf)
+
+	if trimmingHarness() {
+		s.pcs = trimHarnessFrames(s.pcs)
+	}
+
+	if recordingTimes() {
+		s.at = time.Now()
+	}
+
+	return stack{s}
+}
+
+// sampledCallers captures the stack like callersSkip, skip frames up, when
+// it is part of the sample of s, or only the frame of the call site
+// otherwise.
+func sampledCallers(s StackSampling, skip int) stack {
+	var site [1]uintptr
+
+	if runtime.Callers(skipCallers+skip+1, site[:]) == 0 {
+		return stack{&callStack{}}
+	}
+
+	if !s.keep(site[0]) {
+		st := &callStack{pcs: site[:], sampling: sampleSkipped}
+		if recordingTimes() {
+			st.at = time.Now()
+		}
+
+		return stack{st}
+	}
+
+	st := fullCallers(skip + 1)
+	st.sampling = sampleKept
+
+	return st
+}
+
+// trimPanicFrames removes from s the frames of the runtime handling a panic,
+// such as runtime.gopanic or runtime.sigpanic, and the ones above them, so that
+// the stack starts where the panic occurred.
+func trimPanicFrames(s stack) stack {
+	for i, pc := range s.pcs {
+		if Frame(pc).Function() != "runtime.gopanic" {
+			continue
+		}
+
+		i++
+		for i < len(s.pcs)-1 && strings.HasPrefix(Frame(s.pcs[i]).Function(), "runtime.") {
+			i++
+		}
+
+		s.pcs = s.pcs[i:]
+
+		break
+	}
+
+	return s
+}
+
+// noStack is the stack shared by the errors created while the capture of
+// stack traces is disabled and times are not recorded.
+//nolint:gochecknoglobals // never modified.
+var noStack = &callStack{}
+
+// emptyStack returns the stack of the errors created while the capture of
+// stack traces is disabled.
+func emptyStack() stack {
+	if !recordingTimes() {
+		return stack{noStack}
+	}
+
+	return stack{&callStack{at: time.Now()}}
+}
+
+// skipCallers is the number of frames skipped by callersSkip and caller to
+// start at the caller of the exported function calling them: runtime.Callers,
+// callersSkip or caller, and the exported function itself.
+const skipCallers = 3
@@ -0,0 +1,69 @@
+//go:build nostack
+
+package errors
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// With the nostack build tag, no stack trace is ever captured: the errors
+// carry an empty stack, which takes no space in them, as if
+// SetStackCapture(false) was called, without the cost of checking it.
+// No wall time is recorded either, by RecordTimes or WithTime, and the frames
+// decoded from the encodings of another process are dropped.
+
+// stack is the stack trace carried by the errors, holding nothing.
+// It comes first in the errors embedding it, a zero-size last field being
+// padded to keep its address inside the error.
+type stack struct{}
+
+func callers() stack {
+	return stack{}
+}
+
+func callersSkip(int) stack {
+	return stack{}
+}
+
+func caller() stack {
+	return stack{}
+}
+
+func decodedStack([]StackFrame) stack {
+	return stack{}
+}
+
+func trimPanicFrames(s stack) stack {
+	return s
+}
+
+func (stack) isZero() bool {
+	return true
+}
+
+func (s stack) timed() stack {
+	return s
+}
+
+func (stack) Format(fmt.State, rune) {}
+
+func (stack) formatElided(fmt.State, errors.StackTrace) {}
+
+func (stack) StackTrace() errors.StackTrace {
+	return errors.StackTrace{}
+}
+
+func (stack) resolved() []StackFrame {
+	return nil
+}
+
+func (stack) Time() time.Time {
+	return time.Time{}
+}
+
+func (stack) fields() Fields {
+	return nil
+}
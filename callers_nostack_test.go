@@ -0,0 +1,34 @@
+//go:build nostack
+
+package errors
+
+import (
+	"io"
+	"testing"
+	"unsafe"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNoStack(t *testing.T) {
+	for _, err := range []error{
+		New("boom"),
+		Wrap(io.EOF, "reading"),
+		WithStack(io.EOF),
+		NewWithCaller("boom"),
+	} {
+		_, ok := StackOf(err)
+		assert.False(t, ok, err.Error())
+	}
+
+	assert.Zero(t, unsafe.Sizeof(stack{}))
+	assert.Equal(t, unsafe.Sizeof(withStack{}.error), unsafe.Sizeof(withStack{}))
+}
+
+func TestNoStackTimes(t *testing.T) {
+	RecordTimes(true)
+	defer RecordTimes(false)
+
+	assert.True(t, WithTime(io.EOF).(*withStack).Time().IsZero())
+	assert.Empty(t, Timeline(Wrap(New("boom"), "reading")))
+}
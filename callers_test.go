@@ -0,0 +1,48 @@
+//go:build !nostack
+
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFullCallersAllocations(t *testing.T) {
+	var s stack
+
+	allocs := testing.AllocsPerRun(100, func() {
+		s = fullCallers(0)
+	})
+
+	// The stack and its program counters.
+	assert.Equal(t, 2.0, allocs)
+	assert.Equal(t, len(s.pcs), cap(s.pcs))
+}
+
+func TestStackRenderCached(t *testing.T) {
+	s := New("boom").(*fundamental).stack
+
+	first := s.render(0)
+	assert.Equal(t, first, s.rendered.Load().text)
+
+	s.rendered.Load().text = "cached"
+	assert.Equal(t, "cached", s.render(0))
+	assert.NotEqual(t, "cached", s.render(1))
+
+	ClickableFrames(true)
+	defer ClickableFrames(false)
+
+	assert.NotEqual(t, first, s.render(0))
+	assert.NotEqual(t, "cached", s.render(0))
+}
+
+func TestStackResolvedOnce(t *testing.T) {
+	s := New("boom").(*fundamental).stack
+
+	assert.Nil(t, s.resolvedPCs)
+
+	frames := s.resolved()
+	assert.NotEmpty(t, frames)
+	assert.Equal(t, &frames[0], &s.resolved()[0])
+}
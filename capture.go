@@ -0,0 +1,55 @@
+package errors
+
+import (
+	"os"
+	"strconv"
+	"sync/atomic"
+)
+
+// StackCaptureEnv is the environment variable disabling the capture of stack
+// traces when set to a false value, such as "false" or "0", unless
+// SetStackCapture is called.
+const StackCaptureEnv = "ERRORS_STACK_CAPTURE"
+
+// States of the capture of stack traces.
+const (
+	captureUnset int32 = iota
+	captureEnabled
+	captureDisabled
+)
+
+//nolint:gochecknoglobals // the capture of stack traces is configured process-wide.
+var stackCapture int32
+
+// SetStackCapture enables or disables the capture of stack traces, for
+// programs not consuming them to save the cost of runtime.Callers: once
+// disabled, the errors created or wrapped carry empty stack traces, and behave
+// like errors without stack trace in formatting, encodings and StackOf.
+// Stack traces are captured by default, unless disabled by StackCaptureEnv,
+// or at compile time by the nostack build tag.
+func SetStackCapture(enabled bool) {
+	v := captureDisabled
+	if enabled {
+		v = captureEnabled
+	}
+
+	atomic.StoreInt32(&stackCapture, v)
+}
+
+// capturingStacks reports whether stack traces are captured.
+func capturingStacks() bool {
+	v := atomic.LoadInt32(&stackCapture)
+	if v == captureUnset {
+		v = captureEnabled
+		if enabled, err := strconv.ParseBool(os.Getenv(StackCaptureEnv)); err == nil && !enabled {
+			v = captureDisabled
+		}
+
+		// SetStackCapture wins over the environment.
+		if !atomic.CompareAndSwapInt32(&stackCapture, captureUnset, v) {
+			v = atomic.LoadInt32(&stackCapture)
+		}
+	}
+
+	return v == captureEnabled
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/hexbee-net/errors/internal/nostack"
+	"github.com/stretchr/testify/assert"
+)
+
+// stacksCompiled reports whether the capture of stack traces is compiled in,
+// without the nostack build tag.
+const stacksCompiled = !nostack.Enabled
+
+func TestSetStackCapture(t *testing.T) {
+	SetStackCapture(false)
+	defer SetStackCapture(true)
+
+	for _, err := range []error{
+		New("boom"),
+		Wrap(io.EOF, "reading"),
+		WithStack(io.EOF),
+		NewWithCaller("boom"),
+	} {
+		_, ok := StackOf(err)
+		assert.False(t, ok, err.Error())
+		assert.NotContains(t, fmt.Sprintf("%+v", err), "\t", err.Error())
+	}
+
+	SetStackCapture(true)
+
+	_, ok := StackOf(New("boom"))
+	assert.Equal(t, stacksCompiled, ok)
+}
+
+// requireStacks skips the test when the capture of stack traces is compiled
+// out by the nostack build tag.
+func requireStacks(t *testing.T) {
+	t.Helper()
+
+	if !stacksCompiled {
+		t.Skip("stack traces are compiled out by the nostack build tag")
+	}
+}
+
+func TestStackCaptureEnv(t *testing.T) {
+	defer atomic.StoreInt32(&stackCapture, captureUnset)
+
+	tests := []struct {
+		env  string
+		want bool
+	}{
+		{"", true},
+		{"true", true},
+		{"0", false},
+		{"false", false},
+		{"nonsense", true},
+	}
+
+	for _, tt := range tests {
+		t.Setenv(StackCaptureEnv, tt.env)
+		atomic.StoreInt32(&stackCapture, captureUnset)
+
+		assert.Equal(t, tt.want, capturingStacks(), tt.env)
+	}
+
+	t.Setenv(StackCaptureEnv, "false")
+	atomic.StoreInt32(&stackCapture, captureUnset)
+	SetStackCapture(true)
+
+	assert.True(t, capturingStacks())
+}
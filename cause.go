@@ -0,0 +1,115 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+)
+
+// WithCancelCause behaves like context.WithCancelCause, except that the
+// returned function records a stack trace at the point it is called along
+// with the cause, so that FromContext can tell where the context was
+// canceled.
+// The cause is only recorded, and passed through the hooks, by the call
+// canceling the context: once the context is done, the function does
+// nothing. Calling it with a nil cause, as in a deferred call releasing the
+// context, cancels it with context.Canceled, without stack trace.
+func WithCancelCause(parent context.Context) (context.Context, context.CancelCauseFunc) {
+	ctx, cancel := context.WithCancelCause(parent)
+
+	return ctx, func(cause error) {
+		if cause == nil || ctx.Err() != nil {
+			cancel(cause)
+
+			return
+		}
+
+		cancel(withStackOn(cause, cause, callers()))
+	}
+}
+
+// FromContext returns the error describing why ctx is done, combining
+// ctx.Err() with the cause given by context.Cause, and annotated with
+// KindCanceled or KindDeadlineExceeded and the fields describing ctx.
+// The returned error matches both ctx.Err() and the cause with errors.Is, and
+// carries the stack trace recorded by the function returned by
+// WithCancelCause.
+// If ctx is not done, FromContext returns nil.
+func FromContext(ctx context.Context) error {
+	err := ctx.Err()
+	if err == nil {
+		return nil
+	}
+
+	kind := KindCanceled
+	if err == context.DeadlineExceeded {
+		kind = KindDeadlineExceeded
+	}
+
+	w := err
+
+	if cause := context.Cause(ctx); cause != nil && cause != err {
+		w = &contextError{
+			err:   err,
+			cause: cause,
+		}
+	}
+
+	w = withContextFields(ctx, w)
+
+	return runHooks(&withKind{
+		cause: w,
+		kind:  kind,
+	}, w)
+}
+
+// contextError is the error of a done context along with its cause.
+type contextError struct {
+	err   error
+	cause error
+}
+
+func (e *contextError) Error() string {
+	return e.err.Error() + ": " + e.cause.Error()
+}
+
+func (e *contextError) Cause() error {
+	return e.cause
+}
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (e *contextError) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether target is the error of the context.
+func (e *contextError) Is(target error) bool {
+	return target == e.err
+}
+
+func (e *contextError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			formatGoString(s, e)
+
+			return
+		}
+
+		if s.Flag('+') {
+			if customFormat(s, e) {
+				return
+			}
+
+			formatCause(s, e.cause)
+			_, _ = io.WriteString(s, "\n")
+			formatMessage(s, e.err.Error())
+
+			return
+		}
+
+		fallthrough
+	case 's', 'q':
+		_, _ = io.WriteString(s, e.Error())
+	}
+}
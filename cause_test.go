@@ -0,0 +1,104 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func cancelWith(cancel context.CancelCauseFunc, cause error) {
+	cancel(cause)
+}
+
+func TestFromContext(t *testing.T) {
+	ctx, cancel := WithCancelCause(context.Background())
+
+	assert.Nil(t, FromContext(ctx))
+
+	cancelWith(cancel, io.EOF)
+
+	err := FromContext(ctx)
+
+	assert.Equal(t, "context canceled: EOF", err.Error())
+	assert.Equal(t, KindCanceled, GetKind(err))
+	assert.True(t, stderrors.Is(err, context.Canceled))
+	assert.True(t, stderrors.Is(err, io.EOF))
+
+	if stacksCompiled {
+		assert.Contains(t, fmt.Sprintf("%+v", err), "errors.cancelWith\n")
+	}
+}
+
+func TestFromContextNilCause(t *testing.T) {
+	ctx, cancel := WithCancelCause(context.Background())
+	cancelWith(cancel, nil)
+
+	err := FromContext(ctx)
+
+	assert.Equal(t, "context canceled", err.Error())
+	assert.True(t, stderrors.Is(err, context.Canceled))
+	assert.Equal(t, context.Canceled, context.Cause(ctx))
+	assert.NotContains(t, fmt.Sprintf("%+v", err), "errors.cancelWith\n")
+}
+
+func TestWithCancelCauseHooks(t *testing.T) {
+	withHooks(t, func(err error) error {
+		return WithField(err, "build", "v1.2.3")
+	})
+
+	ctx, cancel := WithCancelCause(context.Background())
+	cancelWith(cancel, io.EOF)
+
+	assert.Equal(t, Fields{"build": "v1.2.3"}, GetFields(context.Cause(ctx)))
+}
+
+func TestWithCancelCauseOnce(t *testing.T) {
+	var calls int32
+
+	withHooks(t, func(err error) error {
+		calls++
+
+		return err
+	})
+
+	sub := Subscribe(4)
+	defer sub.Close()
+
+	_, cancel := WithCancelCause(context.Background())
+	cancel(nil)
+	cancel(io.EOF)
+	cancel(nil)
+	assert.Zero(t, calls, "releasing the context records no cause")
+
+	ctx, cancel := WithCancelCause(context.Background())
+	cancel(io.EOF)
+	cancel(io.ErrUnexpectedEOF)
+	cancel(nil)
+
+	assert.Equal(t, int32(1), calls)
+	assert.Equal(t, io.EOF, Cause(context.Cause(ctx)))
+	assert.Len(t, sub.C, 1)
+}
+
+func TestFromContextWithoutCause(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := FromContext(ctx)
+
+	assert.Equal(t, "context canceled", err.Error())
+	assert.True(t, IsCanceled(err))
+
+	ctx, cancel = context.WithTimeout(context.Background(), -time.Second)
+	defer cancel()
+
+	err = FromContext(ctx)
+
+	assert.Equal(t, KindDeadlineExceeded, GetKind(err))
+	assert.True(t, stderrors.Is(err, context.DeadlineExceeded))
+}
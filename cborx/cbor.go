@@ -0,0 +1,391 @@
+// Package cborx encodes the structured representation of error chains with
+// CBOR (RFC 8949).
+//
+// The document has the same shape as the JSON one produced by errors.ToJSON.
+// Integers, lengths and map key order follow the deterministic encoding
+// requirements of RFC 8949 section 4.2, floating-point numbers are always
+// encoded on 64 bits.
+package cborx
+
+import (
+	"encoding/binary"
+	"math"
+	"sort"
+
+	"github.com/hexbee-net/errors"
+	"github.com/hexbee-net/errors/internal/docmap"
+)
+
+// ErrInvalid is returned when decoding malformed CBOR data.
+const ErrInvalid = errors.Error("cborx: invalid data")
+
+// Major types.
+const (
+	majorUint   = 0
+	majorNegInt = 1
+	majorBytes  = 2
+	majorText   = 3
+	majorArray  = 4
+	majorMap    = 5
+	majorTag    = 6
+	majorSimple = 7
+
+	majorShift = 5
+	infoMask   = 0x1f
+)
+
+// Additional information values.
+const (
+	infoDirect     = 23
+	infoUint8      = 24
+	infoUint16     = 25
+	infoUint32     = 26
+	infoUint64     = 27
+	infoIndefinite = 31
+
+	simpleFalse   = 20
+	simpleTrue    = 21
+	simpleNull    = 22
+	simpleUndef   = 23
+	simpleFloat16 = infoUint16
+	simpleFloat32 = infoUint32
+	simpleFloat64 = infoUint64
+
+	breakCode = majorSimple<<majorShift | infoIndefinite
+)
+
+//nolint:gochecknoglobals // read-only
+var sizes = map[byte]int{infoUint8: 1, infoUint16: 2, infoUint32: 4, infoUint64: 8}
+
+// Encode returns the CBOR encoding of the structured representation of err.
+func Encode(err error) ([]byte, error) {
+	return appendValue(nil, docmap.Encode(errors.NewDocument(err))), nil
+}
+
+// Decode rebuilds the error chain encoded by Encode as errors.RemoteError values.
+// If data is malformed, the decoding error is returned.
+// If the document has no layers, Decode returns nil.
+func Decode(data []byte) error {
+	d := &decoder{data: data}
+
+	tree, err := d.value()
+	if err == nil && len(d.data) > 0 {
+		err = errors.Wrap(ErrInvalid, "trailing data")
+	}
+
+	if err != nil {
+		return errors.WithMessage(err, "decoding remote error")
+	}
+
+	doc, err := docmap.Decode(tree)
+	if err != nil {
+		return errors.WithMessage(err, "decoding remote error")
+	}
+
+	return errors.FromDocument(doc)
+}
+
+func appendValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, majorSimple<<majorShift|simpleNull)
+	case bool:
+		if v {
+			return append(b, majorSimple<<majorShift|simpleTrue)
+		}
+
+		return append(b, majorSimple<<majorShift|simpleFalse)
+	case int64:
+		if v < 0 {
+			return appendHead(b, majorNegInt, uint64(-1-v))
+		}
+
+		return appendHead(b, majorUint, uint64(v))
+	case uint64:
+		return appendHead(b, majorUint, v)
+	case float64:
+		var buf [8]byte
+
+		binary.BigEndian.PutUint64(buf[:], math.Float64bits(v))
+
+		return append(append(b, majorSimple<<majorShift|simpleFloat64), buf[:]...)
+	case string:
+		return append(appendHead(b, majorText, uint64(len(v))), v...)
+	case []byte:
+		return append(appendHead(b, majorBytes, uint64(len(v))), v...)
+	case []interface{}:
+		b = appendHead(b, majorArray, uint64(len(v)))
+		for _, e := range v {
+			b = appendValue(b, e)
+		}
+
+		return b
+	case map[string]interface{}:
+		keys := docmap.SortedKeys(v)
+
+		// Deterministic encoding sorts the keys by their encoded form:
+		// shorter text strings come first.
+		sort.SliceStable(keys, func(i, j int) bool { return len(keys[i]) < len(keys[j]) })
+
+		b = appendHead(b, majorMap, uint64(len(v)))
+		for _, k := range keys {
+			b = appendValue(b, k)
+			b = appendValue(b, v[k])
+		}
+
+		return b
+	default:
+		return appendValue(b, docmap.Normalize(v))
+	}
+}
+
+// appendHead appends the initial byte and argument of a data item, in their
+// shortest form.
+func appendHead(b []byte, major byte, arg uint64) []byte {
+	m := major << majorShift
+
+	switch {
+	case arg <= infoDirect:
+		return append(b, m|byte(arg))
+	case arg <= math.MaxUint8:
+		return append(b, m|infoUint8, byte(arg))
+	case arg <= math.MaxUint16:
+		var buf [2]byte
+
+		binary.BigEndian.PutUint16(buf[:], uint16(arg))
+
+		return append(append(b, m|infoUint16), buf[:]...)
+	case arg <= math.MaxUint32:
+		var buf [4]byte
+
+		binary.BigEndian.PutUint32(buf[:], uint32(arg))
+
+		return append(append(b, m|infoUint32), buf[:]...)
+	default:
+		var buf [8]byte
+
+		binary.BigEndian.PutUint64(buf[:], arg)
+
+		return append(append(b, m|infoUint64), buf[:]...)
+	}
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+
+type decoder struct {
+	data []byte
+}
+
+func (d *decoder) next(n uint64) ([]byte, error) {
+	if n > uint64(len(d.data)) {
+		return nil, errors.Wrap(ErrInvalid, "unexpected end of data")
+	}
+
+	b := d.data[:n]
+	d.data = d.data[n:]
+
+	return b, nil
+}
+
+// head decodes the initial byte and argument of a data item.
+// indefinite is set for the indefinite-length encoding.
+func (d *decoder) head() (major, info byte, arg uint64, indefinite bool, err error) {
+	b, err := d.next(1)
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+
+	major, info = b[0]>>majorShift, b[0]&infoMask
+
+	switch {
+	case info <= infoDirect:
+		return major, info, uint64(info), false, nil
+	case info == infoIndefinite:
+		return major, info, 0, true, nil
+	}
+
+	size, ok := sizes[info]
+	if !ok {
+		return 0, 0, 0, false, errors.Wrapf(ErrInvalid, "reserved additional information %d", info)
+	}
+
+	b, err = d.next(uint64(size))
+	if err != nil {
+		return 0, 0, 0, false, err
+	}
+
+	for _, c := range b {
+		arg = arg<<8 | uint64(c)
+	}
+
+	return major, info, arg, false, nil
+}
+
+func (d *decoder) atBreak() bool {
+	if len(d.data) > 0 && d.data[0] == breakCode {
+		d.data = d.data[1:]
+		return true
+	}
+
+	return false
+}
+
+//nolint:gocyclo,funlen // one case per major type.
+func (d *decoder) value() (interface{}, error) {
+	major, info, arg, indefinite, err := d.head()
+	if err != nil {
+		return nil, err
+	}
+
+	switch major {
+	case majorUint:
+		if arg <= math.MaxInt64 {
+			return int64(arg), nil
+		}
+
+		return arg, nil
+	case majorNegInt:
+		if arg > math.MaxInt64 {
+			return nil, errors.Wrap(ErrInvalid, "negative integer overflow")
+		}
+
+		return -1 - int64(arg), nil
+	case majorBytes, majorText:
+		b, err := d.str(major, arg, indefinite)
+		if major == majorText {
+			return string(b), err
+		}
+
+		return b, err
+	case majorArray:
+		list := make([]interface{}, 0)
+
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.atBreak() {
+				break
+			}
+
+			v, err := d.value()
+			if err != nil {
+				return nil, err
+			}
+
+			list = append(list, v)
+		}
+
+		return list, nil
+	case majorMap:
+		m := make(map[string]interface{})
+
+		for i := uint64(0); indefinite || i < arg; i++ {
+			if indefinite && d.atBreak() {
+				break
+			}
+
+			k, err := d.value()
+			if err != nil {
+				return nil, err
+			}
+
+			key, ok := k.(string)
+			if !ok {
+				return nil, errors.Wrapf(ErrInvalid, "map key is a %T", k)
+			}
+
+			if m[key], err = d.value(); err != nil {
+				return nil, err
+			}
+		}
+
+		return m, nil
+	case majorTag:
+		// Tags only give a hint on the interpretation of their content.
+		return d.value()
+	default:
+		return simple(info, arg)
+	}
+}
+
+// str decodes the content of a byte or text string.
+func (d *decoder) str(major byte, arg uint64, indefinite bool) ([]byte, error) {
+	if !indefinite {
+		b, err := d.next(arg)
+
+		return append([]byte(nil), b...), err
+	}
+
+	out := make([]byte, 0)
+
+	for !d.atBreak() {
+		m, _, n, ind, err := d.head()
+		if err != nil {
+			return nil, err
+		}
+
+		if m != major || ind {
+			return nil, errors.Wrap(ErrInvalid, "invalid string chunk")
+		}
+
+		b, err := d.next(n)
+		if err != nil {
+			return nil, err
+		}
+
+		out = append(out, b...)
+	}
+
+	return out, nil
+}
+
+func simple(info byte, arg uint64) (interface{}, error) {
+	switch info {
+	case simpleFalse:
+		return false, nil
+	case simpleTrue:
+		return true, nil
+	case simpleNull, simpleUndef:
+		return nil, nil
+	case simpleFloat16:
+		return float16(uint16(arg)), nil
+	case simpleFloat32:
+		return float64(math.Float32frombits(uint32(arg))), nil
+	case simpleFloat64:
+		return math.Float64frombits(arg), nil
+	default:
+		return nil, errors.Wrapf(ErrInvalid, "unsupported simple value %d", arg)
+	}
+}
+
+// float16 decodes an IEEE 754 half-precision number.
+func float16(h uint16) float64 {
+	const (
+		expBits  = 5
+		mantBits = 10
+		expMask  = 1<<expBits - 1
+		mantMask = 1<<mantBits - 1
+		bias     = 15
+	)
+
+	exp := int(h>>mantBits) & expMask
+	mant := float64(h & mantMask)
+
+	var v float64
+
+	switch exp {
+	case 0:
+		v = math.Ldexp(mant, 1-bias-mantBits)
+	case expMask:
+		v = math.Inf(1)
+		if mant != 0 {
+			v = math.NaN()
+		}
+	default:
+		v = math.Ldexp(mant+1<<mantBits, exp-bias-mantBits)
+	}
+
+	if h>>(expBits+mantBits) != 0 {
+		return -v
+	}
+
+	return v
+}
@@ -0,0 +1,139 @@
+package cborx
+
+import (
+	"encoding/hex"
+	"math"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/hexbee-net/errors/internal/nostack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTrip(t *testing.T) {
+	err := errors.WithCode(
+		errors.WithKind(
+			errors.WithFields(errors.Wrap(errors.New("boom"), "read error"), errors.Fields{
+				"file":   "data.txt",
+				"size":   42,
+				"offset": -1000,
+				"ratio":  0.5,
+				"raw":    []byte{1, 2},
+				"nested": map[string]interface{}{"ok": true, "none": nil, "tags": []string{"a"}},
+			}),
+			errors.KindDataLoss,
+		),
+		"file.truncated",
+	)
+
+	data, e := Encode(err)
+	assert.NoError(t, e)
+
+	got := Decode(data)
+
+	assert.IsType(t, &errors.RemoteError{}, got)
+	assert.Equal(t, "read error: boom", got.Error())
+	assert.Equal(t, errors.KindDataLoss, errors.GetKind(got))
+	assert.Equal(t, "file.truncated", errors.GetCode(got))
+	assert.Equal(t, errors.Fields{
+		"file":   "data.txt",
+		"size":   int64(42),
+		"offset": int64(-1000),
+		"ratio":  0.5,
+		"raw":    []byte{1, 2},
+		"nested": map[string]interface{}{"ok": true, "none": nil, "tags": []interface{}{"a"}},
+	}, errors.GetFields(got))
+
+	if stack := got.(*errors.RemoteError).Stack(); !nostack.Enabled && assert.NotEmpty(t, stack) {
+		assert.Equal(t, "github.com/hexbee-net/errors/cborx.TestRoundTrip", stack[0].Function)
+	}
+
+	again, e := Encode(got)
+	assert.NoError(t, e)
+	assert.Equal(t, data, again)
+}
+
+func TestDecodeNil(t *testing.T) {
+	data, err := Encode(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, Decode(data))
+}
+
+// Examples from RFC 8949 appendix A.
+func TestRFCExamples(t *testing.T) {
+	tests := []struct {
+		hex    string
+		want   interface{}
+		encode bool
+	}{
+		{"00", int64(0), true},
+		{"1818", int64(24), true},
+		{"1903e8", int64(1000), true},
+		{"1bffffffffffffffff", uint64(math.MaxUint64), true},
+		{"20", int64(-1), true},
+		{"3903e7", int64(-1000), true},
+		{"fb3ff199999999999a", 1.1, true},
+		{"f93c00", 1.0, false},
+		{"f97bff", 65504.0, false},
+		{"f90001", 5.960464477539063e-08, false},
+		{"fa47c35000", 100000.0, false},
+		{"f4", false, true},
+		{"f5", true, true},
+		{"f6", nil, true},
+		{"c11a514b67b0", int64(1363896240), false},
+		{"4401020304", []byte{1, 2, 3, 4}, true},
+		{"6449455446", "IETF", true},
+		{"80", []interface{}{}, true},
+		{"83010203", []interface{}{int64(1), int64(2), int64(3)}, true},
+		{"a26161016162820203", map[string]interface{}{"a": int64(1), "b": []interface{}{int64(2), int64(3)}}, true},
+		{"9f018202039f0405ffff", []interface{}{
+			int64(1), []interface{}{int64(2), int64(3)}, []interface{}{int64(4), int64(5)},
+		}, false},
+		{"bf61610161629f0203ffff", map[string]interface{}{"a": int64(1), "b": []interface{}{int64(2), int64(3)}}, false},
+		{"7f657374726561646d696e67ff", "streaming", false},
+	}
+
+	for _, tt := range tests {
+		data, err := hex.DecodeString(tt.hex)
+		assert.NoError(t, err)
+
+		d := &decoder{data: data}
+		got, err := d.value()
+		assert.NoError(t, err, tt.hex)
+		assert.Equal(t, tt.want, got, tt.hex)
+		assert.Empty(t, d.data, tt.hex)
+
+		if tt.encode {
+			assert.Equal(t, tt.hex, hex.EncodeToString(appendValue(nil, tt.want)))
+		}
+	}
+}
+
+func TestDeterministicKeyOrder(t *testing.T) {
+	got := appendValue(nil, map[string]interface{}{"bb": int64(1), "c": int64(2), "a": int64(3)})
+
+	assert.Equal(t, "a361610361630262626201", hex.EncodeToString(got))
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	tests := []string{
+		"",
+		"1c",
+		"19ff",
+		"6449",
+		"a10101",
+		"f8ff",
+		"7f01ff",
+		"0000",
+		"01",
+	}
+
+	for _, tt := range tests {
+		data, err := hex.DecodeString(tt)
+		assert.NoError(t, err)
+
+		err = Decode(data)
+		assert.Error(t, err, tt)
+		assert.Contains(t, err.Error(), "decoding remote error", tt)
+	}
+}
@@ -0,0 +1,87 @@
+package errors
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// ChainOption configures FormatChain.
+type ChainOption func(*chainOptions)
+
+type chainOptions struct {
+	fields bool
+	stack  bool
+}
+
+// ChainWithoutFields leaves the fields of the layers out of FormatChain.
+func ChainWithoutFields() ChainOption {
+	return func(o *chainOptions) { o.fields = false }
+}
+
+// ChainWithoutStack leaves the stack trace out of FormatChain.
+func ChainWithoutStack() ChainOption {
+	return func(o *chainOptions) { o.stack = false }
+}
+
+// FormatChain writes a human-readable rendering of err to w, a middle ground
+// between the %s and %+v verbs: the message of every layer on its own line,
+// each cause indented under the layer it caused and introduced by "because:",
+// with the fields of the layer below its message, then the stack trace
+// recorded closest to the origin of the chain:
+//
+//     loading config
+//       path: /etc/app.yaml
+//       because: EOF
+//
+//     main.load
+//     	/src/main.go:12
+//
+// The frames are filtered and printed like by the %+v verb.
+// If err is nil, nothing is written.
+func FormatChain(w io.Writer, err error, opts ...ChainOption) error {
+	if err == nil {
+		return nil
+	}
+
+	o := chainOptions{fields: true, stack: true}
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	var b bytes.Buffer
+
+	limits := GetFieldLimits()
+
+	for i, layer := range GetFieldsByLayer(err) {
+		indent := strings.Repeat("  ", i)
+
+		if i == 0 {
+			b.WriteString(layer.Message)
+		} else {
+			b.WriteString("\n" + indent + "because: " + layer.Message)
+		}
+
+		if !o.fields {
+			continue
+		}
+
+		for _, k := range layer.Fields.keys() {
+			b.WriteString("\n" + indent + "  " + k + ": " + FormatField(k, limits.Limit(k, layer.Fields[k])))
+		}
+	}
+
+	if st, ok := StackOf(err); ok && o.stack {
+		b.WriteString("\n")
+
+		for _, f := range st.Visible() {
+			formatFrame(&b, f)
+		}
+	}
+
+	b.WriteString("\n")
+
+	_, werr := w.Write(b.Bytes())
+
+	return werr
+}
@@ -0,0 +1,34 @@
+package errors
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatChain(t *testing.T) {
+	err := WithFields(Wrap(WithFields(io.EOF, Fields{"offset": 12}), "reading"), Fields{"path": "a.txt"})
+
+	var b strings.Builder
+
+	assert.NoError(t, FormatChain(&b, err, ChainWithoutStack()))
+	assert.Equal(t, "reading\n  path: a.txt\n  because: EOF\n    offset: 12\n", b.String())
+
+	b.Reset()
+	assert.NoError(t, FormatChain(&b, err, ChainWithoutStack(), ChainWithoutFields()))
+	assert.Equal(t, "reading\n  because: EOF\n", b.String())
+
+	b.Reset()
+	assert.NoError(t, FormatChain(&b, err))
+
+	if stacksCompiled {
+		assert.True(t, strings.HasPrefix(b.String(),
+			"reading\n  path: a.txt\n  because: EOF\n    offset: 12\n\ngithub.com/hexbee-net/errors.TestFormatChain\n\t"), b.String())
+	}
+
+	b.Reset()
+	assert.NoError(t, FormatChain(&b, nil))
+	assert.Empty(t, b.String())
+}
@@ -0,0 +1,54 @@
+package errors
+
+import "context"
+
+// ChanReceivedField is the field annotating the errors returned by FromChan
+// with the number of values received from the channel, nil ones included.
+const ChanReceivedField = "chan.received"
+
+// FromChan receives the errors sent to ch until it is closed or ctx is done,
+// and returns a MultiError holding the non-nil ones, for fan-in patterns:
+//
+//     errs := make(chan error)
+//
+//     for _, job := range jobs {
+//            go func(job Job) { errs <- job.Run() }(job)
+//     }
+//
+//     go func() { wg.Wait(); close(errs) }()
+//
+//     return errors.FromChan(ctx, errs)
+//
+// The MultiError is annotated with the number of values received as
+// ChanReceivedField. If ctx is done before ch is closed, the error given by
+// FromContext is added last.
+// If no error was received and ctx is not done, FromChan returns nil.
+func FromChan(ctx context.Context, ch <-chan error) error {
+	var (
+		members  []error
+		received int
+	)
+
+	for done := false; !done; {
+		select {
+		case <-ctx.Done():
+			members = append(members, FromContext(ctx))
+			done = true
+		case err, ok := <-ch:
+			if !ok {
+				done = true
+
+				break
+			}
+
+			received++
+			members = appendFlat(members, err)
+		}
+	}
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	return WithFields(newMulti(members, callers()), Fields{ChanReceivedField: received})
+}
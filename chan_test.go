@@ -0,0 +1,49 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromChan(t *testing.T) {
+	ch := make(chan error, 3)
+	ch <- io.EOF
+	ch <- nil
+	ch <- io.ErrUnexpectedEOF
+	close(ch)
+
+	err := FromChan(context.Background(), ch)
+
+	var m *MultiError
+	if assert.True(t, errors.As(err, &m)) {
+		assert.Equal(t, []error{io.EOF, io.ErrUnexpectedEOF}, m.Errors())
+	}
+
+	assert.Equal(t, 3, GetFields(err)[ChanReceivedField])
+	assert.True(t, errors.Is(err, io.ErrUnexpectedEOF))
+
+	_, hasStack := StackOf(err)
+	assert.Equal(t, stacksCompiled, hasStack)
+}
+
+func TestFromChanNoError(t *testing.T) {
+	ch := make(chan error, 1)
+	ch <- nil
+	close(ch)
+
+	assert.Nil(t, FromChan(context.Background(), ch))
+}
+
+func TestFromChanContextDone(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := FromChan(ctx, make(chan error))
+
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, 0, GetFields(err)[ChanReceivedField])
+}
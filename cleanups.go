@@ -0,0 +1,63 @@
+package errors
+
+// CleanupField is the field annotating the errors of the steps run by
+// Cleanups with the name of the step.
+const CleanupField = "cleanup"
+
+// Cleanups are the teardown steps of the resources acquired so far, such as
+// on the path setting up a service or a test:
+//
+//     var c errors.Cleanups
+//
+//     db, err := openDB()
+//     if err != nil {
+//            return err
+//     }
+//
+//     c.Add("closing database", db.Close)
+//
+//     ...
+//
+//     return c.Run()
+//
+// The zero Cleanups is ready to use.
+// Cleanups are not safe for concurrent use.
+type Cleanups struct {
+	steps []cleanupStep
+}
+
+type cleanupStep struct {
+	name string
+	fn   func() error
+}
+
+// Add registers the step fn named name.
+func (c *Cleanups) Add(name string, fn func() error) {
+	c.steps = append(c.steps, cleanupStep{name: name, fn: fn})
+}
+
+// Run runs the steps in the reverse order they were added, then forgets them,
+// and returns a MultiError holding the errors of the steps that failed, or
+// the ones describing their panics, annotated with their name as
+// CleanupField.
+// Every step is run, whatever the failures of the others.
+// If no step failed, Run returns nil.
+func (c *Cleanups) Run() error {
+	var errs []error
+
+	for i := len(c.steps) - 1; i >= 0; i-- {
+		step := c.steps[i]
+
+		if err := runTask(step.fn); err != nil {
+			errs = append(errs, WithField(err, CleanupField, step.name))
+		}
+	}
+
+	c.steps = nil
+
+	if len(errs) == 0 {
+		return nil
+	}
+
+	return newMulti(errs, callers())
+}
@@ -0,0 +1,48 @@
+package errors
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCleanups(t *testing.T) {
+	var (
+		c   Cleanups
+		ran []string
+	)
+
+	assert.Nil(t, c.Run())
+
+	step := func(name string, err error) {
+		c.Add(name, func() error {
+			ran = append(ran, name)
+
+			return err
+		})
+	}
+
+	step("closing file", io.ErrShortWrite)
+	step("removing directory", nil)
+	c.Add("stopping server", func() error { panic("boom") })
+	step("closing database", io.EOF)
+
+	err := c.Run()
+
+	assert.Equal(t, []string{"closing database", "removing directory", "closing file"}, ran)
+
+	m, ok := err.(*MultiError)
+	if assert.True(t, ok) && assert.Len(t, m.Errors(), 3) {
+		names := make([]interface{}, 0, len(m.Errors()))
+		for _, e := range m.Errors() {
+			names = append(names, GetFields(e)[CleanupField])
+		}
+
+		assert.Equal(t, []interface{}{"closing database", "stopping server", "closing file"}, names)
+		assert.Equal(t, io.EOF, Cause(m.Errors()[0]))
+		assert.Equal(t, "panic: boom", m.Errors()[1].Error())
+	}
+
+	assert.Nil(t, c.Run(), "the steps are forgotten")
+}
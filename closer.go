@@ -0,0 +1,44 @@
+package errors
+
+import "io"
+
+// CloseErrorField is the field holding the error of a Close call that failed
+// after the function deferring CloseAndAppend had already failed.
+const CloseErrorField = "close.error"
+
+// CloseAndAppend closes c and reports its failure through the error returned
+// by the function deferring it, so that a failed Close, such as one flushing
+// the end of a written file, is not silently lost:
+//
+//     func save(path string, data []byte) (err error) {
+//            f, err := os.Create(path)
+//            if err != nil {
+//                   return errors.Wrap(err, "creating file")
+//            }
+//
+//            defer errors.CloseAndAppend(f, &err, "closing file")
+//
+//            ...
+//     }
+//
+// The error of Close is wrapped with a stack trace and msg. It becomes the
+// returned error if there was none, and is otherwise added to the returned
+// error as CloseErrorField, the returned error staying the primary failure.
+// The values of this field over the chain are given by GetAllFields when
+// several closes failed.
+func CloseAndAppend(c io.Closer, errp *error, msg string) {
+	cerr := c.Close()
+	if cerr == nil {
+		return
+	}
+
+	w := wrapStack(cerr, msg, callers())
+
+	if *errp == nil {
+		*errp = w
+
+		return
+	}
+
+	*errp = WithField(*errp, CloseErrorField, w)
+}
@@ -0,0 +1,42 @@
+package errors
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// closer is an io.Closer returning err.
+type closer struct {
+	err error
+}
+
+func (c closer) Close() error { return c.err }
+
+func closing(c io.Closer, err error) (ret error) {
+	defer CloseAndAppend(c, &ret, "closing file")
+
+	return err
+}
+
+func TestCloseAndAppend(t *testing.T) {
+	assert.Nil(t, closing(closer{}, nil))
+	assert.Equal(t, io.EOF, closing(closer{}, io.EOF))
+
+	err := closing(closer{io.ErrShortWrite}, nil)
+	assert.Equal(t, "closing file: short write", err.Error())
+
+	st := err.(stackTracer).StackTrace()
+	if stacksCompiled && assert.NotEmpty(t, st) {
+		assert.Equal(t, "github.com/hexbee-net/errors.closing", Frame(st[0]).Function())
+	}
+
+	err = closing(closer{io.ErrShortWrite}, io.EOF)
+	assert.Equal(t, io.EOF, Cause(err))
+
+	secondary, ok := GetFields(err)[CloseErrorField].(error)
+	if assert.True(t, ok) {
+		assert.Equal(t, "closing file: short write", secondary.Error())
+	}
+}
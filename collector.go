@@ -0,0 +1,82 @@
+package errors
+
+import "sync"
+
+// Collector accumulates the errors reported by many goroutines, such as the
+// workers of a fan-out that must report all their failures:
+//
+//     var c errors.Collector
+//
+//     for i, job := range jobs {
+//            wg.Add(1)
+//
+//            go func(i int, job Job) {
+//                   defer wg.Done()
+//
+//                   c.AddWithFields(job.Run(), errors.Fields{"worker": i})
+//            }(i, job)
+//     }
+//
+//     wg.Wait()
+//
+//     return c.Err()
+//
+// The zero Collector is ready to use.
+type Collector struct {
+	mu   sync.Mutex
+	errs []error
+}
+
+// NewCollector returns an empty Collector.
+func NewCollector() *Collector {
+	return &Collector{}
+}
+
+// Add adds err to the collected errors.
+// If err is nil, Add does nothing.
+func (c *Collector) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.errs = append(c.errs, err)
+}
+
+// AddWithFields adds err annotated with fields to the collected errors, such
+// as the index of the worker that failed.
+// If err is nil, AddWithFields does nothing.
+func (c *Collector) AddWithFields(err error, fields Fields) {
+	c.Add(WithFields(err, fields))
+}
+
+// Len returns the number of collected errors.
+func (c *Collector) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return len(c.errs)
+}
+
+// Err returns a MultiError holding the collected errors, in the order they
+// were added and flattened like by Append, along with the stack trace of the
+// call to Err.
+// If no error was collected, Err returns nil.
+func (c *Collector) Err() error {
+	c.mu.Lock()
+
+	members := make([]error, 0, len(c.errs))
+	for _, err := range c.errs {
+		members = appendFlat(members, err)
+	}
+
+	c.mu.Unlock()
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	return newMulti(members, callers())
+}
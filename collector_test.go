@@ -0,0 +1,60 @@
+package errors
+
+import (
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollector(t *testing.T) {
+	var c Collector
+
+	assert.Nil(t, c.Err())
+
+	var wg sync.WaitGroup
+
+	const workers = 8
+
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+
+		go func(i int) {
+			defer wg.Done()
+
+			c.Add(nil)
+			c.AddWithFields(io.EOF, Fields{"worker": i})
+		}(i)
+	}
+
+	wg.Wait()
+
+	assert.Equal(t, workers, c.Len())
+
+	err := c.Err()
+	m, ok := err.(*MultiError)
+
+	if assert.True(t, ok) {
+		assert.Len(t, m.Errors(), workers)
+
+		seen := make(map[interface{}]bool)
+		for _, e := range m.Errors() {
+			seen[GetFields(e)["worker"]] = true
+		}
+
+		assert.Len(t, seen, workers)
+	}
+
+	_, hasStack := StackOf(err)
+	assert.Equal(t, stacksCompiled, hasStack)
+}
+
+func TestCollectorFlattens(t *testing.T) {
+	c := NewCollector()
+
+	c.Add(Append(nil, io.EOF, io.ErrUnexpectedEOF))
+	c.Add(io.ErrClosedPipe)
+
+	assert.Equal(t, []error{io.EOF, io.ErrUnexpectedEOF, io.ErrClosedPipe}, c.Err().(*MultiError).Errors())
+}
@@ -0,0 +1,207 @@
+package errors
+
+import (
+	"context"
+	stderrors "errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Fields added by WrapContext.
+const (
+	ContextDeadlineField = "context.deadline"
+	ContextElapsedField  = "context.elapsed"
+)
+
+// WrapContext returns an error annotating err, returned by an operation
+// running under ctx, with a stack trace at the point WrapContext is called
+// and KindCanceled or KindDeadlineExceeded, depending on whether err or ctx
+// reports a cancellation or an expired deadline.
+// When ctx has a deadline, it is recorded as a time.Time field, along with
+// the time.Duration elapsed since the deadline once it has passed.
+// The fields describing ctx are recorded as well.
+// If neither err nor ctx report the end of the context, err is returned as is.
+// If err is nil, WrapContext returns nil.
+func WrapContext(ctx context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var kind Kind
+
+	switch {
+	case stderrors.Is(err, context.DeadlineExceeded):
+		kind = KindDeadlineExceeded
+	case stderrors.Is(err, context.Canceled):
+		kind = KindCanceled
+	case ctx.Err() == context.DeadlineExceeded:
+		kind = KindDeadlineExceeded
+	case ctx.Err() != nil:
+		kind = KindCanceled
+	default:
+		return err
+	}
+
+	w := withContextFields(ctx, withStackOn(err, err, callers()))
+
+	if deadline, ok := ctx.Deadline(); ok {
+		fields := Fields{ContextDeadlineField: deadline}
+
+		if elapsed := time.Since(deadline); elapsed >= 0 {
+			fields[ContextElapsedField] = elapsed
+		}
+
+		w = runHooks(&withFields{
+			w,
+			newFieldSet(fields),
+		}, w)
+	}
+
+	return runHooks(&withKind{
+		cause: w,
+		kind:  kind,
+	}, w)
+}
+
+// IsCanceled reports whether err is, or wraps, context.Canceled or has
+// KindCanceled.
+func IsCanceled(err error) bool {
+	return stderrors.Is(err, context.Canceled) || GetKind(err) == KindCanceled
+}
+
+// IsDeadlineExceeded reports whether err is, or wraps,
+// context.DeadlineExceeded or has KindDeadlineExceeded.
+func IsDeadlineExceeded(err error) bool {
+	return stderrors.Is(err, context.DeadlineExceeded) || GetKind(err) == KindDeadlineExceeded
+}
+
+type contextFieldsKey struct{}
+
+// WithContextFields returns a copy of ctx carrying fields, such as the ID of
+// the request being served or of its user, merged with the fields already
+// stashed on ctx.
+// The errors created by NewCtx, WrapCtx, WrapContext and FromContext are
+// annotated with these fields.
+func WithContextFields(ctx context.Context, fields Fields) context.Context {
+	parent, _ := ctx.Value(contextFieldsKey{}).(Fields)
+	merged := make(Fields, len(parent)+len(fields))
+
+	for k, v := range parent {
+		merged[k] = v
+	}
+
+	for k, v := range fields {
+		merged[k] = v
+	}
+
+	return context.WithValue(ctx, contextFieldsKey{}, merged)
+}
+
+// FieldsFromContext returns the fields stashed on ctx by WithContextFields.
+// If ctx carries no fields, an empty map will be returned.
+func FieldsFromContext(ctx context.Context) Fields {
+	fields, _ := ctx.Value(contextFieldsKey{}).(Fields)
+	out := make(Fields, len(fields))
+
+	for k, v := range fields {
+		out[k] = v
+	}
+
+	return out
+}
+
+// NewCtx returns an error with the supplied message, annotated with the
+// fields stashed on ctx.
+// NewCtx also records the stack trace at the point it was called.
+func NewCtx(ctx context.Context, message string) error {
+	err := runHooks(&fundamental{
+		msg:   message,
+		stack: callers(),
+	}, nil)
+
+	publishCreated(err, nil)
+
+	return withContextFields(ctx, err)
+}
+
+// WrapCtx returns an error annotating err with a stack trace at the point
+// WrapCtx is called, the supplied message, and the fields stashed on ctx.
+// If err is nil, WrapCtx returns nil.
+func WrapCtx(ctx context.Context, err error, message string) error {
+	if err == nil {
+		return nil
+	}
+
+	w := wrapStack(err, message, callers())
+
+	return withContextFields(ctx, w)
+}
+
+// ContextExtractor returns the fields describing ctx, such as the ID of the
+// trace it belongs to, the authenticated subject or the locale.
+type ContextExtractor func(ctx context.Context) Fields
+
+//nolint:gochecknoglobals // extractors are registered process-wide.
+var (
+	extractorsMu sync.Mutex
+	extractors   atomic.Value // []ContextExtractor
+)
+
+// RegisterContextExtractor adds e to the extractors consulted by NewCtx,
+// WrapCtx, WrapContext and FromContext.
+// The fields of the extractors are merged in registration order, and the
+// fields stashed on the context with WithContextFields override them.
+// Extractors may be called from many goroutines at once, so they must be
+// cheap and safe for concurrent use.
+func RegisterContextExtractor(e ContextExtractor) {
+	if e == nil {
+		return
+	}
+
+	extractorsMu.Lock()
+	defer extractorsMu.Unlock()
+
+	current, _ := extractors.Load().([]ContextExtractor)
+
+	registered := make([]ContextExtractor, len(current), len(current)+1)
+	copy(registered, current)
+
+	extractors.Store(append(registered, e))
+}
+
+// contextFields returns the fields of the registered extractors merged with
+// the fields stashed on ctx.
+func contextFields(ctx context.Context) Fields {
+	registered, _ := extractors.Load().([]ContextExtractor)
+	if len(registered) == 0 {
+		return FieldsFromContext(ctx)
+	}
+
+	fields := make(Fields)
+
+	for _, e := range registered {
+		for k, v := range e(ctx) {
+			fields[k] = v
+		}
+	}
+
+	for k, v := range FieldsFromContext(ctx) {
+		fields[k] = v
+	}
+
+	return fields
+}
+
+// withContextFields annotates err with the fields describing ctx, if any.
+func withContextFields(ctx context.Context, err error) error {
+	fields := contextFields(ctx)
+	if len(fields) == 0 {
+		return err
+	}
+
+	return runHooks(&withFields{
+		err,
+		newFieldSet(fields),
+	}, err)
+}
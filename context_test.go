@@ -0,0 +1,163 @@
+package errors
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapContext(t *testing.T) {
+	assert.Nil(t, WrapContext(context.Background(), nil))
+	assert.Equal(t, io.EOF, WrapContext(context.Background(), io.EOF))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := WrapContext(ctx, io.EOF)
+
+	assert.Equal(t, "EOF", err.Error())
+	assert.Equal(t, KindCanceled, GetKind(err))
+	assert.True(t, IsCanceled(err))
+	assert.False(t, IsDeadlineExceeded(err))
+	assert.Empty(t, GetFields(err))
+
+	if stacksCompiled {
+		assert.Contains(t, fmt.Sprintf("%+v", err), "errors.TestWrapContext\n")
+	}
+
+	deadline := time.Now().Add(-time.Second)
+	ctx, cancel = context.WithDeadline(context.Background(), deadline)
+
+	defer cancel()
+
+	err = WrapContext(ctx, Wrap(ctx.Err(), "querying"))
+
+	assert.Equal(t, KindDeadlineExceeded, GetKind(err))
+	assert.True(t, IsDeadlineExceeded(err))
+	assert.False(t, IsCanceled(err))
+	assert.Equal(t, deadline, GetFields(err)[ContextDeadlineField])
+
+	elapsed, ok := GetFields(err)[ContextElapsedField].(time.Duration)
+	assert.True(t, ok)
+	assert.True(t, elapsed >= time.Second, elapsed)
+}
+
+func TestWrapContextBeforeDeadline(t *testing.T) {
+	deadline := time.Now().Add(time.Hour)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+
+	cancel()
+
+	err := WrapContext(ctx, context.Canceled)
+
+	assert.Equal(t, KindCanceled, GetKind(err))
+	assert.Equal(t, Fields{ContextDeadlineField: deadline}, GetFields(err))
+}
+
+func TestIsCanceled(t *testing.T) {
+	assert.False(t, IsCanceled(nil))
+	assert.True(t, IsCanceled(Wrap(context.Canceled, "reading")))
+	assert.True(t, IsCanceled(WithKind(io.EOF, KindCanceled)))
+	assert.False(t, IsCanceled(context.DeadlineExceeded))
+}
+
+func TestIsDeadlineExceeded(t *testing.T) {
+	assert.False(t, IsDeadlineExceeded(nil))
+	assert.True(t, IsDeadlineExceeded(Wrap(context.DeadlineExceeded, "reading")))
+	assert.True(t, IsDeadlineExceeded(WithKind(io.EOF, KindDeadlineExceeded)))
+	assert.False(t, IsDeadlineExceeded(io.EOF))
+}
+
+func TestContextFields(t *testing.T) {
+	ctx := context.Background()
+
+	assert.Equal(t, Fields{}, FieldsFromContext(ctx))
+
+	ctx = WithContextFields(ctx, Fields{"request_id": "r1", "user_id": "u1"})
+	child := WithContextFields(ctx, Fields{"user_id": "u2", "tenant": "t1"})
+
+	assert.Equal(t, Fields{"request_id": "r1", "user_id": "u1"}, FieldsFromContext(ctx))
+	assert.Equal(t, Fields{"request_id": "r1", "user_id": "u2", "tenant": "t1"}, FieldsFromContext(child))
+
+	FieldsFromContext(ctx)["request_id"] = "changed"
+	assert.Equal(t, "r1", FieldsFromContext(ctx)["request_id"])
+}
+
+func TestNewCtx(t *testing.T) {
+	ctx := WithContextFields(context.Background(), Fields{"request_id": "r1"})
+
+	err := NewCtx(ctx, "boom")
+
+	assert.Equal(t, "boom", err.Error())
+	assert.Equal(t, Fields{"request_id": "r1"}, GetFields(err))
+
+	if stacksCompiled {
+		assert.Contains(t, fmt.Sprintf("%+v", err), "errors.TestNewCtx\n")
+	}
+
+	assert.Empty(t, GetFields(NewCtx(context.Background(), "boom")))
+}
+
+func TestWrapCtx(t *testing.T) {
+	ctx := WithContextFields(context.Background(), Fields{"request_id": "r1"})
+
+	assert.Nil(t, WrapCtx(ctx, nil, "reading"))
+
+	err := WrapCtx(ctx, WithField(io.EOF, "request_id", "inner"), "reading")
+
+	assert.Equal(t, "reading: EOF", err.Error())
+	assert.Equal(t, Fields{"request_id": "inner"}, GetFields(err))
+
+	if stacksCompiled {
+		assert.Contains(t, fmt.Sprintf("%+v", err), "errors.TestWrapCtx\n")
+	}
+}
+
+func withExtractors(t *testing.T, es ...ContextExtractor) {
+	t.Helper()
+
+	extractors.Store([]ContextExtractor(nil))
+
+	for _, e := range es {
+		RegisterContextExtractor(e)
+	}
+
+	t.Cleanup(func() { extractors.Store([]ContextExtractor(nil)) })
+}
+
+type localeKey struct{}
+
+func TestRegisterContextExtractor(t *testing.T) {
+	withExtractors(t,
+		func(ctx context.Context) Fields {
+			return Fields{"trace_id": "t1", "locale": "en"}
+		},
+		nil,
+		func(ctx context.Context) Fields {
+			if l, ok := ctx.Value(localeKey{}).(string); ok {
+				return Fields{"locale": l}
+			}
+
+			return nil
+		},
+	)
+
+	ctx := context.WithValue(context.Background(), localeKey{}, "fr")
+	ctx = WithContextFields(ctx, Fields{"trace_id": "stashed"})
+
+	want := Fields{"trace_id": "stashed", "locale": "fr"}
+
+	assert.Equal(t, want, GetFields(NewCtx(ctx, "boom")))
+	assert.Equal(t, want, GetFields(WrapCtx(ctx, io.EOF, "reading")))
+	assert.Equal(t, Fields{"trace_id": "t1", "locale": "en"}, GetFields(NewCtx(context.Background(), "boom")))
+
+	ctx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	assert.Equal(t, want, GetFields(WrapContext(ctx, io.EOF)))
+	assert.Equal(t, want, GetFields(FromContext(ctx)))
+}
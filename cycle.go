@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"fmt"
+	"math"
+	"sync/atomic"
+)
+
+// ErrCycle is returned by Cause, and given as the origin layer by Unpack, when
+// the chain of an error loops, that is when a Cause method returns the error
+// itself or one of the errors wrapping it, or is deeper than MaxChainDepth.
+const ErrCycle = Error("error chain loops")
+
+// DefaultMaxChainDepth is the number of layers after which the traversals of
+// a chain stop, unless set otherwise with SetMaxChainDepth.
+const DefaultMaxChainDepth = 1024
+
+// maxChainDepth is the number of layers after which the traversals of a chain
+// stop, the chain being considered to loop.
+// Counting the layers keeps the traversals free of allocations, unlike a set
+// of the visited errors, and still works for errors that are not comparable.
+//nolint:gochecknoglobals // the depth is configured process-wide.
+var maxChainDepth int32 = DefaultMaxChainDepth
+
+// SetMaxChainDepth sets the number of layers after which the traversals of a
+// chain stop, such as to keep the wrap loops of retry paths from flooding the
+// logs. The chains formatted with the %+v verb then end with a note telling
+// they were truncated.
+// If n is not positive, DefaultMaxChainDepth is restored.
+func SetMaxChainDepth(n int) {
+	if n <= 0 || n > math.MaxInt32 {
+		n = DefaultMaxChainDepth
+	}
+
+	atomic.StoreInt32(&maxChainDepth, int32(n))
+}
+
+// MaxChainDepth returns the number of layers after which the traversals of a
+// chain stop, as set by SetMaxChainDepth.
+func MaxChainDepth() int {
+	return int(atomic.LoadInt32(&maxChainDepth))
+}
+
+// HasCycle reports whether the chain of err loops, or is deeper than
+// MaxChainDepth.
+// The traversals of the package stop at the layer the loop is detected at,
+// instead of never returning.
+func HasCycle(err error) bool {
+	limit := MaxChainDepth()
+
+	for depth := 0; err != nil; depth++ {
+		if depth == limit {
+			return true
+		}
+
+		err = causeOf(err)
+	}
+
+	return false
+}
+
+// formatTruncated prints the note ending the chains formatted with the %+v
+// verb once they are deeper than MaxChainDepth.
+func formatTruncated(s verboseState) {
+	_, _ = fmt.Fprintf(s, "... chain truncated after %d layers", MaxChainDepth())
+}
@@ -0,0 +1,83 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// loopError is a malformed error whose cause can be set to an error wrapping
+// it.
+type loopError struct {
+	cause error
+}
+
+func (e *loopError) Error() string { return "loop" }
+
+func (e *loopError) Cause() error {
+	if e.cause == nil {
+		return e
+	}
+
+	return e.cause
+}
+
+func TestHasCycle(t *testing.T) {
+	assert.False(t, HasCycle(nil))
+	assert.False(t, HasCycle(io.EOF))
+	assert.False(t, HasCycle(Wrap(New("boom"), "wrapped")))
+	assert.True(t, HasCycle(&loopError{}))
+
+	l := &loopError{}
+	l.cause = WithFields(Wrap(l, "outer"), Fields{"k": "v"})
+
+	assert.True(t, HasCycle(l.cause))
+}
+
+func TestTraversalsStopOnCycles(t *testing.T) {
+	l := &loopError{}
+	err := WithKind(WithFields(Wrap(l, "outer"), Fields{"k": "v"}), KindInternal)
+	l.cause = err
+
+	assert.Equal(t, ErrCycle, Cause(err))
+	assert.Equal(t, Fields{"k": "v"}, GetFields(err))
+	assert.Equal(t, KindInternal, GetKind(err))
+	assert.Equal(t, "", GetCode(err))
+
+	_, ok := StackOf(err)
+	assert.Equal(t, stacksCompiled, ok)
+
+	layers := Unpack(err)
+	if assert.NotEmpty(t, layers) {
+		assert.Equal(t, ErrCycle, layers[0])
+	}
+
+	assert.Equal(t, []error{ErrCycle}, Unpack(&loopError{}))
+}
+
+func TestSetMaxChainDepth(t *testing.T) {
+	assert.Equal(t, DefaultMaxChainDepth, MaxChainDepth())
+
+	SetMaxChainDepth(3)
+	defer SetMaxChainDepth(0)
+
+	assert.Equal(t, 3, MaxChainDepth())
+
+	err := WithFields(Wrap(Wrap(New("a"), "b"), "c"), Fields{"k": 1})
+
+	assert.True(t, HasCycle(err))
+	assert.Equal(t, ErrCycle, Cause(err))
+	assert.Equal(t, []error{ErrCycle, err}, Unpack(err))
+
+	got := fmt.Sprintf("%+v", err)
+	assert.True(t, strings.HasPrefix(got, "... chain truncated after 3 layers\nc\n"), got)
+	assert.NotContains(t, got, "b\n")
+	assert.Contains(t, fmt.Sprintf("%#v", err), `&errors.withMessage{msg:"c", cause:...}`)
+
+	SetMaxChainDepth(-1)
+	assert.Equal(t, DefaultMaxChainDepth, MaxChainDepth())
+	assert.False(t, HasCycle(err))
+}
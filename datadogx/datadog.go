@@ -0,0 +1,120 @@
+// Package datadogx tags dd-trace-go spans with Datadog error tracking attributes.
+//
+// Spans are accepted through the Tagger interface, which ddtrace.Span
+// satisfies, so the package does not depend on the tracer:
+//
+//     span, ctx := tracer.StartSpanFromContext(ctx, "operation")
+//     defer span.Finish()
+//
+//     if err := run(ctx); err != nil {
+//         datadogx.SetError(span, err)
+//     }
+package datadogx
+
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+
+	"github.com/hexbee-net/errors"
+)
+
+// Tags set by SetError.
+const (
+	ErrorTag        = "error"
+	ErrorMessageTag = "error.message"
+	ErrorTypeTag    = "error.type"
+	ErrorStackTag   = "error.stack"
+	ErrorKindTag    = "error.kind"
+	ErrorCodeTag    = "error.code"
+
+	// FieldTagPrefix is prepended to the key of every field of the chain.
+	FieldTagPrefix = "error.fields."
+)
+
+// Tagger is the subset of ddtrace.Span used by SetError.
+type Tagger interface {
+	SetTag(key string, value interface{})
+}
+
+// SetError flags span as failed and tags it with the message, type and stack
+// of err, plus its kind, code and fields.
+// The type is the one of the root cause and the stack is the deepest one
+// recorded in the chain.
+// If err is nil, SetError does nothing.
+func SetError(span Tagger, err error) {
+	if err == nil {
+		return
+	}
+
+	for k, v := range Tags(err) {
+		span.SetTag(k, v)
+	}
+}
+
+// Tags returns the span tags describing err.
+// If err is nil, an empty map will be returned.
+func Tags(err error) map[string]interface{} {
+	tags := make(map[string]interface{})
+	if err == nil {
+		return tags
+	}
+
+	for k, v := range errors.GetFields(err) {
+		tags[FieldTagPrefix+k] = v
+	}
+
+	tags[ErrorTag] = true
+	tags[ErrorMessageTag] = err.Error()
+	tags[ErrorTypeTag] = fmt.Sprintf("%T", errors.Cause(err))
+
+	if st := Stack(err); st != "" {
+		tags[ErrorStackTag] = st
+	}
+
+	if kind := errors.GetKind(err); kind != errors.KindUnknown {
+		tags[ErrorKindTag] = kind.String()
+	}
+
+	if code := errors.GetCode(err); code != "" {
+		tags[ErrorCodeTag] = code
+	}
+
+	return tags
+}
+
+// Stack renders the deepest stack trace of the chain the way dd-trace-go
+// renders its own: one "function\n\tfile:line" entry per frame.
+// If the chain carries no stack trace, an empty string will be returned.
+func Stack(err error) string {
+	stack, ok := errors.StackOf(err)
+	if !ok {
+		return ""
+	}
+
+	pcs := stack.PCs()
+
+	var sb strings.Builder
+
+	frames := runtime.CallersFrames(pcs)
+
+	for i := 0; ; i++ {
+		f, more := frames.Next()
+		if i != 0 {
+			sb.WriteByte('\n')
+		}
+
+		sb.WriteString(f.Function)
+		sb.WriteString("\n\t")
+		sb.WriteString(f.File)
+		sb.WriteByte(':')
+		sb.WriteString(strconv.Itoa(f.Line))
+
+		if !more {
+			break
+		}
+	}
+
+	return sb.String()
+}
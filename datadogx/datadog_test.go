@@ -0,0 +1,60 @@
+package datadogx
+
+import (
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/hexbee-net/errors/internal/nostack"
+	"github.com/stretchr/testify/assert"
+)
+
+type span map[string]interface{}
+
+func (s span) SetTag(key string, value interface{}) { s[key] = value }
+
+func TestSetErrorNil(t *testing.T) {
+	s := span{}
+	SetError(s, nil)
+	assert.Empty(t, s)
+}
+
+func TestSetError(t *testing.T) {
+	err := errors.WithCode(
+		errors.WithKind(
+			errors.WithField(errors.Wrap(io.EOF, "read error"), "file", "data.txt"),
+			errors.KindDataLoss,
+		),
+		"file.truncated",
+	)
+
+	s := span{}
+	SetError(s, err)
+
+	stack, ok := s[ErrorStackTag].(string)
+	if !nostack.Enabled && assert.True(t, ok) {
+		assert.True(t, strings.HasPrefix(stack, "github.com/hexbee-net/errors/datadogx.TestSetError\n\t"))
+	}
+
+	delete(s, ErrorStackTag)
+	assert.Equal(t, span{
+		ErrorTag:                true,
+		ErrorMessageTag:         "read error: EOF",
+		ErrorTypeTag:            "*errors.errorString",
+		ErrorKindTag:            "data_loss",
+		ErrorCodeTag:            "file.truncated",
+		FieldTagPrefix + "file": "data.txt",
+	}, s)
+}
+
+func TestStack(t *testing.T) {
+	assert.Equal(t, "", Stack(nil))
+	assert.Equal(t, "", Stack(io.EOF))
+	assert.Equal(t, "", Stack(errors.WithMessage(io.EOF, "read error")))
+
+	inner := errors.New("inner")
+	outer := errors.Wrap(inner, "outer")
+	assert.Equal(t, Stack(inner), Stack(outer))
+	assert.NotContains(t, Stack(outer), "\n\n")
+}
@@ -0,0 +1,154 @@
+// Package debugx serves the errors kept by an errors.Recorder over HTTP,
+// in the spirit of net/http/pprof:
+//
+//     rec := errors.NewRecorder(100)
+//     defer rec.Close()
+//
+//     debugx.Register(http.DefaultServeMux, rec)
+//
+// The page lists the recent errors with expandable fields and stack traces.
+// JSON is served instead when the request has a "format=json" query parameter
+// or accepts application/json.
+package debugx
+
+import (
+	"encoding/json"
+	"html/template"
+	"net/http"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/hexbee-net/errors"
+)
+
+// Path is the path under which Register serves the handler.
+const Path = "/debug/errors"
+
+// Record is the JSON representation of an errors.Record.
+type Record struct {
+	Time    time.Time              `json:"time"`
+	Message string                 `json:"message"`
+	Fields  map[string]interface{} `json:"fields,omitempty"`
+	Stack   []Frame                `json:"stack,omitempty"`
+}
+
+// Frame is the JSON representation of a stack frame.
+type Frame struct {
+	Function string `json:"function"`
+	File     string `json:"file"`
+	Line     int    `json:"line"`
+}
+
+// Register serves the records of rec on mux under Path.
+func Register(mux *http.ServeMux, rec *errors.Recorder) {
+	mux.Handle(Path, Handler(rec))
+}
+
+// Handler returns a handler rendering the records of rec as HTML or JSON.
+func Handler(rec *errors.Recorder) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		records := convert(rec.Recent())
+
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+
+		if wantsJSON(r) {
+			w.Header().Set("Content-Type", "application/json")
+
+			enc := json.NewEncoder(w)
+			enc.SetIndent("", "  ")
+			_ = enc.Encode(records)
+
+			return
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+
+		if err := page.Execute(w, records); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+}
+
+func wantsJSON(r *http.Request) bool {
+	if r.URL.Query().Get("format") == "json" {
+		return true
+	}
+
+	return strings.Contains(r.Header.Get("Accept"), "application/json")
+}
+
+func convert(records []errors.Record) []Record {
+	out := make([]Record, len(records))
+
+	for i, rec := range records {
+		out[i] = Record{
+			Time:    rec.Time,
+			Message: rec.Message,
+			Fields:  errors.JSONFields(rec.Fields),
+			Stack:   frames(rec),
+		}
+	}
+
+	return out
+}
+
+func frames(rec errors.Record) []Frame {
+	if len(rec.Stack) == 0 {
+		return nil
+	}
+
+	pcs := make([]uintptr, len(rec.Stack))
+	for i, f := range rec.Stack {
+		pcs[i] = uintptr(f)
+	}
+
+	out := make([]Frame, 0, len(pcs))
+	rf := runtime.CallersFrames(pcs)
+
+	for {
+		f, more := rf.Next()
+		out = append(out, Frame{
+			Function: f.Function,
+			File:     f.File,
+			Line:     f.Line,
+		})
+
+		if !more {
+			break
+		}
+	}
+
+	return out
+}
+
+//nolint:gochecknoglobals // parsed once.
+var page = template.Must(template.New("errors").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>/debug/errors</title>
+<style>
+body { font-family: sans-serif; }
+pre, code { font-family: monospace; }
+.error { border-top: 1px solid #ccc; padding: 0.5em 0; }
+.time { color: #666; }
+</style>
+</head>
+<body>
+<h1>/debug/errors</h1>
+<p>{{len .}} recent errors, most recent first. <a href="?format=json">JSON</a></p>
+{{range .}}<div class="error">
+<div><span class="time">{{.Time.Format "2006-01-02T15:04:05.000Z07:00"}}</span> <code>{{.Message}}</code></div>
+{{if .Fields}}<details><summary>fields ({{len .Fields}})</summary>
+<table>{{range $k, $v := .Fields}}<tr><td><code>{{$k}}</code></td><td><code>{{printf "%v" $v}}</code></td></tr>{{end}}</table>
+</details>{{end}}
+{{if .Stack}}<details><summary>stack ({{len .Stack}} frames)</summary>
+<pre>{{range .Stack}}{{.Function}}
+	{{.File}}:{{.Line}}
+{{end}}</pre>
+</details>{{end}}
+</div>
+{{end}}</body>
+</html>
+`))
@@ -0,0 +1,91 @@
+package debugx
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/hexbee-net/errors/internal/nostack"
+	"github.com/stretchr/testify/assert"
+)
+
+type panicky struct{}
+
+func (panicky) MarshalJSON() ([]byte, error) { panic("boom") }
+
+func newRecorder(t *testing.T) *errors.Recorder {
+	t.Helper()
+
+	rec := errors.NewRecorder(10)
+	t.Cleanup(rec.Close)
+
+	rec.Add(io.EOF)
+	rec.Add(errors.WithFields(errors.Wrap(io.EOF, "read <file>"), errors.Fields{
+		"file":    "data.txt",
+		"handler": func() {},
+		"secret":  panicky{},
+	}))
+
+	return rec
+}
+
+func TestHandlerJSON(t *testing.T) {
+	mux := http.NewServeMux()
+	Register(mux, newRecorder(t))
+
+	for _, req := range []*http.Request{
+		httptest.NewRequest(http.MethodGet, Path+"?format=json", nil),
+		func() *http.Request {
+			r := httptest.NewRequest(http.MethodGet, Path, nil)
+			r.Header.Set("Accept", "application/json")
+
+			return r
+		}(),
+	} {
+		w := httptest.NewRecorder()
+		mux.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "application/json", w.Header().Get("Content-Type"))
+
+		var records []Record
+
+		assert.NoError(t, json.Unmarshal(w.Body.Bytes(), &records))
+		assert.Len(t, records, 2)
+
+		assert.Equal(t, "read <file>: EOF", records[0].Message)
+		assert.Equal(t, "data.txt", records[0].Fields["file"])
+		assert.IsType(t, "", records[0].Fields["handler"])
+		assert.Equal(t, `<panic rendering field "secret">`, records[0].Fields["secret"])
+
+		if !nostack.Enabled && assert.NotEmpty(t, records[0].Stack) {
+			assert.Equal(t, "github.com/hexbee-net/errors/debugx.newRecorder", records[0].Stack[0].Function)
+		}
+
+		assert.Equal(t, "EOF", records[1].Message)
+		assert.Empty(t, records[1].Fields)
+		assert.Empty(t, records[1].Stack)
+	}
+}
+
+func TestHandlerHTML(t *testing.T) {
+	w := httptest.NewRecorder()
+	Handler(newRecorder(t)).ServeHTTP(w, httptest.NewRequest(http.MethodGet, Path, nil))
+
+	body := w.Body.String()
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "text/html; charset=utf-8", w.Header().Get("Content-Type"))
+	assert.Contains(t, body, "read &lt;file&gt;: EOF")
+	assert.Contains(t, body, "<summary>fields (3)</summary>")
+
+	if !nostack.Enabled {
+		assert.Contains(t, body, "debugx.newRecorder")
+	}
+
+	assert.Equal(t, 2, strings.Count(body, `<div class="error">`))
+}
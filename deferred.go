@@ -0,0 +1,23 @@
+package errors
+
+import "fmt"
+
+// Defer wraps the error the function deferring it returns, if any, with a
+// stack trace and the format specifier, like Wrapf does:
+//
+//     func process(name string) (err error) {
+//            defer errors.Defer(&err, "processing %s", name)
+//
+//            ...
+//     }
+//
+// The format specifier is only formatted if the function fails.
+// If the returned error is nil, Defer does nothing.
+func Defer(errp *error, format string, args ...interface{}) {
+	err := *errp
+	if err == nil {
+		return
+	}
+
+	*errp = wrapStack(err, fmt.Sprintf(format, args...), callers())
+}
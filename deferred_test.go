@@ -0,0 +1,28 @@
+package errors
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func deferring(name string, err error) (ret error) {
+	defer Defer(&ret, "processing %s", name)
+
+	return err
+}
+
+func TestDefer(t *testing.T) {
+	assert.Nil(t, deferring("a.txt", nil))
+
+	err := deferring("a.txt", io.EOF)
+
+	assert.Equal(t, "processing a.txt: EOF", err.Error())
+	assert.Equal(t, io.EOF, Cause(err))
+
+	st := err.(stackTracer).StackTrace()
+	if stacksCompiled && assert.NotEmpty(t, st) {
+		assert.Equal(t, "github.com/hexbee-net/errors.deferring", Frame(st[0]).Function())
+	}
+}
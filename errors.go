@@ -1,9 +1,10 @@
 package errors
 
 import (
-	"errors"
 	"fmt"
 	"io"
+	"sort"
+	"sync/atomic"
 
 	"github.com/apex/log"
 )
@@ -17,8 +18,22 @@ func (e Error) Error() string { return string(e) }
 type Fields map[string]interface{}
 
 // Fields is used for compatibility with Apex Log WithFields method.
+// Lazy values are evaluated, and the values that are not of a basic type are
+// guarded against panics when rendered by the log handlers.
 func (f Fields) Fields() log.Fields {
-	return log.Fields(f)
+	return log.Fields(safeFields(resolveFields(f)))
+}
+
+// keys returns the keys of f in sorted order.
+func (f Fields) keys() []string {
+	keys := make([]string, 0, len(f))
+	for k := range f {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
 }
 
 type causer interface {
@@ -27,38 +42,66 @@ type causer interface {
 
 // fundamental is an error that has a message and a stack, but no caller.
 type fundamental struct {
-	msg   string
-	stack *stack
+	stack
+	msg  string
+	lazy *lazyFormat
 }
 
 // New returns an error with the supplied message.
 // New also records the stack trace at the point it was called.
 func New(message string) error {
-	return &fundamental{
+	err := runHooks(&fundamental{
 		msg:   message,
 		stack: callers(),
-	}
+	}, nil)
+
+	publishCreated(err, nil)
+
+	return err
 }
 
 // Errorf formats according to a format specifier and returns the string
 // as a value that satisfies error.
 // Errorf also records the stack trace at the point it was called.
 func Errorf(format string, args ...interface{}) error {
-	return &fundamental{
+	err := runHooks(&fundamental{
 		msg:   fmt.Sprintf(format, args...),
 		stack: callers(),
-	}
+	}, nil)
+
+	publishCreated(err, nil)
+
+	return err
 }
 
 func (f *fundamental) Error() string {
+	return f.message()
+}
+
+// message returns the message of f, formatted on first use for LazyErrorf.
+func (f *fundamental) message() string {
+	if f.lazy != nil {
+		return f.lazy.String()
+	}
+
 	return f.msg
 }
 
 func (f *fundamental) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
+		if s.Flag('#') {
+			formatGoString(s, f)
+
+			return
+		}
+
 		if s.Flag('+') {
-			_, _ = io.WriteString(s, f.msg)
+			if customFormat(s, f) {
+				return
+			}
+
+			formatMessage(s, f.message())
 			f.stack.Format(s, verb)
 
 			return
@@ -66,9 +109,9 @@ func (f *fundamental) Format(s fmt.State, verb rune) {
 
 		fallthrough
 	case 's':
-		_, _ = io.WriteString(s, f.msg)
+		_, _ = io.WriteString(s, f.message())
 	case 'q':
-		_, _ = fmt.Fprintf(s, "%q", f.msg)
+		_, _ = fmt.Fprintf(s, "%q", f.message())
 	}
 }
 
@@ -79,15 +122,7 @@ func Wrap(err error, message string) error {
 		return nil
 	}
 
-	err = &withMessage{
-		cause: err,
-		msg:   message,
-	}
-
-	return &withStack{
-		err,
-		callers(),
-	}
+	return wrapStack(err, message, callers())
 }
 
 // Wrapf returns an error annotating err with a stack trace at the point Wrapf is called, and the format specifier.
@@ -97,18 +132,17 @@ func Wrapf(err error, format string, args ...interface{}) error {
 		return nil
 	}
 
-	err = &withMessage{
-		cause: err,
-		msg:   fmt.Sprintf(format, args...),
-	}
-
-	return &withStack{
-		err,
-		callers(),
-	}
+	return wrapStack(err, fmt.Sprintf(format, args...), callers())
 }
 
-// Unpack returns a slice of all the underlying errors, if possible.
+// Unpack returns a slice of all the underlying errors, if possible, one per
+// layer of the chain. The first item is the top of the stack, the origin of
+// the chain, the last one its outermost layer.
+// Layers are split like in NewDocument: a layer starts at every error adding
+// a message and includes the annotations found directly above it, such as
+// stack traces and fields. Every layer is given by its outermost error value,
+// so that its annotations are kept, its Error method returning the messages of
+// the layers below too.
 // An error value has a cause if it implements the following
 // interface:
 //
@@ -116,49 +150,204 @@ func Wrapf(err error, format string, args ...interface{}) error {
 //            Cause() error
 //     }
 //
+// If the chain loops, its layers are given down to the point the loop is
+// detected at, ErrCycle standing for the origin.
 // If the error is nil, an empty slice will be returned.
 func Unpack(err error) []error {
-	stack := make([]error, 0)
-
-	for err != nil {
-		switch v := err.(type) {
-		case *withStack:
-		case *withFields:
-		case *withMessage:
-			stack = append(stack, errors.New(v.msg))
-		default:
-			stack = append(stack, err)
+	n, depth, limit := 0, 0, MaxChainDepth()
+	e := err
+
+	for ; e != nil && depth < limit; depth++ {
+		if _, ok := ownMessage(e); ok {
+			n++
 		}
 
-		if cause, ok := err.(causer); ok {
-			err = cause.Cause()
-		} else {
-			break
+		e = causeOf(e)
+	}
+
+	if e != nil {
+		n++
+	}
+
+	layers := make([]error, n)
+	top := err
+
+	if e != nil {
+		layers[0] = ErrCycle
+	}
+
+	for e, depth = err, 0; e != nil && depth < limit; depth++ {
+		if _, ok := ownMessage(e); ok {
+			n--
+			layers[n] = top
+			top = causeOf(e)
 		}
+
+		e = causeOf(e)
 	}
 
-	// reverse the slice so that the first item is the top of the stack.
-	for i := len(stack)/2 - 1; i >= 0; i-- {
-		opp := len(stack) - 1 - i
-		stack[i], stack[opp] = stack[opp], stack[i]
+	return layers
+}
+
+// causeOf returns the cause of err, or nil if it has none.
+func causeOf(err error) error {
+	if cause, ok := err.(causer); ok {
+		return cause.Cause()
 	}
 
-	return stack
+	return nil
 }
 
 // GetFields retrieve all the fields associated with an error stack.
+// When several layers set the same key, the innermost value wins, as with
+// GetFieldsWithPrecedence and InnerFieldsWin.
 // If the error is nil, an empty slice will be returned.
 func GetFields(err error) Fields {
+	return GetFieldsWithPrecedence(err, InnerFieldsWin)
+}
+
+// FieldPrecedence tells which value GetFieldsWithPrecedence keeps when
+// several layers of a chain set the same key.
+type FieldPrecedence int
+
+const (
+	// InnerFieldsWin keeps the value set closest to the origin of the chain,
+	// the details of the failure overriding the context it was reported in.
+	InnerFieldsWin FieldPrecedence = iota
+	// OuterFieldsWin keeps the value set by the outermost layer, the
+	// annotations of the callers shadowing the ones of the code they call.
+	OuterFieldsWin
+)
+
+// MergeStrategy tells how GetFieldsWithOptions combines the values set for
+// the same key by several layers of a chain.
+type MergeStrategy int
+
+const (
+	// MergeReplace keeps the winning value as a whole.
+	MergeReplace MergeStrategy = iota
+	// MergeDeep merges the values that are both maps, such as Fields or
+	// map[string]interface{}, key by key and recursively, the winning value
+	// overriding the other on conflicting keys.
+	MergeDeep
+)
+
+// FieldsOptions configure the way GetFieldsWithOptions collects fields.
+type FieldsOptions struct {
+	// Precedence tells which layer wins when several set the same key.
+	Precedence FieldPrecedence
+	// Merge tells how the values of the same key are combined.
+	Merge MergeStrategy
+}
+
+// GetFieldsWithPrecedence retrieve all the fields associated with an error
+// stack, resolving the keys set by several layers according to p.
+// If the error is nil, an empty slice will be returned.
+func GetFieldsWithPrecedence(err error, p FieldPrecedence) Fields {
+	return GetFieldsWithOptions(err, FieldsOptions{Precedence: p})
+}
+
+// GetFieldsWithOptions retrieve all the fields associated with an error
+// stack, resolving the keys set by several layers according to opts.
+// The values of the fields are never modified: merged maps are copies.
+// If the error is nil, an empty slice will be returned.
+func GetFieldsWithOptions(err error, opts FieldsOptions) Fields {
 	type fielder interface {
 		Fields() Fields
 	}
 
 	fields := make(Fields)
 
-	for err != nil {
+	for depth := 0; err != nil && depth < MaxChainDepth(); depth++ {
+		if f, ok := err.(fielder); ok {
+			for k, v := range f.Fields() {
+				prev, set := fields[k]
+
+				switch {
+				case !set:
+					fields[k] = v
+				case opts.Merge == MergeDeep && opts.Precedence == OuterFieldsWin:
+					fields[k] = mergeValues(v, prev)
+				case opts.Merge == MergeDeep:
+					fields[k] = mergeValues(prev, v)
+				case opts.Precedence == InnerFieldsWin:
+					fields[k] = v
+				}
+			}
+		}
+
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+
+		err = cause.Cause()
+	}
+
+	return fields
+}
+
+// mergeValues returns over merged into base when both are maps, and over
+// otherwise.
+func mergeValues(base, over interface{}) interface{} {
+	b, ok := fieldMap(base)
+	if !ok {
+		return over
+	}
+
+	o, ok := fieldMap(over)
+	if !ok {
+		return over
+	}
+
+	merged := make(map[string]interface{}, len(b)+len(o))
+
+	for k, v := range b {
+		merged[k] = v
+	}
+
+	for k, v := range o {
+		if prev, set := merged[k]; set {
+			v = mergeValues(prev, v)
+		}
+
+		merged[k] = v
+	}
+
+	if _, ok := over.(Fields); ok {
+		return Fields(merged)
+	}
+
+	return merged
+}
+
+// fieldMap returns v as a map if it is one that can be merged.
+func fieldMap(v interface{}) (map[string]interface{}, bool) {
+	switch m := v.(type) {
+	case Fields:
+		return m, true
+	case map[string]interface{}:
+		return m, true
+	default:
+		return nil, false
+	}
+}
+
+// GetAllFields retrieve every value set for each field key across an error
+// stack, from the outermost layer to the innermost one, so that keys set by
+// several layers, like an attempt number, keep all their values.
+// If the error is nil, an empty map will be returned.
+func GetAllFields(err error) map[string][]interface{} {
+	type fielder interface {
+		Fields() Fields
+	}
+
+	fields := make(map[string][]interface{})
+
+	for depth := 0; err != nil && depth < MaxChainDepth(); depth++ {
 		if f, ok := err.(fielder); ok {
 			for k, v := range f.Fields() {
-				fields[k] = v
+				fields[k] = append(fields[k], v)
 			}
 		}
 
@@ -173,6 +362,49 @@ func GetFields(err error) Fields {
 	return fields
 }
 
+// LayerFields are the fields annotating one layer of an error chain.
+type LayerFields struct {
+	Message string `json:"message" yaml:"message"`
+	Fields  Fields `json:"fields" yaml:"fields"`
+}
+
+// GetFieldsByLayer returns the fields of err grouped by the layer that added
+// them, outermost first, so that the origin of every field can be told.
+// Layers are split like in NewDocument: a layer starts at every error adding
+// a message and includes the annotations found directly above it.
+// Layers without fields come with an empty map.
+// If the error is nil, an empty slice will be returned.
+func GetFieldsByLayer(err error) []LayerFields {
+	type fielder interface {
+		Fields() Fields
+	}
+
+	layers := make([]LayerFields, 0)
+	cur := make(Fields)
+
+	for depth := 0; err != nil && depth < MaxChainDepth(); depth++ {
+		if f, ok := err.(fielder); ok {
+			for k, v := range f.Fields() {
+				cur[k] = v
+			}
+		}
+
+		if msg, ok := ownMessage(err); ok {
+			layers = append(layers, LayerFields{Message: msg, Fields: cur})
+			cur = make(Fields)
+		}
+
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+
+		err = cause.Cause()
+	}
+
+	return layers
+}
+
 // Cause returns the underlying cause of the error, if possible.
 // An error value has a cause if it implements the following
 // interface:
@@ -183,9 +415,14 @@ func GetFields(err error) Fields {
 //
 // If the error does not implement Cause, the original error will
 // be returned. If the error is nil, nil will be returned without further
-// investigation.
+// investigation. If the chain loops, or is deeper than MaxChainDepth, ErrCycle
+// will be returned.
 func Cause(err error) error {
-	for err != nil {
+	for depth := 0; err != nil; depth++ {
+		if depth == MaxChainDepth() {
+			return ErrCycle
+		}
+
 		cause, ok := err.(causer)
 		if !ok {
 			break
@@ -200,8 +437,8 @@ func Cause(err error) error {
 // /////////////////////////////////////////////////////////////////////////////
 
 type withStack struct {
+	stack
 	error
-	*stack
 }
 
 // WithStack annotates err with a stack trace at the point WithStack was called.
@@ -211,10 +448,23 @@ func WithStack(err error) error {
 		return nil
 	}
 
-	return &withStack{
-		err,
-		callers(),
-	}
+	return withStackOn(err, err, callers())
+}
+
+// wrapStack returns err annotated with msg and the stack trace st, passed
+// through the hooks like the errors of the other constructors.
+func wrapStack(err error, msg string, st stack) error {
+	return withStackOn(&withMessage{cause: err, msg: msg}, err, st)
+}
+
+// withStackOn returns err, built on top of cause, annotated with the stack
+// trace st, passed through the hooks and published as created.
+func withStackOn(err, cause error, st stack) error {
+	w := runHooks(&withStack{st, err}, cause)
+
+	publishCreated(w, cause)
+
+	return w
 }
 
 func (w *withStack) Cause() error {
@@ -229,9 +479,19 @@ func (w *withStack) Unwrap() error {
 func (w *withStack) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
+		if s.Flag('#') {
+			formatGoString(s, w)
+
+			return
+		}
+
 		if s.Flag('+') {
-			_, _ = fmt.Fprintf(s, "%+v", w.Cause())
-			w.stack.Format(s, verb)
+			if customFormat(s, w) {
+				return
+			}
+
+			formatCause(s, w.Cause())
+			w.stack.formatElided(s, deepestStackBelow(w.Cause()))
 
 			return
 		}
@@ -249,6 +509,11 @@ func (w *withStack) Format(s fmt.State, verb rune) {
 type withMessage struct {
 	cause error
 	msg   string
+	lazy  *lazyFormat
+
+	// The message of the chain, built by the first call to Error as it is
+	// quadratic in the depth of the chain.
+	err atomic.Pointer[string]
 }
 
 // WithMessage annotates err with a new message.
@@ -258,10 +523,10 @@ func WithMessage(err error, message string) error {
 		return nil
 	}
 
-	return &withMessage{
+	return runHooks(&withMessage{
 		cause: err,
 		msg:   message,
-	}
+	}, err)
 }
 
 // WithMessagef annotates err with the format specifier.
@@ -271,14 +536,36 @@ func WithMessagef(err error, format string, args ...interface{}) error {
 		return nil
 	}
 
-	return &withMessage{
+	return runHooks(&withMessage{
 		cause: err,
 		msg:   fmt.Sprintf(format, args...),
-	}
+	}, err)
 }
 
+// Error returns the message of w followed by the one of its cause.
+// The message is built by the first call and kept for the next ones, so that
+// printing a deep chain repeatedly is not quadratic in its depth: a cause
+// changing its message afterwards, such as a Validation given more
+// violations, is not reflected.
+// Error is safe for concurrent use.
 func (w *withMessage) Error() string {
-	return w.msg + ": " + w.cause.Error()
+	if s := w.err.Load(); s != nil {
+		return *s
+	}
+
+	s := w.message() + ": " + w.cause.Error()
+	w.err.Store(&s)
+
+	return s
+}
+
+// message returns the message of w, formatted on first use for LazyWrapf.
+func (w *withMessage) message() string {
+	if w.lazy != nil {
+		return w.lazy.String()
+	}
+
+	return w.msg
 }
 
 func (w *withMessage) Cause() error {
@@ -293,9 +580,20 @@ func (w *withMessage) Unwrap() error {
 func (w *withMessage) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
+		if s.Flag('#') {
+			formatGoString(s, w)
+
+			return
+		}
+
 		if s.Flag('+') {
-			_, _ = fmt.Fprintf(s, "%+v\n", w.Cause())
-			_, _ = io.WriteString(s, w.msg)
+			if customFormat(s, w) {
+				return
+			}
+
+			formatCause(s, w.Cause())
+			_, _ = io.WriteString(s, "\n")
+			formatMessage(s, w.message())
 
 			return
 		}
@@ -310,7 +608,7 @@ func (w *withMessage) Format(s fmt.State, verb rune) {
 
 type withFields struct {
 	cause  error
-	fields Fields
+	fields fieldSet
 }
 
 // WithField annotates err with the specified field.
@@ -320,10 +618,10 @@ func WithField(err error, key string, value interface{}) error {
 		return nil
 	}
 
-	return &withFields{
+	return runHooks(&withFields{
 		err,
-		Fields{key: value},
-	}
+		singleField(key, value),
+	}, err)
 }
 
 // WithFields annotates err with fields.
@@ -333,16 +631,10 @@ func WithFields(err error, fields Fields) error {
 		return nil
 	}
 
-	f := make(Fields, len(fields))
-
-	for k, v := range fields {
-		f[k] = v
-	}
-
-	return &withFields{
+	return runHooks(&withFields{
 		err,
-		f,
-	}
+		newFieldSet(fields),
+	}, err)
 }
 
 func (w *withFields) Error() string {
@@ -358,16 +650,28 @@ func (w *withFields) Unwrap() error {
 }
 
 func (w *withFields) Fields() Fields {
-	return w.fields
+	return resolveFields(w.fields.toMap())
 }
 
 func (w *withFields) Format(s fmt.State, verb rune) {
 	switch verb {
 	case 'v':
+		if s.Flag('#') {
+			formatGoString(s, w)
+
+			return
+		}
+
 		if s.Flag('+') {
-			_, _ = fmt.Fprintf(s, "%+v\n", w.Cause())
-			for k, v := range w.fields {
-				_, _ = fmt.Fprintf(s, "  %s: %v\n", k, v)
+			if customFormat(s, w) {
+				return
+			}
+
+			formatCause(s, w.Cause())
+			_, _ = io.WriteString(s, "\n")
+			fields, limits := w.Fields(), GetFieldLimits()
+			for _, k := range fields.keys() {
+				formatAnnotation(s, k, FormatField(k, limits.Limit(k, fields[k])))
 			}
 
 			return
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"io"
 	"reflect"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -129,6 +131,11 @@ func TestWrapf(t *testing.T) {
 }
 
 func TestUnstack(t *testing.T) {
+	wrapped := Wrap(io.EOF, "read error")
+	annotated := WithField(wrapped, "key", "value")
+	origin := WithStack(WithKind(New("origin"), KindInternal))
+	deep := WithMessage(origin, "outer")
+
 	tests := []struct {
 		err  error
 		want []error
@@ -138,30 +145,42 @@ func TestUnstack(t *testing.T) {
 			want: []error{},
 		},
 		{
-			err: Wrap(io.EOF, "read error"),
-			want: []error{
-				errors.New("read error"),
-				io.EOF,
-			},
+			err:  wrapped,
+			want: []error{io.EOF, wrapped},
 		},
 		{
-			err: WithField(Wrap(io.EOF, "read error"), "key", "value"),
-			want: []error{
-				errors.New("read error"),
-				io.EOF,
-			},
+			err:  annotated,
+			want: []error{io.EOF, annotated},
 		},
 		{
-			err: io.EOF,
-			want: []error{
-				io.EOF,
-			},
+			err:  io.EOF,
+			want: []error{io.EOF},
+		},
+		{
+			err:  deep,
+			want: []error{origin, deep},
 		},
 	}
 
 	for _, tt := range tests {
-		got := Unpack(tt.err)
-		assert.ElementsMatch(t, tt.want, got)
+		assert.Equal(t, tt.want, Unpack(tt.err))
+	}
+}
+
+func BenchmarkUnpack(b *testing.B) {
+	for _, depth := range []int{1, 8, 64, 256} {
+		err := error(io.EOF)
+		for i := 0; i < depth; i++ {
+			err = WithField(Wrap(err, "layer"), "depth", i)
+		}
+
+		b.Run(fmt.Sprintf("depth=%d", depth), func(b *testing.B) {
+			b.ReportAllocs()
+
+			for i := 0; i < b.N; i++ {
+				_ = Unpack(err)
+			}
+		})
 	}
 }
 
@@ -251,6 +270,98 @@ func TestWithFields(t *testing.T) {
 	}
 }
 
+func TestWithFieldsFormat(t *testing.T) {
+	err := WithFields(io.EOF, Fields{"c": 3, "a": 1, "b": 2, "d": 4})
+
+	for i := 0; i < 10; i++ {
+		assert.Equal(t, "EOF\n  a: 1\n  b: 2\n  c: 3\n  d: 4\n", fmt.Sprintf("%+v", err))
+	}
+}
+
+func TestGetFieldsWithPrecedence(t *testing.T) {
+	err := WithFields(Wrap(WithFields(io.EOF, Fields{"key1": "inner", "key2": "value2"}), "read error"), Fields{"key1": "outer"})
+
+	assert.Equal(t, Fields{}, GetFieldsWithPrecedence(nil, OuterFieldsWin))
+	assert.Equal(t, Fields{"key1": "inner", "key2": "value2"}, GetFields(err))
+	assert.Equal(t, Fields{"key1": "inner", "key2": "value2"}, GetFieldsWithPrecedence(err, InnerFieldsWin))
+	assert.Equal(t, Fields{"key1": "outer", "key2": "value2"}, GetFieldsWithPrecedence(err, OuterFieldsWin))
+}
+
+func TestGetFieldsWithOptions(t *testing.T) {
+	err := WithField(
+		Wrap(WithFields(io.EOF, Fields{
+			"request": map[string]interface{}{"id": "inner", "headers": Fields{"a": 1}},
+			"size":    1,
+		}), "read error"),
+		"request", Fields{"id": "outer", "method": "GET", "headers": Fields{"b": 2}},
+	)
+
+	tests := []struct {
+		opts FieldsOptions
+		want Fields
+	}{
+		{
+			FieldsOptions{},
+			Fields{"request": map[string]interface{}{"id": "inner", "headers": Fields{"a": 1}}, "size": 1},
+		},
+		{
+			FieldsOptions{Precedence: OuterFieldsWin},
+			Fields{"request": Fields{"id": "outer", "method": "GET", "headers": Fields{"b": 2}}, "size": 1},
+		},
+		{
+			FieldsOptions{Merge: MergeDeep},
+			Fields{"request": map[string]interface{}{"id": "inner", "method": "GET", "headers": Fields{"a": 1, "b": 2}}, "size": 1},
+		},
+		{
+			FieldsOptions{Precedence: OuterFieldsWin, Merge: MergeDeep},
+			Fields{"request": Fields{"id": "outer", "method": "GET", "headers": Fields{"a": 1, "b": 2}}, "size": 1},
+		},
+	}
+
+	for i, tt := range tests {
+		assert.Equal(t, tt.want, GetFieldsWithOptions(err, tt.opts), "test %d", i+1)
+	}
+
+	// The fields of the layers are left untouched.
+	assert.Equal(t, Fields{"id": "outer", "method": "GET", "headers": Fields{"b": 2}}, GetFieldsByLayer(err)[0].Fields["request"])
+	assert.Equal(t, map[string]interface{}{"id": "inner", "headers": Fields{"a": 1}}, GetFieldsByLayer(err)[1].Fields["request"])
+}
+
+func TestGetAllFields(t *testing.T) {
+	err := WithField(Wrap(WithFields(io.EOF, Fields{"attempt": 1, "query": "q"}), "retrying"), "attempt", 2)
+
+	assert.Equal(t, map[string][]interface{}{}, GetAllFields(nil))
+	assert.Equal(t, map[string][]interface{}{"attempt": {2, 1}, "query": {"q"}}, GetAllFields(err))
+}
+
+func TestGetFieldsByLayer(t *testing.T) {
+	tests := []struct {
+		err  error
+		want []LayerFields
+	}{
+		{nil, []LayerFields{}},
+		{io.EOF, []LayerFields{{"EOF", Fields{}}}},
+		{
+			err: WithField(Wrap(WithFields(io.EOF, Fields{"key1": "inner", "key2": "value2"}), "read error"), "key1", "outer"),
+			want: []LayerFields{
+				{"read error", Fields{"key1": "outer"}},
+				{"EOF", Fields{"key1": "inner", "key2": "value2"}},
+			},
+		},
+		{
+			err: WithField(WithMessage(WithKind(io.EOF, KindNotFound), "read error"), "key", "value"),
+			want: []LayerFields{
+				{"read error", Fields{"key": "value"}},
+				{"EOF", Fields{}},
+			},
+		},
+	}
+
+	for i, tt := range tests {
+		assert.Equal(t, tt.want, GetFieldsByLayer(tt.err), "test %d", i+1)
+	}
+}
+
 func TestWithMessageNil(t *testing.T) {
 	got := WithMessage(nil, "no error")
 	assert.Nil(t, got)
@@ -321,3 +432,68 @@ func TestErrorEquality(t *testing.T) {
 		}
 	})
 }
+
+func TestFormatElidesCommonFrames(t *testing.T) {
+	requireStacks(t)
+
+	ShowAllFrames(true)
+	defer ShowAllFrames(false)
+
+	inner := func() error { return New("inner") }
+
+	err := Wrap(inner(), "outer")
+	got := fmt.Sprintf("%+v", err)
+
+	assert.Contains(t, got, "\n... 2 common frames omitted")
+	assert.Equal(t, 1, strings.Count(got, "testing.tRunner"))
+	assert.Equal(t, 2, strings.Count(got, "TestFormatElidesCommonFrames\n"))
+
+	assert.NotContains(t, fmt.Sprintf("%+v", WithStack(io.EOF)), "common frames omitted")
+}
+
+func TestWithMessageErrorCached(t *testing.T) {
+	err := WithMessage(WithMessage(io.EOF, "inner"), "outer").(*withMessage)
+
+	assert.Nil(t, err.err.Load())
+
+	done := make(chan string)
+
+	for i := 0; i < 4; i++ {
+		go func() { done <- err.Error() }()
+	}
+
+	for i := 0; i < 4; i++ {
+		assert.Equal(t, "outer: inner: EOF", <-done)
+	}
+
+	if assert.NotNil(t, err.err.Load()) {
+		assert.Equal(t, "outer: inner: EOF", *err.err.Load())
+	}
+
+	assert.Equal(t, "inner: EOF", *err.cause.(*withMessage).err.Load())
+}
+
+func TestWithMessageErrorConcurrent(t *testing.T) {
+	err := error(io.EOF)
+	for i := 0; i < 64; i++ {
+		err = WithMessagef(err, "layer %d", i)
+	}
+
+	want := err.(*withMessage).message() + ": " + err.(*withMessage).cause.Error()
+
+	var wg sync.WaitGroup
+
+	for i := 0; i < 16; i++ {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			for j := 0; j < 100; j++ {
+				assert.Equal(t, want, err.Error())
+			}
+		}()
+	}
+
+	wg.Wait()
+}
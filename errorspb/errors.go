@@ -0,0 +1,167 @@
+// Package errorspb converts error chains to and from the protocol buffer
+// messages described by errors.proto.
+//
+// The Error, Layer and Frame types are generated from errors.proto by
+// protoc-gen-go, so that the messages can be attached to gRPC statuses as
+// details and decoded by code generated from the schema in any language.
+//
+// It lives in its own module so that the core package does not depend on the
+// protocol buffer runtime.
+package errorspb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative errors.proto
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/hexbee-net/errors"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ToProto returns the message describing err.
+// The fields are encoded as google.protobuf.Value: errors and fmt.Stringer
+// values are replaced by their message, and the values of other types JSON
+// has no representation for by their fmt.Sprint formatting.
+// If err is nil, ToProto returns nil.
+func ToProto(err error) *Error {
+	if err == nil {
+		return nil
+	}
+
+	doc := errors.NewDocument(err)
+	m := &Error{
+		Message: doc.Message,
+		Layers:  make([]*Layer, len(doc.Layers)),
+	}
+
+	for i, l := range doc.Layers {
+		pl := &Layer{
+			Message:     l.Message,
+			Kind:        string(l.Kind),
+			Code:        l.Code,
+			UserMessage: l.UserMessage,
+			Status:      int32(l.Status),
+			Stack:       make([]*Frame, len(l.Stack)),
+		}
+
+		if len(l.Fields) > 0 {
+			pl.Fields = make(map[string]*structpb.Value, len(l.Fields))
+			for k, v := range l.Fields {
+				pl.Fields[k] = toValue(v)
+			}
+		}
+
+		for j, f := range l.Stack {
+			pl.Stack[j] = &Frame{
+				Function: f.Function,
+				File:     f.File,
+				Line:     int64(f.Line),
+			}
+		}
+
+		m.Layers[i] = pl
+	}
+
+	return m
+}
+
+// FromProto rebuilds the error chain described by m as errors.RemoteError values.
+// The fields are decoded like by structpb.Value.AsInterface: numbers as
+// float64, nested maps as map[string]interface{} and lists as []interface{}.
+// If m is nil or has no layers, FromProto returns nil.
+func FromProto(m *Error) error {
+	if m == nil {
+		return nil
+	}
+
+	doc := errors.Document{
+		Message: m.GetMessage(),
+		Layers:  make([]errors.Layer, len(m.GetLayers())),
+	}
+
+	for i, pl := range m.GetLayers() {
+		l := errors.Layer{
+			Message:     pl.GetMessage(),
+			Kind:        errors.Kind(pl.GetKind()),
+			Code:        pl.GetCode(),
+			UserMessage: pl.GetUserMessage(),
+			Status:      int(pl.GetStatus()),
+		}
+
+		if len(pl.GetFields()) > 0 {
+			l.Fields = make(errors.Fields, len(pl.GetFields()))
+			for k, v := range pl.GetFields() {
+				l.Fields[k] = v.AsInterface()
+			}
+		}
+
+		if len(pl.GetStack()) > 0 {
+			l.Stack = make([]errors.StackFrame, len(pl.GetStack()))
+			for j, f := range pl.GetStack() {
+				l.Stack[j] = errors.StackFrame{
+					Function: f.GetFunction(),
+					File:     f.GetFile(),
+					Line:     int(f.GetLine()),
+				}
+			}
+		}
+
+		doc.Layers[i] = l
+	}
+
+	return errors.FromDocument(doc)
+}
+
+// toValue returns the google.protobuf.Value describing the field value v.
+func toValue(v interface{}) *structpb.Value {
+	switch v := v.(type) {
+	case nil:
+		return structpb.NewNullValue()
+	case string:
+		return structpb.NewStringValue(v)
+	case bool:
+		return structpb.NewBoolValue(v)
+	case error:
+		return structpb.NewStringValue(v.Error())
+	case fmt.Stringer:
+		return structpb.NewStringValue(v.String())
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() { //nolint:exhaustive // other kinds are formatted
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return structpb.NewNumberValue(float64(rv.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return structpb.NewNumberValue(float64(rv.Uint()))
+	case reflect.Float32, reflect.Float64:
+		return structpb.NewNumberValue(rv.Float())
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		st := &structpb.Struct{Fields: make(map[string]*structpb.Value, rv.Len())}
+
+		iter := rv.MapRange()
+		for iter.Next() {
+			st.Fields[iter.Key().String()] = toValue(iter.Value().Interface())
+		}
+
+		return structpb.NewStructValue(st)
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			break
+		}
+
+		list := &structpb.ListValue{Values: make([]*structpb.Value, rv.Len())}
+		for i := 0; i < rv.Len(); i++ {
+			list.Values[i] = toValue(rv.Index(i).Interface())
+		}
+
+		return structpb.NewListValue(list)
+	}
+
+	return structpb.NewStringValue(fmt.Sprint(v))
+}
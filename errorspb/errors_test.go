@@ -0,0 +1,125 @@
+package errorspb
+
+import (
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestToProtoNil(t *testing.T) {
+	assert.Nil(t, ToProto(nil))
+	assert.Nil(t, FromProto(nil))
+	assert.Nil(t, FromProto(&Error{}))
+}
+
+func TestRoundTrip(t *testing.T) {
+	err := errors.WithHTTPStatus(errors.WithUserMessage(errors.WithCode(
+		errors.WithKind(
+			errors.WithFields(errors.Wrap(errors.New("boom"), "read error"), errors.Fields{
+				"file":   "data.txt",
+				"size":   42,
+				"ok":     false,
+				"none":   nil,
+				"tags":   []string{"a", "b"},
+				"nested": map[string]interface{}{"depth": 1.5},
+			}),
+			errors.KindDataLoss,
+		),
+		"file.truncated",
+	), "The file is corrupted."), http.StatusUnprocessableEntity)
+
+	opts := proto.MarshalOptions{Deterministic: true}
+
+	data, e := opts.Marshal(ToProto(err))
+	assert.NoError(t, e)
+
+	var m Error
+
+	assert.NoError(t, proto.Unmarshal(data, &m))
+
+	got := FromProto(&m)
+
+	assert.Equal(t, "read error: boom", got.Error())
+	assert.Equal(t, errors.KindDataLoss, errors.GetKind(got))
+	assert.Equal(t, "file.truncated", errors.GetCode(got))
+	assert.Equal(t, "The file is corrupted.", errors.GetUserMessage(got))
+	assert.Equal(t, http.StatusUnprocessableEntity, errors.HTTPStatus(got))
+	assert.Equal(t, errors.Fields{
+		"file":   "data.txt",
+		"size":   42.0,
+		"ok":     false,
+		"none":   nil,
+		"tags":   []interface{}{"a", "b"},
+		"nested": map[string]interface{}{"depth": 1.5},
+	}, errors.GetFields(got))
+
+	assert.Len(t, m.Layers, 2)
+	assert.NotEmpty(t, m.Layers[0].Stack)
+	assert.Equal(t, "github.com/hexbee-net/errors/errorspb.TestRoundTrip", m.Layers[0].Stack[0].Function)
+	assert.NotZero(t, m.Layers[0].Stack[0].Line)
+
+	again, e := opts.Marshal(ToProto(got))
+	assert.NoError(t, e)
+	assert.Equal(t, data, again)
+}
+
+func TestToValue(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want *structpb.Value
+	}{
+		{nil, structpb.NewNullValue()},
+		{true, structpb.NewBoolValue(true)},
+		{"a", structpb.NewStringValue("a")},
+		{1, structpb.NewNumberValue(1)},
+		{uint8(2), structpb.NewNumberValue(2)},
+		{float32(0.5), structpb.NewNumberValue(0.5)},
+		{io.EOF, structpb.NewStringValue("EOF")},
+		{struct{ A int }{1}, structpb.NewStringValue("{1}")},
+		{[]byte("ab"), structpb.NewStringValue("[97 98]")},
+		{[]bool{false}, structpb.NewListValue(&structpb.ListValue{Values: []*structpb.Value{structpb.NewBoolValue(false)}})},
+		{map[string]int{"a": 1}, structpb.NewStructValue(&structpb.Struct{Fields: map[string]*structpb.Value{
+			"a": structpb.NewNumberValue(1),
+		}})},
+		{map[int]string{1: "a"}, structpb.NewStringValue("map[1:a]")},
+	}
+
+	for i, tt := range tests {
+		assert.True(t, proto.Equal(tt.want, toValue(tt.v)), "test %d", i+1)
+	}
+}
+
+func TestUnmarshalUnknownFields(t *testing.T) {
+	data := []byte{
+		0x0a, 0x03, 'E', 'O', 'F', // message
+		0x18, 0x2a, // unknown varint field 3
+		0x25, 0, 0, 0, 0, // unknown fixed32 field 4
+		0x12, 0x05, 0x0a, 0x03, 'E', 'O', 'F', // layer
+	}
+
+	var m Error
+
+	assert.NoError(t, proto.Unmarshal(data, &m))
+	assert.Equal(t, "EOF", FromProto(&m).Error())
+}
+
+func TestUnmarshalInvalid(t *testing.T) {
+	tests := [][]byte{
+		{0x0a},
+		{0x0a, 0x05, 'E'},
+		{0x00},
+		{0x12, 0x02, 0x0a, 0x05},
+		{0x0f},
+	}
+
+	for i, data := range tests {
+		var m Error
+
+		assert.Error(t, proto.Unmarshal(data, &m), "test %d", i+1)
+	}
+}
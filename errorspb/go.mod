@@ -0,0 +1,19 @@
+module github.com/hexbee-net/errors/errorspb
+
+go 1.20
+
+require (
+	github.com/hexbee-net/errors v0.0.0
+	github.com/stretchr/testify v1.6.1
+	google.golang.org/protobuf v1.31.0
+)
+
+require (
+	github.com/apex/log v1.9.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/hexbee-net/errors => ../
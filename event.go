@@ -0,0 +1,212 @@
+package errors
+
+import (
+	stderrors "errors"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// EventType tells how an error reached the subscribers.
+type EventType int
+
+// Event types.
+const (
+	// EventCreated is published the first time a chain records a stack trace,
+	// that is by New and Errorf, or by Wrap, Wrapf and WithStack on an error
+	// that does not carry a stack trace yet.
+	EventCreated EventType = iota
+	// EventReported is published by Report.
+	EventReported
+	// EventIgnored is published by Ignore.
+	EventIgnored
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventCreated:
+		return "created"
+	case EventReported:
+		return "reported"
+	case EventIgnored:
+		return "ignored"
+	default:
+		return "unknown"
+	}
+}
+
+// Event is delivered to subscribers.
+type Event struct {
+	Type EventType
+	Err  error
+	Time time.Time
+}
+
+// Subscription receives events on C until it is closed.
+type Subscription struct {
+	// C delivers the events.
+	C <-chan Event
+
+	mu      sync.RWMutex
+	c       chan Event
+	closed  bool
+	dropped uint64
+}
+
+//nolint:gochecknoglobals // subscriptions are registered process-wide.
+var (
+	subscriptionsMu sync.Mutex
+	subscriptions   atomic.Value // []*Subscription
+)
+
+// Subscribe returns a subscription buffering up to size events.
+// Events are never waited for: when the buffer of a subscription is full,
+// the event is dropped for that subscription and counted in Dropped.
+// The subscription must be closed once it is not needed anymore.
+func Subscribe(size int) *Subscription {
+	c := make(chan Event, size)
+	s := &Subscription{
+		C: c,
+		c: c,
+	}
+
+	subscriptionsMu.Lock()
+	defer subscriptionsMu.Unlock()
+
+	current, _ := subscriptions.Load().([]*Subscription)
+
+	registered := make([]*Subscription, len(current), len(current)+1)
+	copy(registered, current)
+
+	subscriptions.Store(append(registered, s))
+
+	return s
+}
+
+// Close unsubscribes s and closes C.
+// Close can safely be called several times.
+func (s *Subscription) Close() {
+	subscriptionsMu.Lock()
+
+	current, _ := subscriptions.Load().([]*Subscription)
+
+	registered := make([]*Subscription, 0, len(current))
+
+	for _, r := range current {
+		if r != s {
+			registered = append(registered, r)
+		}
+	}
+
+	subscriptions.Store(registered)
+	subscriptionsMu.Unlock()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.closed {
+		s.closed = true
+		close(s.c)
+	}
+}
+
+// Dropped returns the number of events dropped because the buffer was full.
+func (s *Subscription) Dropped() uint64 {
+	return atomic.LoadUint64(&s.dropped)
+}
+
+func (s *Subscription) send(e Event) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.closed {
+		return
+	}
+
+	select {
+	case s.c <- e:
+	default:
+		atomic.AddUint64(&s.dropped, 1)
+	}
+}
+
+// Report publishes err to the subscribers, for instance to ship it to an
+// error tracker in the background.
+// If err is nil, Report does nothing.
+func Report(err error) {
+	if err == nil {
+		return
+	}
+
+	publish(EventReported, err)
+}
+
+// Ignore discards err deliberately, publishing it to the subscribers as
+// ignored so that the discarded errors remain observable, such as by metrics:
+//
+//     errors.Ignore(conn.Close())
+//
+// If reasons are given, err is only discarded if it matches one of them with
+// errors.Is, and returned otherwise:
+//
+//     if err := errors.Ignore(os.Remove(path), fs.ErrNotExist); err != nil {
+//            return err
+//     }
+//
+// If err is nil or discarded, Ignore returns nil.
+func Ignore(err error, reasons ...error) error {
+	if err == nil {
+		return nil
+	}
+
+	if len(reasons) > 0 && !isAny(err, reasons) {
+		return err
+	}
+
+	publish(EventIgnored, err)
+
+	return nil
+}
+
+// isAny reports whether err matches one of targets with errors.Is.
+func isAny(err error, targets []error) bool {
+	for _, target := range targets {
+		if stderrors.Is(err, target) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// publishCreated publishes err, recorded on top of cause, if cause does not
+// carry a stack trace.
+func publishCreated(err, cause error) {
+	registered, _ := subscriptions.Load().([]*Subscription)
+	if len(registered) == 0 {
+		return
+	}
+
+	if cause != nil && hasStack(cause) {
+		return
+	}
+
+	publish(EventCreated, err)
+}
+
+func publish(t EventType, err error) {
+	registered, _ := subscriptions.Load().([]*Subscription)
+	if len(registered) == 0 {
+		return
+	}
+
+	e := Event{
+		Type: t,
+		Err:  err,
+		Time: time.Now(),
+	}
+
+	for _, s := range registered {
+		s.send(e)
+	}
+}
@@ -0,0 +1,118 @@
+package errors
+
+import (
+	"io"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSubscribe(t *testing.T) {
+	s := Subscribe(10)
+	defer s.Close()
+
+	fundamental := New("created")
+	wrapped := Wrap(io.EOF, "created")
+	_ = Wrap(fundamental, "not created")
+	_ = WithStack(wrapped)
+	_ = WithMessage(io.EOF, "not created")
+	_ = WithField(io.EOF, "key", "value")
+
+	Report(nil)
+	Report(io.EOF)
+
+	want := []struct {
+		typ EventType
+		err error
+	}{
+		{EventCreated, fundamental},
+		{EventCreated, wrapped},
+		{EventReported, io.EOF},
+	}
+
+	for _, w := range want {
+		e := <-s.C
+		assert.Equal(t, w.typ, e.Type)
+		assert.Equal(t, w.err, e.Err)
+		assert.False(t, e.Time.IsZero())
+	}
+
+	assert.Empty(t, s.C)
+}
+
+func TestSubscriptionDropped(t *testing.T) {
+	s := Subscribe(1)
+	defer s.Close()
+
+	Report(io.EOF)
+	Report(io.EOF)
+	Report(io.EOF)
+
+	assert.Equal(t, uint64(2), s.Dropped())
+	assert.Len(t, s.C, 1)
+}
+
+func TestSubscriptionClose(t *testing.T) {
+	s := Subscribe(1)
+	s.Close()
+	s.Close()
+
+	Report(io.EOF)
+
+	_, ok := <-s.C
+	assert.False(t, ok)
+}
+
+func TestSubscriptionConcurrent(t *testing.T) {
+	const n = 100
+
+	var wg sync.WaitGroup
+
+	wg.Add(2 * n)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+
+			Report(io.EOF)
+		}()
+
+		go func() {
+			defer wg.Done()
+
+			s := Subscribe(1)
+			s.Close()
+		}()
+	}
+
+	wg.Wait()
+}
+
+func TestIgnore(t *testing.T) {
+	unexpected := Wrap(io.ErrUnexpectedEOF, "reading")
+	kept := Wrap(io.ErrClosedPipe, "writing")
+
+	s := Subscribe(10)
+	defer s.Close()
+
+	assert.Nil(t, Ignore(nil))
+	assert.Nil(t, Ignore(io.EOF))
+	assert.Nil(t, Ignore(unexpected, io.EOF, io.ErrUnexpectedEOF))
+	assert.Equal(t, kept, Ignore(kept, io.EOF))
+
+	for _, want := range []error{io.EOF, unexpected} {
+		e := <-s.C
+		assert.Equal(t, EventIgnored, e.Type)
+		assert.Equal(t, want, e.Err)
+	}
+
+	assert.Empty(t, s.C)
+}
+
+func TestEventTypeString(t *testing.T) {
+	assert.Equal(t, "created", EventCreated.String())
+	assert.Equal(t, "reported", EventReported.String())
+	assert.Equal(t, "ignored", EventIgnored.String())
+	assert.Equal(t, "unknown", EventType(-1).String())
+}
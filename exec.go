@@ -0,0 +1,79 @@
+package errors
+
+import (
+	stderrors "errors"
+	"fmt"
+	"os/exec"
+	"unicode/utf8"
+)
+
+// Fields added by WrapExec.
+const (
+	ExecArgsField     = "exec.args"
+	ExecExitCodeField = "exec.exit_code"
+	ExecStderrField   = "exec.stderr"
+)
+
+// MaxExecStderr is the number of bytes, from the end of the standard error of
+// a command, kept by WrapExec.
+const MaxExecStderr = 1024
+
+// WrapExec returns an error annotating err, returned by running cmd, with a
+// stack trace at the point WrapExec is called and the arguments, exit code
+// and end of the standard error of the command as fields.
+// The standard error is taken from the *exec.ExitError returned by
+// cmd.Output, or from cmd.Stderr when it has a String method, like
+// *bytes.Buffer.
+// If err is nil, WrapExec returns nil.
+func WrapExec(err error, cmd *exec.Cmd) error {
+	if err == nil {
+		return nil
+	}
+
+	fields := Fields{ExecArgsField: cmd.Args}
+
+	var stderr string
+
+	var exitErr *exec.ExitError
+	if stderrors.As(err, &exitErr) {
+		fields[ExecExitCodeField] = exitErr.ExitCode()
+		stderr = string(exitErr.Stderr)
+	}
+
+	if s, ok := cmd.Stderr.(fmt.Stringer); ok && stderr == "" {
+		stderr = s.String()
+	}
+
+	if stderr != "" {
+		fields[ExecStderrField] = tail(stderr, MaxExecStderr)
+	}
+
+	name := cmd.Path
+	if len(cmd.Args) > 0 {
+		name = cmd.Args[0]
+	}
+
+	w := wrapStack(err, "running "+name, callers())
+
+	return runHooks(&withFields{
+		w,
+		newFieldSet(fields),
+	}, w)
+}
+
+// tail returns the last max bytes of s, prefixed with an ellipsis when s is
+// truncated.
+func tail(s string, max int) string {
+	if len(s) <= max {
+		return s
+	}
+
+	s = s[len(s)-max:]
+
+	// Do not start in the middle of a character.
+	for i := 0; i < utf8.UTFMax && s != "" && !utf8.RuneStart(s[0]); i++ {
+		s = s[1:]
+	}
+
+	return "…" + s
+}
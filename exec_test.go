@@ -0,0 +1,66 @@
+package errors
+
+import (
+	"bytes"
+	stderrors "errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrapExecNil(t *testing.T) {
+	assert.Nil(t, WrapExec(nil, exec.Command("true")))
+}
+
+func TestWrapExec(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("requires a POSIX shell")
+	}
+
+	cmd := exec.Command("sh", "-c", "echo oops >&2; exit 3")
+	_, err := cmd.Output()
+
+	err = WrapExec(err, cmd)
+
+	assert.Equal(t, "running sh: exit status 3", err.Error())
+	assert.Equal(t, Fields{
+		ExecArgsField:     []string{"sh", "-c", "echo oops >&2; exit 3"},
+		ExecExitCodeField: 3,
+		ExecStderrField:   "oops\n",
+	}, GetFields(err))
+
+	var exitErr *exec.ExitError
+	assert.True(t, stderrors.As(err, &exitErr))
+
+	if stacksCompiled {
+		assert.Contains(t, fmt.Sprintf("%+v", err), "errors.TestWrapExec\n")
+	}
+
+	var stderr bytes.Buffer
+
+	cmd = exec.Command("sh", "-c", "echo failed >&2; exit 1")
+	cmd.Stderr = &stderr
+
+	err = WrapExec(cmd.Run(), cmd)
+	assert.Equal(t, "failed\n", GetFields(err)[ExecStderrField])
+	assert.Equal(t, 1, GetFields(err)[ExecExitCodeField])
+}
+
+func TestWrapExecNotFound(t *testing.T) {
+	cmd := exec.Command("hexbee-missing-command")
+
+	err := WrapExec(cmd.Run(), cmd)
+
+	assert.True(t, stderrors.Is(err, exec.ErrNotFound))
+	assert.Equal(t, Fields{ExecArgsField: []string{"hexbee-missing-command"}}, GetFields(err))
+}
+
+func TestTail(t *testing.T) {
+	assert.Equal(t, "short", tail("short", 10))
+	assert.Equal(t, "…6789", tail("0123456789", 4))
+	assert.Equal(t, "…b", tail(strings.Repeat("a", 10)+"éb", 2))
+}
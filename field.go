@@ -0,0 +1,184 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"time"
+)
+
+type fieldType uint8
+
+const (
+	fieldAny fieldType = iota
+	fieldString
+	fieldInt
+	fieldBool
+	fieldDuration
+	fieldTime
+	fieldTimeFull
+	fieldErr
+)
+
+// Field is a typed field built by String, Int, Bool, Duration, Time, Err or
+// Any.
+// Typed fields carry their value without boxing it into an interface, so that
+// WithFieldList annotates errors without allocating a map in hot paths; the
+// values are only converted when the fields are read.
+type Field struct {
+	Key string
+
+	typ fieldType
+	num int64
+	str string
+	val interface{}
+}
+
+// String returns a field holding a string.
+func String(key, value string) Field {
+	return Field{Key: key, typ: fieldString, str: value}
+}
+
+// Int returns a field holding an int.
+func Int(key string, value int) Field {
+	return Field{Key: key, typ: fieldInt, num: int64(value)}
+}
+
+// Bool returns a field holding a bool.
+func Bool(key string, value bool) Field {
+	var n int64
+	if value {
+		n = 1
+	}
+
+	return Field{Key: key, typ: fieldBool, num: n}
+}
+
+// Duration returns a field holding a time.Duration.
+func Duration(key string, value time.Duration) Field {
+	return Field{Key: key, typ: fieldDuration, num: int64(value)}
+}
+
+// Time returns a field holding a time.Time.
+// The monotonic clock reading of value is dropped.
+func Time(key string, value time.Time) Field {
+	// Only the times representable as nanoseconds since the epoch avoid boxing.
+	if n := value.UnixNano(); time.Unix(0, n).Equal(value) {
+		return Field{Key: key, typ: fieldTime, num: n, val: value.Location()}
+	}
+
+	return Field{Key: key, typ: fieldTimeFull, val: value.Round(0)}
+}
+
+// Err returns a field holding the message of err.
+// If err is nil, the value of the field is nil.
+func Err(key string, err error) Field {
+	return Field{Key: key, typ: fieldErr, val: err}
+}
+
+// Any returns a field holding value of any type.
+// Lazy values are evaluated when the field is read.
+func Any(key string, value interface{}) Field {
+	return Field{Key: key, typ: fieldAny, val: value}
+}
+
+// Value returns the value held by f.
+func (f Field) Value() interface{} {
+	switch f.typ {
+	case fieldString:
+		return f.str
+	case fieldInt:
+		return int(f.num)
+	case fieldBool:
+		return f.num == 1
+	case fieldDuration:
+		return time.Duration(f.num)
+	case fieldTime:
+		loc, _ := f.val.(*time.Location)
+
+		return time.Unix(0, f.num).In(loc)
+	case fieldErr:
+		if err, ok := f.val.(error); ok && err != nil {
+			return err.Error()
+		}
+
+		return nil
+	case fieldAny, fieldTimeFull:
+		return resolveField(f.val)
+	default:
+		return f.val
+	}
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+
+type withFieldList struct {
+	cause  error
+	fields []Field
+}
+
+// WithFieldList annotates err with the typed fields.
+// When several fields share a key, the last one wins.
+// If err is nil, WithFieldList returns nil.
+func WithFieldList(err error, fields ...Field) error {
+	if err == nil {
+		return nil
+	}
+
+	return runHooks(&withFieldList{
+		err,
+		fields,
+	}, err)
+}
+
+func (w *withFieldList) Error() string {
+	return w.cause.Error()
+}
+
+func (w *withFieldList) Cause() error {
+	return w.cause
+}
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withFieldList) Unwrap() error {
+	return w.cause
+}
+
+func (w *withFieldList) Fields() Fields {
+	fields := make(Fields, len(w.fields))
+
+	for _, f := range w.fields {
+		fields[f.Key] = f.Value()
+	}
+
+	return fields
+}
+
+func (w *withFieldList) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			formatGoString(s, w)
+
+			return
+		}
+
+		if s.Flag('+') {
+			if customFormat(s, w) {
+				return
+			}
+
+			formatCause(s, w.Cause())
+			_, _ = io.WriteString(s, "\n")
+			limits := GetFieldLimits()
+			for _, f := range w.fields {
+				formatAnnotation(s, f.Key, FormatField(f.Key, limits.Limit(f.Key, f.Value())))
+			}
+
+			return
+		}
+
+		fallthrough
+	case 's', 'q':
+		_, _ = io.WriteString(s, w.Error())
+	}
+}
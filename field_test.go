@@ -0,0 +1,79 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldValue(t *testing.T) {
+	now := time.Date(2020, 6, 1, 12, 30, 0, 5, time.FixedZone("CEST", 7200))
+	distant := time.Date(3000, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		field Field
+		want  interface{}
+	}{
+		{String("k", "v"), "v"},
+		{Int("k", -3), -3},
+		{Bool("k", true), true},
+		{Bool("k", false), false},
+		{Duration("k", time.Second), time.Second},
+		{Time("k", now), now},
+		{Time("k", distant), distant},
+		{Time("k", time.Time{}), time.Time{}},
+		{Err("k", io.EOF), "EOF"},
+		{Err("k", nil), nil},
+		{Any("k", []int{1}), []int{1}},
+		{Any("k", Lazy(func() interface{} { return 1 })), 1},
+	}
+
+	for i, tt := range tests {
+		got := tt.field.Value()
+
+		if want, ok := tt.want.(time.Time); ok {
+			assert.True(t, want.Equal(got.(time.Time)), "test %d", i+1)
+			assert.Equal(t, want.Location().String(), got.(time.Time).Location().String(), "test %d", i+1)
+
+			continue
+		}
+
+		assert.Equal(t, tt.want, got, "test %d", i+1)
+	}
+}
+
+func TestWithFieldList(t *testing.T) {
+	assert.Nil(t, WithFieldList(nil, String("k", "v")))
+
+	err := WithFieldList(Wrap(WithField(io.EOF, "inner", 1), "reading"),
+		String("name", "n"),
+		Int("size", 2),
+		Int("size", 3),
+	)
+
+	assert.Equal(t, "reading: EOF", err.Error())
+	assert.Equal(t, Fields{"inner": 1, "name": "n", "size": 3}, GetFields(err))
+	assert.Contains(t, fmt.Sprintf("%+v", err), "\n  name: n\n  size: 2\n  size: 3\n")
+	assert.Len(t, Unpack(err), 2)
+
+	b, jerr := json.Marshal(err)
+	assert.NoError(t, jerr)
+	assert.Contains(t, string(b), `"fields":{"name":"n","size":3}`)
+
+	text, terr := err.(interface{ MarshalText() ([]byte, error) }).MarshalText()
+	assert.NoError(t, terr)
+	assert.Equal(t, "reading: EOF", string(text))
+}
+
+func TestWithFieldListAllocs(t *testing.T) {
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = WithFieldList(io.EOF, String("name", "n"), Int("size", 2), Bool("ok", true), Duration("d", time.Second))
+	})
+
+	// The field list and the wrapper itself.
+	assert.LessOrEqual(t, allocs, float64(2))
+}
@@ -0,0 +1,64 @@
+package errors
+
+// maxInlineFields is the number of fields of a layer stored without a map.
+const maxInlineFields = 4
+
+type fieldPair struct {
+	key   string
+	value interface{}
+}
+
+// fieldSet stores the fields of a layer: up to maxInlineFields fields are kept
+// in an array, saving the allocation of a map for the common layers annotated
+// with one or two fields, the map only being allocated beyond.
+type fieldSet struct {
+	inline [maxInlineFields]fieldPair
+	n      int
+	m      Fields
+}
+
+// newFieldSet returns a fieldSet holding a copy of fields.
+func newFieldSet(fields Fields) fieldSet {
+	var s fieldSet
+
+	if len(fields) > maxInlineFields {
+		s.m = make(Fields, len(fields))
+
+		for k, v := range fields {
+			s.m[k] = v
+		}
+
+		return s
+	}
+
+	for k, v := range fields {
+		s.inline[s.n] = fieldPair{k, v}
+		s.n++
+	}
+
+	return s
+}
+
+// singleField returns a fieldSet holding the field key.
+func singleField(key string, value interface{}) fieldSet {
+	s := fieldSet{n: 1}
+	s.inline[0] = fieldPair{key, value}
+
+	return s
+}
+
+// toMap returns the fields of s as a map, only built on demand for inline
+// fields.
+func (s *fieldSet) toMap() Fields {
+	if s.m != nil {
+		return s.m
+	}
+
+	out := make(Fields, s.n)
+
+	for _, p := range s.inline[:s.n] {
+		out[p.key] = p.value
+	}
+
+	return out
+}
@@ -0,0 +1,44 @@
+package errors
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFieldSet(t *testing.T) {
+	tests := []struct {
+		name   string
+		fields Fields
+		inline bool
+	}{
+		{"empty", Fields{}, true},
+		{"inline", Fields{"a": 1, "b": "two"}, true},
+		{"full", Fields{"a": 1, "b": 2, "c": 3, "d": 4}, true},
+		{"map", Fields{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5}, false},
+	}
+
+	for _, tt := range tests {
+		tt := tt
+		t.Run(tt.name, func(t *testing.T) {
+			s := newFieldSet(tt.fields)
+
+			assert.Equal(t, tt.inline, s.m == nil)
+			assert.Equal(t, tt.fields, s.toMap())
+		})
+	}
+
+	s := singleField("a", 1)
+	assert.Equal(t, Fields{"a": 1}, s.toMap())
+}
+
+func TestWithFieldsAllocations(t *testing.T) {
+	fields := Fields{"user": "alice", "attempt": "2"}
+
+	allocs := testing.AllocsPerRun(100, func() {
+		_ = WithFields(io.EOF, fields)
+	})
+
+	assert.Equal(t, 1.0, allocs)
+}
@@ -0,0 +1,173 @@
+package errors
+
+import (
+	"path/filepath"
+	"runtime"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// StackFilter reports whether a frame is shown when formatting the stack
+// traces of errors with the %+v verb.
+type StackFilter func(Frame) bool
+
+//nolint:gochecknoglobals // the filter is configured process-wide.
+var stackFilter atomic.Value // StackFilter
+
+//nolint:gochecknoglobals // showing all frames is enabled process-wide.
+var showAllFrames int32
+
+// SetStackFilter sets the filter of the frames printed when formatting errors
+// with the %+v verb, such as HidePackages to leave out middleware packages.
+// The stack traces themselves are kept whole, as returned by StackOf and in the
+// structured encodings.
+// If f is nil, the default ApplicationFrames filter is restored.
+func SetStackFilter(f StackFilter) {
+	if f == nil {
+		f = ApplicationFrames
+	}
+
+	stackFilter.Store(f)
+	formatOptionsChanged()
+}
+
+// ShowAllFrames enables or disables the printing of all the frames of the
+// stack traces, whatever the filter set by SetStackFilter.
+func ShowAllFrames(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&showAllFrames, v)
+	formatOptionsChanged()
+}
+
+// showFrame reports whether f is printed when formatting errors.
+func showFrame(f Frame) bool {
+	if atomic.LoadInt32(&showAllFrames) == 1 {
+		return true
+	}
+
+	filter, _ := stackFilter.Load().(StackFilter)
+	if filter == nil {
+		filter = ApplicationFrames
+	}
+
+	return filter(f)
+}
+
+// ApplicationFrames is the default StackFilter, hiding the frames of the
+// standard library and of vendored packages.
+func ApplicationFrames(f Frame) bool {
+	if strings.Contains(f.File(), "/vendor/") {
+		return false
+	}
+
+	return isApplicationPackage(f.Package(), f.File())
+}
+
+//nolint:gochecknoglobals // computed once, as they never change.
+var (
+	buildOnce   sync.Once
+	gorootSrc   string   // the directory of the sources of the standard library
+	modulePaths []string // the paths of the modules the binary was built from
+)
+
+func loadBuild() {
+	if root := runtime.GOROOT(); root != "" {
+		gorootSrc = filepath.ToSlash(filepath.Join(root, "src")) + "/"
+	}
+
+	info, ok := debug.ReadBuildInfo()
+	if !ok {
+		return
+	}
+
+	if info.Main.Path != "" && info.Main.Path != "command-line-arguments" {
+		modulePaths = append(modulePaths, info.Main.Path)
+	}
+
+	for _, m := range info.Deps {
+		modulePaths = append(modulePaths, m.Path)
+	}
+}
+
+// isApplicationPackage reports whether the package with the import path pkg,
+// whose code is in the source file file, is neither a package of the standard
+// library nor a vendored one.
+// The packages of the standard library are the ones with their sources in
+// GOROOT. When the source files are not known by their absolute path, such as
+// in the binaries built with -trimpath, the packages of the modules listed in
+// the build information of the binary are application ones, and the other
+// ones are told apart by their import path, the first element of the ones of
+// the standard library having no dot.
+func isApplicationPackage(pkg, file string) bool {
+	if pkg == "main" || pkg == "unknown" {
+		return true
+	}
+
+	if strings.Contains(pkg, "/vendor/") {
+		return false
+	}
+
+	buildOnce.Do(loadBuild)
+
+	if gorootSrc != "" && filepath.IsAbs(file) {
+		return !strings.HasPrefix(file, gorootSrc)
+	}
+
+	for _, m := range modulePaths {
+		if pkg == m || strings.HasPrefix(pkg, m+"/") {
+			return true
+		}
+	}
+
+	first := pkg
+	if slash := strings.Index(pkg, "/"); slash >= 0 {
+		first = pkg[:slash]
+	}
+
+	return strings.Contains(first, ".")
+}
+
+// HidePackages returns a StackFilter hiding the frames of the packages whose
+// import path starts with one of prefixes, on top of the ones hidden by
+// ApplicationFrames.
+func HidePackages(prefixes ...string) StackFilter {
+	return func(f Frame) bool {
+		if !ApplicationFrames(f) {
+			return false
+		}
+
+		pkg := f.Package()
+		for _, p := range prefixes {
+			if strings.HasPrefix(pkg, p) {
+				return false
+			}
+		}
+
+		return true
+	}
+}
+
+// AllFrames is a StackFilter showing every frame.
+func AllFrames(Frame) bool {
+	return true
+}
+
+// Visible returns the frames of st shown by the filter set by SetStackFilter,
+// for renderers printing stack traces like the %+v verb does.
+func (st StackTrace) Visible() StackTrace {
+	out := make(StackTrace, 0, len(st))
+
+	for _, f := range st {
+		if showFrame(f) {
+			out = append(out, f)
+		}
+	}
+
+	return out
+}
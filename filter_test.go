@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"fmt"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsApplicationPackage(t *testing.T) {
+	goroot := filepath.ToSlash(runtime.GOROOT())
+
+	tests := []struct {
+		pkg  string
+		file string
+		want bool
+	}{
+		{"runtime", goroot + "/src/runtime/proc.go", false},
+		{"net/http", goroot + "/src/net/http/server.go", false},
+		{"github.com/org/app/vendor/github.com/lib/pq", "/src/app/vendor/github.com/lib/pq/conn.go", false},
+		{"github.com/hexbee-net/errors", "/src/errors/errors.go", true},
+		{"myservice/handlers", "/src/myservice/handlers/user.go", true},
+		{"main", "/src/app/main.go", true},
+		{"unknown", "unknown", true},
+
+		// Trimmed paths.
+		{"runtime", "runtime/proc.go", false},
+		{"net/http", "net/http/server.go", false},
+		{"github.com/hexbee-net/errors", "github.com/hexbee-net/errors/errors.go", true},
+		{"github.com/stretchr/testify/assert", "github.com/stretchr/testify@v1.6.1/assert/assertions.go", true},
+	}
+
+	for _, tt := range tests {
+		if goroot == "" && filepath.IsAbs(tt.file) {
+			// Built with -trimpath: the absolute paths are never seen.
+			continue
+		}
+
+		assert.Equal(t, tt.want, isApplicationPackage(tt.pkg, tt.file), tt.pkg)
+	}
+}
+
+func TestStackFilter(t *testing.T) {
+	requireStacks(t)
+
+	defer SetStackFilter(nil)
+
+	err := New("boom")
+
+	got := fmt.Sprintf("%+v", err)
+	assert.Contains(t, got, "errors.TestStackFilter\n")
+	assert.NotContains(t, got, "testing.tRunner")
+	assert.NotContains(t, got, "runtime.goexit")
+
+	ShowAllFrames(true)
+	assert.Contains(t, fmt.Sprintf("%+v", err), "testing.tRunner")
+	ShowAllFrames(false)
+
+	SetStackFilter(HidePackages("github.com/hexbee-net/errors"))
+	assert.Equal(t, "boom", fmt.Sprintf("%+v", err))
+
+	SetStackFilter(AllFrames)
+	assert.Contains(t, fmt.Sprintf("%+v", err), "runtime.goexit")
+
+	SetStackFilter(nil)
+	assert.NotContains(t, fmt.Sprintf("%+v", err), "testing.tRunner")
+}
@@ -0,0 +1,80 @@
+package errors
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// fingerprintFrames is the number of application frames in a fingerprint.
+const fingerprintFrames = 3
+
+// fingerprintSize is the number of bytes of a fingerprint.
+const fingerprintSize = 16
+
+//nolint:gochecknoglobals // immutable patterns.
+var (
+	quotedPattern = regexp.MustCompile(`"(?:[^"\\]|\\.)*"|'(?:[^'\\]|\\.)*'`)
+	uuidPattern   = regexp.MustCompile(`(?i)\b[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12}\b`)
+	hexPattern    = regexp.MustCompile(`(?i)\b(?:0x[0-9a-f]+|[0-9a-f]{8,})\b`)
+	numberPattern = regexp.MustCompile(`[0-9]+(?:\.[0-9]+)?`)
+)
+
+// Fingerprint returns a stable hash of err for grouping similar errors, such
+// as in dashboards: these share a fingerprint when they come from the same
+// kind of failure at the same place, whatever the values they mention.
+// The fingerprint is computed from the type of the origin of the chain, the
+// code of the error, the messages of its layers with their variable parts, such
+// as quoted strings, numbers and identifiers, replaced by placeholders, and the
+// top application frames of the stack trace recorded closest to the origin.
+// Line numbers are left out to keep fingerprints stable across releases.
+// If err is nil, an empty string will be returned.
+func Fingerprint(err error) string {
+	if err == nil {
+		return ""
+	}
+
+	h := sha256.New()
+
+	_, _ = fmt.Fprintf(h, "type:%T\ncode:%s\n", Cause(err), GetCode(err))
+
+	for _, layer := range GetFieldsByLayer(err) {
+		_, _ = fmt.Fprintf(h, "message:%s\n", messageTemplate(layer.Message))
+	}
+
+	if st, ok := StackOf(err); ok {
+		n := 0
+
+		for _, f := range st {
+			if n == fingerprintFrames {
+				break
+			}
+
+			if ApplicationFrames(f) {
+				_, _ = fmt.Fprintf(h, "frame:%s\n", f.Function())
+				n++
+			}
+		}
+	}
+
+	return hex.EncodeToString(h.Sum(nil)[:fingerprintSize])
+}
+
+// messageTemplate returns msg with its variable parts replaced by
+// placeholders.
+func messageTemplate(msg string) string {
+	msg = quotedPattern.ReplaceAllString(msg, `"?"`)
+	msg = uuidPattern.ReplaceAllString(msg, "<uuid>")
+	msg = hexPattern.ReplaceAllStringFunc(msg, func(s string) string {
+		// Keep the words made of hex letters only, such as "accede".
+		if !strings.ContainsAny(s, "0123456789") {
+			return s
+		}
+
+		return "<hex>"
+	})
+
+	return numberPattern.ReplaceAllString(msg, "<n>")
+}
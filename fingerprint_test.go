@@ -0,0 +1,45 @@
+package errors
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMessageTemplate(t *testing.T) {
+	tests := []struct {
+		msg  string
+		want string
+	}{
+		{"user 42 not found", "user <n> not found"},
+		{`opening "a.txt": permission denied`, `opening "?": permission denied`},
+		{"request 3f2a9c1e-77b0-4c1d-9a55-0e1f2d3c4b5a timed out after 1.5s", "request <uuid> timed out after <n>s"},
+		{"bad pointer 0xc000012345", "bad pointer <hex>"},
+		{"sha deadbeef1 mismatch", "sha <hex> mismatch"},
+		{"connection refused", "connection refused"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, messageTemplate(tt.msg), tt.msg)
+	}
+}
+
+func TestFingerprint(t *testing.T) {
+	newErr := func(id int) error {
+		return WithCode(Wrapf(io.EOF, "loading user %d", id), "user_load")
+	}
+
+	a, b := newErr(1), newErr(2)
+
+	assert.Len(t, Fingerprint(a), 2*fingerprintSize)
+	assert.Equal(t, Fingerprint(a), Fingerprint(b))
+	assert.NotEqual(t, Fingerprint(a), Fingerprint(Wrapf(io.EOF, "loading user %d", 1)))
+	assert.NotEqual(t, Fingerprint(a), Fingerprint(WithCode(Wrapf(io.ErrUnexpectedEOF, "loading user %d", 1), "user_load")))
+
+	if stacksCompiled {
+		assert.NotEqual(t, Fingerprint(New("boom")), Fingerprint(func() error { return New("boom") }()))
+	}
+
+	assert.Empty(t, Fingerprint(nil))
+}
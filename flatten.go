@@ -0,0 +1,152 @@
+package errors
+
+import "reflect"
+
+// FlattenOption configures Flatten.
+type FlattenOption func(*flattenOptions)
+
+type flattenOptions struct {
+	dedupe bool
+}
+
+// FlattenDeduplicated leaves out of Flatten the errors equal to an error
+// already returned, such as the same sentinel reported by several tasks.
+// Errors of types that cannot be compared are always kept.
+func FlattenDeduplicated() FlattenOption {
+	return func(o *flattenOptions) { o.dedupe = true }
+}
+
+// Flatten returns the errors aggregated by err, recursively, as a flat slice,
+// for code iterating the failures one by one.
+// The aggregates are found down the chain of err and of their members, like
+// the errors joined by errors.Join or accumulated by Append, and are replaced
+// by their members: the annotations of the layers wrapping an aggregate are
+// not kept. The chains are followed like in Leaves, through both the Cause
+// and the Unwrap methods.
+// If err aggregates nothing, Flatten returns a slice holding err.
+// If err is nil, an empty slice will be returned.
+func Flatten(err error, opts ...FlattenOption) []error {
+	var o flattenOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	out := appendLeaves(make([]error, 0), err, 0)
+
+	if o.dedupe {
+		out = deduplicate(out)
+	}
+
+	return out
+}
+
+// appendLeaves appends to dst the errors aggregated by err, or err itself if
+// it aggregates nothing, depth being the number of aggregates above err.
+func appendLeaves(dst []error, err error, depth int) []error {
+	if err == nil {
+		return dst
+	}
+
+	for e, layer := err, 0; e != nil && layer < MaxChainDepth(); e, layer = unwrapOnce(e), layer+1 {
+		ms, ok := aggregated(e)
+		if !ok {
+			continue
+		}
+
+		if depth == MaxChainDepth() {
+			return dst
+		}
+
+		for _, m := range ms {
+			dst = appendLeaves(dst, m, depth+1)
+		}
+
+		return dst
+	}
+
+	return append(dst, err)
+}
+
+// Leaves returns the root causes of err, the errors at the bottom of its
+// chain or of the chains of the errors it aggregates, recursively, so that
+// classification code can inspect the underlying failures only.
+// The chains are followed through both the Cause method of the errors of this
+// package and the Unwrap method of the errors of the standard library, the
+// annotation layers being skipped.
+// If err is nil, an empty slice will be returned.
+func Leaves(err error) []error {
+	return appendRoots(make([]error, 0), err, 0)
+}
+
+// appendRoots appends to dst the root causes of err, depth being the number
+// of aggregates above err.
+func appendRoots(dst []error, err error, depth int) []error {
+	for layer := 0; err != nil && layer < MaxChainDepth(); layer++ {
+		if ms, ok := aggregated(err); ok {
+			if depth == MaxChainDepth() {
+				return dst
+			}
+
+			for _, m := range ms {
+				dst = appendRoots(dst, m, depth+1)
+			}
+
+			return dst
+		}
+
+		next := unwrapOnce(err)
+		if next == nil {
+			return append(dst, err)
+		}
+
+		err = next
+	}
+
+	return dst
+}
+
+// unwrapOnce returns the error wrapped by err, if any.
+func unwrapOnce(err error) error {
+	switch v := err.(type) {
+	case causer:
+		return v.Cause()
+	case interface{ Unwrap() error }:
+		return v.Unwrap()
+	}
+
+	return nil
+}
+
+// aggregated returns the errors aggregated by err, such as with an
+// `Unwrap() []error` method like the errors of errors.Join and MultiError, or
+// an `Errors() []error` one like the errors of go-multierror.
+func aggregated(err error) ([]error, bool) {
+	switch v := err.(type) {
+	case interface{ Unwrap() []error }:
+		return v.Unwrap(), true
+	case interface{ Errors() []error }:
+		return v.Errors(), true
+	}
+
+	return nil, false
+}
+
+// deduplicate returns errs without the errors equal to a previous one.
+func deduplicate(errs []error) []error {
+	seen := make(map[error]struct{}, len(errs))
+	out := errs[:0]
+
+	for _, err := range errs {
+		if reflect.TypeOf(err).Comparable() {
+			if _, dup := seen[err]; dup {
+				continue
+			}
+
+			seen[err] = struct{}{}
+		}
+
+		out = append(out, err)
+	}
+
+	return out
+}
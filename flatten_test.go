@@ -0,0 +1,76 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// errorsList aggregates errors like go-multierror does.
+type errorsList []error
+
+func (l errorsList) Error() string { return "list" }
+
+func (l errorsList) Errors() []error { return l }
+
+// uncomparableError is an error that cannot be compared.
+type uncomparableError []string
+
+func (e uncomparableError) Error() string { return "uncomparable" }
+
+func TestFlatten(t *testing.T) {
+	leaf := WithFields(io.ErrClosedPipe, Fields{"k": "v"})
+
+	err := Wrap(errors.Join(
+		io.EOF,
+		Append(nil, io.ErrUnexpectedEOF, WithStack(errors.Join(leaf, nil))),
+		errorsList{io.EOF, io.ErrShortWrite},
+	), "closing")
+
+	tests := []struct {
+		name string
+		err  error
+		opts []FlattenOption
+		want []error
+	}{
+		{"nil", nil, nil, []error{}},
+		{"leaf", leaf, nil, []error{leaf}},
+		{"wrapped", fmt.Errorf("closing: %w", Append(io.EOF, leaf)), nil, []error{io.EOF, leaf}},
+		{"tree", err, nil, []error{io.EOF, io.ErrUnexpectedEOF, leaf, io.EOF, io.ErrShortWrite}},
+		{"deduplicated", err, []FlattenOption{FlattenDeduplicated()}, []error{io.EOF, io.ErrUnexpectedEOF, leaf, io.ErrShortWrite}},
+		{
+			"uncomparable",
+			errorsList{uncomparableError{}, uncomparableError{}},
+			[]FlattenOption{FlattenDeduplicated()},
+			[]error{uncomparableError{}, uncomparableError{}},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Flatten(tt.err, tt.opts...))
+		})
+	}
+}
+
+func TestLeaves(t *testing.T) {
+	leaf := WithFields(io.ErrClosedPipe, Fields{"k": "v"})
+
+	err := Wrap(errors.Join(
+		fmt.Errorf("reading: %w", WithStack(io.EOF)),
+		Append(nil, io.ErrUnexpectedEOF, WithStack(errors.Join(leaf, nil))),
+		errorsList{New("origin")},
+	), "closing")
+
+	leaves := Leaves(err)
+	if assert.Len(t, leaves, 4) {
+		assert.Equal(t, []error{io.EOF, io.ErrUnexpectedEOF, io.ErrClosedPipe}, leaves[:3])
+		assert.Equal(t, "origin", leaves[3].Error())
+	}
+
+	assert.Equal(t, []error{}, Leaves(nil))
+	assert.Equal(t, []error{io.EOF}, Leaves(Wrap(io.EOF, "wrapped")))
+}
@@ -0,0 +1,102 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"sync/atomic"
+)
+
+// Formatter renders an error chain for the %+v verb, such as a JSON document
+// in production or a colorized tree during development.
+type Formatter func(w io.Writer, err error)
+
+//nolint:gochecknoglobals // the formatter is configured process-wide.
+var formatter atomic.Value // Formatter
+
+// SetFormatter replaces the rendering of the %+v verb by f for every error of
+// this package, the other verbs keeping their behavior.
+// f is given the error being formatted, the outermost layer of the chain, and
+// may call FormatDefault to render the chain the default way.
+// If f is nil, the default rendering is restored.
+func SetFormatter(f Formatter) {
+	formatter.Store(f)
+}
+
+// FormatDefault writes the default %+v rendering of err to w, whatever the
+// Formatter set by SetFormatter.
+func FormatDefault(w io.Writer, err error) {
+	formatCause(verboseState{w: w}, err)
+}
+
+// customFormat renders err with the Formatter set by SetFormatter, unless
+// there is none or s is the state of an inner layer of the chain, and
+// reports whether it did.
+func customFormat(s fmt.State, err error) bool {
+	if _, nested := s.(verboseState); nested {
+		return false
+	}
+
+	f, _ := formatter.Load().(Formatter)
+	if f == nil {
+		return false
+	}
+
+	f(s, err)
+
+	return true
+}
+
+// formatCause prints err with the %+v verb as an inner layer of the chain
+// being formatted to s.
+// Past MaxChainDepth layers, a note telling the chain was truncated is printed
+// instead.
+func formatCause(s io.Writer, err error) {
+	st := verbose(s)
+	st.depth++
+	if st.depth > MaxChainDepth() {
+		formatTruncated(st)
+
+		return
+	}
+
+	f, ok := err.(fmt.Formatter)
+	if !ok {
+		if members := joined(err); members != nil {
+			formatTree(st, err, members)
+
+			return
+		}
+
+		_, _ = fmt.Fprintf(st, "%+v", err)
+
+		return
+	}
+
+	f.Format(st, 'v')
+}
+
+// verbose returns s as the state of a layer of a chain formatted with the %+v
+// verb.
+func verbose(s io.Writer) verboseState {
+	if st, ok := s.(verboseState); ok {
+		return st
+	}
+
+	// s is the state of the outermost layer.
+	return verboseState{w: s, depth: 1}
+}
+
+// verboseState is the fmt.State of the inner layers of a chain formatted with
+// the %+v verb.
+type verboseState struct {
+	w     io.Writer
+	depth int
+}
+
+func (s verboseState) Write(b []byte) (int, error) { return s.w.Write(b) }
+
+func (verboseState) Width() (int, bool) { return 0, false }
+
+func (verboseState) Precision() (int, bool) { return 0, false }
+
+func (verboseState) Flag(c int) bool { return c == '+' }
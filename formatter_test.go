@@ -0,0 +1,61 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetFormatter(t *testing.T) {
+	defer SetFormatter(nil)
+
+	err := WithFields(Wrap(New("inner"), "outer"), Fields{"a": 1})
+	want := fmt.Sprintf("%+v", err)
+
+	var calls int
+
+	SetFormatter(func(w io.Writer, err error) {
+		calls++
+
+		_, _ = fmt.Fprintf(w, "custom(%s)", err)
+	})
+
+	assert.Equal(t, "custom(outer: inner)", fmt.Sprintf("%+v", err))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, "outer: inner", fmt.Sprintf("%v", err))
+	assert.Equal(t, "outer: inner", fmt.Sprintf("%s", err))
+
+	var b strings.Builder
+
+	FormatDefault(&b, err)
+	assert.Equal(t, want, b.String())
+
+	SetFormatter(nil)
+	assert.Equal(t, want, fmt.Sprintf("%+v", err))
+}
+
+func TestSetFormatterTypes(t *testing.T) {
+	defer SetFormatter(nil)
+
+	SetFormatter(func(w io.Writer, err error) {
+		_, _ = io.WriteString(w, "custom")
+	})
+
+	for _, err := range []error{
+		New("new"),
+		WithStack(io.EOF),
+		WithMessage(io.EOF, "message"),
+		WithFields(io.EOF, Fields{"a": 1}),
+		WithFieldList(io.EOF, Int("a", 1)),
+		WithKind(io.EOF, KindNotFound),
+		WithCode(io.EOF, "code"),
+		WithHTTPStatus(io.EOF, http.StatusNotFound),
+		WithUserMessage(io.EOF, "user"),
+	} {
+		assert.Equal(t, "custom", fmt.Sprintf("%+v", err), err.Error())
+	}
+}
@@ -0,0 +1,221 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Frame is a location in a program, captured the same way as the frames of
+// the stack traces recorded by this package.
+// Its value is a program counter as returned by runtime.Callers, like the
+// frames of pkg/errors.
+type Frame uintptr
+
+// Caller returns the frame of the caller of the function calling Caller,
+// skip frames up: Caller(0) is the location Caller is called from.
+// If there is no such frame, the zero Frame will be returned.
+func Caller(skip int) Frame {
+	const skipCaller = 2 // runtime.Callers and Caller
+
+	var pcs [1]uintptr
+
+	if runtime.Callers(skip+skipCaller, pcs[:]) == 0 {
+		return 0
+	}
+
+	return Frame(pcs[0])
+}
+
+//nolint:gochecknoglobals // the program counters of a process never change.
+var frameCache sync.Map // Frame -> runtime.Frame
+
+// frame returns the resolved location of f.
+// Locations are symbolized once per program counter, as the frames of the
+// printed stack traces are looked up several times by the stack filter and
+// the formatting of the frames themselves.
+func (f Frame) frame() runtime.Frame {
+	if f == 0 {
+		return runtime.Frame{}
+	}
+
+	if fr, ok := frameCache.Load(f); ok {
+		return fr.(runtime.Frame)
+	}
+
+	fr, _ := runtime.CallersFrames([]uintptr{uintptr(f)}).Next()
+	frameCache.Store(f, fr)
+
+	return fr
+}
+
+// File returns the full path to the file of the location.
+// If the location is unknown, "unknown" will be returned.
+func (f Frame) File() string {
+	if file := f.frame().File; file != "" {
+		return file
+	}
+
+	return "unknown"
+}
+
+// Line returns the line number of the location.
+// If the location is unknown, 0 will be returned.
+func (f Frame) Line() int {
+	return f.frame().Line
+}
+
+// Func returns the name of the function of the location without its package,
+// such as "(*Type).Method".
+// If the location is unknown, "unknown" will be returned.
+func (f Frame) Func() string {
+	name := f.frame().Function
+	if name == "" {
+		return "unknown"
+	}
+
+	return strings.TrimPrefix(name, funcPackage(name)+".")
+}
+
+// Package returns the import path of the package of the location.
+// If the location is unknown, "unknown" will be returned.
+func (f Frame) Package() string {
+	name := f.frame().Function
+	if name == "" {
+		return "unknown"
+	}
+
+	return funcPackage(name)
+}
+
+// Format formats the frame like the frames of the stack traces of this
+// package:
+//
+//     %s    source file
+//     %d    source line
+//     %n    function name
+//     %v    equivalent to %s:%d
+//     %+v   fully qualified function name and full path of source file
+func (f Frame) Format(s fmt.State, verb rune) {
+	errors.Frame(f).Format(s, verb)
+}
+
+// funcPackage extracts the import path from a fully qualified function name
+// such as "github.com/org/repo/pkg.(*Type).Method".
+func funcPackage(name string) string {
+	slash := strings.LastIndex(name, "/")
+
+	if dot := strings.Index(name[slash+1:], "."); dot >= 0 {
+		return name[:slash+1+dot]
+	}
+
+	return name
+}
+
+// Function returns the fully qualified name of the function of the location,
+// such as "github.com/org/repo/pkg.(*Type).Method".
+// If the location is unknown, "unknown" will be returned.
+func (f Frame) Function() string {
+	if name := f.frame().Function; name != "" {
+		return name
+	}
+
+	return "unknown"
+}
+
+// PC returns the program counter of the location.
+// If the location is unknown, 0 will be returned.
+func (f Frame) PC() uintptr {
+	return f.frame().PC
+}
+
+// StackTrace is a stack of frames, from the innermost call to the outermost.
+type StackTrace []Frame
+
+// Format formats the stack like the stack traces of this package, the verbs
+// applying to every frame:
+//
+//     %s    lists the source files of the stack
+//     %v    lists the source file and line of every frame
+//     %+v   prints the function name and full path of every frame, one per line
+func (st StackTrace) Format(s fmt.State, verb rune) {
+	st.pkgStackTrace().Format(s, verb)
+}
+
+// PCs returns the program counters of the stack, as returned by
+// runtime.Callers.
+func (st StackTrace) PCs() []uintptr {
+	pcs := make([]uintptr, len(st))
+	for i, f := range st {
+		pcs[i] = uintptr(f)
+	}
+
+	return pcs
+}
+
+// Frames resolves the stack into runtime frames, inlined calls included, for
+// tools working with the frames of the runtime package.
+func (st StackTrace) Frames() []runtime.Frame {
+	if len(st) == 0 {
+		return nil
+	}
+
+	out := make([]runtime.Frame, 0, len(st))
+	frames := runtime.CallersFrames(st.PCs())
+
+	for {
+		f, more := frames.Next()
+		out = append(out, f)
+
+		if !more {
+			break
+		}
+	}
+
+	return out
+}
+
+// MarshalJSON implements json.Marshaler, encoding the stack as an array of
+// frames in the representation used by the structured encodings of errors:
+//
+//     [{"function": "main.main", "file": "/src/main.go", "line": 12}]
+func (st StackTrace) MarshalJSON() ([]byte, error) {
+	frames := resolve(st.pkgStackTrace())
+	if frames == nil {
+		frames = make([]StackFrame, 0)
+	}
+
+	return json.Marshal(frames)
+}
+
+func (st StackTrace) pkgStackTrace() errors.StackTrace {
+	out := make(errors.StackTrace, len(st))
+	for i, f := range st {
+		out[i] = errors.Frame(f)
+	}
+
+	return out
+}
+
+// StackOf returns the stack trace recorded closest to the origin of the chain
+// of err, that is where the error was created or first wrapped.
+// Only the stack traces recorded in this process are considered: the ones of
+// errors decoded from another process only come as resolved StackFrame values.
+// If no layer of the chain carries a stack trace, StackOf returns false.
+func StackOf(err error) (StackTrace, bool) {
+	pst := deepestStack(err)
+	if len(pst) == 0 {
+		return nil, false
+	}
+
+	st := make(StackTrace, len(pst))
+	for i, f := range pst {
+		st[i] = Frame(f)
+	}
+
+	return st, true
+}
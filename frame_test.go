@@ -0,0 +1,118 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"runtime"
+	"testing"
+
+	pkgerrors "github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type frameRecorder struct{}
+
+func (frameRecorder) record() Frame { return Caller(1) }
+
+func TestCaller(t *testing.T) {
+	f := Caller(0)
+
+	assert.Regexp(t, `/frame_test.go$`, f.File())
+	assert.NotZero(t, f.Line())
+	assert.Equal(t, "TestCaller", f.Func())
+	assert.Equal(t, "github.com/hexbee-net/errors", f.Package())
+	assert.Equal(t, fmt.Sprintf("frame_test.go:%d", f.Line()), fmt.Sprintf("%v", f))
+	assert.Regexp(t, regexp.MustCompile(`^github.com/hexbee-net/errors.TestCaller\n\t.+/frame_test.go:\d+$`), fmt.Sprintf("%+v", f))
+
+	g := func() Frame { return frameRecorder{}.record() }()
+	assert.Equal(t, "TestCaller.func1", g.Func())
+}
+
+func TestCallerUnknown(t *testing.T) {
+	f := Caller(1000)
+
+	assert.Equal(t, Frame(0), f)
+	assert.Equal(t, "unknown", f.File())
+	assert.Equal(t, 0, f.Line())
+	assert.Equal(t, "unknown", f.Func())
+	assert.Equal(t, "unknown", f.Package())
+}
+
+func TestFrameFunction(t *testing.T) {
+	f := Caller(0)
+
+	assert.Equal(t, "github.com/hexbee-net/errors.TestFrameFunction", f.Function())
+	assert.NotZero(t, f.PC())
+	assert.Equal(t, "unknown", Frame(0).Function())
+	assert.Zero(t, Frame(0).PC())
+}
+
+func TestStackOf(t *testing.T) {
+	requireStacks(t)
+
+	_, ok := StackOf(io.EOF)
+	assert.False(t, ok)
+
+	err := New("boom")
+
+	st, ok := StackOf(Wrap(err, "saving"))
+	assert.True(t, ok)
+
+	inner, _ := StackOf(err)
+	assert.Equal(t, inner, st)
+	assert.Equal(t, "github.com/hexbee-net/errors.TestStackOf", st[0].Function())
+	assert.Len(t, st.PCs(), len(st))
+	assert.Equal(t, uintptr(st[0]), st.PCs()[0])
+
+	assert.Equal(t, fmt.Sprintf("%+v", err.(interface{ StackTrace() pkgerrors.StackTrace }).StackTrace()), fmt.Sprintf("%+v", st))
+	assert.Regexp(t, `^\[frame_test.go:\d+ `, fmt.Sprintf("%v", st))
+}
+
+func TestStackTraceFrames(t *testing.T) {
+	requireStacks(t)
+
+	assert.Nil(t, StackTrace(nil).Frames())
+
+	st, _ := StackOf(New("boom"))
+	frames := st.Frames()
+
+	if assert.NotEmpty(t, frames) {
+		assert.Equal(t, "github.com/hexbee-net/errors.TestStackTraceFrames", frames[0].Function)
+		assert.Equal(t, st[0].File(), frames[0].File)
+		assert.Equal(t, st[0].Line(), frames[0].Line)
+	}
+}
+
+func TestStackTraceMarshalJSON(t *testing.T) {
+	requireStacks(t)
+
+	b, err := json.Marshal(StackTrace(nil))
+	assert.NoError(t, err)
+	assert.Equal(t, "[]", string(b))
+
+	e := New("boom")
+	st, _ := StackOf(e)
+
+	b, err = json.Marshal(st)
+	assert.NoError(t, err)
+
+	var frames []StackFrame
+
+	assert.NoError(t, json.Unmarshal(b, &frames))
+	assert.Equal(t, NewDocument(e).Layers[0].Stack, frames)
+	assert.Contains(t, string(b), `{"function":"github.com/hexbee-net/errors.TestStackTraceMarshalJSON","file":"`)
+}
+
+func TestFrameCache(t *testing.T) {
+	f := Caller(0)
+	frameCache.Delete(f)
+
+	assert.Equal(t, "TestFrameCache", f.Func())
+
+	fr, cached := frameCache.Load(f)
+	if assert.True(t, cached) {
+		assert.Equal(t, "github.com/hexbee-net/errors.TestFrameCache", fr.(runtime.Frame).Function)
+	}
+}
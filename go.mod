@@ -1,9 +1,15 @@
 module github.com/hexbee-net/errors
 
-go 1.14
+go 1.20
 
 require (
 	github.com/apex/log v1.9.0
 	github.com/pkg/errors v0.9.1
 	github.com/stretchr/testify v1.6.1
+	gopkg.in/yaml.v3 v3.0.1
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
 )
@@ -0,0 +1,255 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/gob"
+)
+
+//nolint:gochecknoinits // gob needs the concrete types registered before any error is sent as an interface value.
+func init() {
+	gob.Register(&fundamental{})
+	gob.Register(&withStack{})
+	gob.Register(&withMessage{})
+	gob.Register(&withFields{})
+	gob.Register(&withFieldList{})
+	gob.Register(&withKind{})
+	gob.Register(&withCode{})
+	gob.Register(&withUserMessage{})
+	gob.Register(&withHTTPStatus{})
+	gob.Register(&RemoteError{})
+}
+
+// gobEncode encodes the structured representation of err.
+// Field values that are not of a basic type are replaced by their default format.
+func gobEncode(err error) ([]byte, error) {
+	doc := NewDocument(err)
+
+	for i := range doc.Layers {
+		for k, v := range doc.Layers[i].Fields {
+			if !isGobBasic(v) {
+				doc.Layers[i].Fields[k] = FormatField(k, v)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(doc); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// gobDecode decodes a document encoded by gobEncode.
+func gobDecode(data []byte) (Document, error) {
+	var doc Document
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(&doc); err != nil {
+		return doc, Wrap(err, "decoding error")
+	}
+
+	if len(doc.Layers) == 0 {
+		return doc, New("decoding error: no layer")
+	}
+
+	return doc, nil
+}
+
+// isGobBasic reports whether v is of a type gob registers by default.
+func isGobBasic(v interface{}) bool {
+	switch v.(type) {
+	case bool, string, []byte,
+		int, int8, int16, int32, int64,
+		uint, uint8, uint16, uint32, uint64, uintptr,
+		float32, float64, complex64, complex128,
+		[]bool, []string,
+		[]int, []int8, []int16, []int32, []int64,
+		[]uint, []uint16, []uint32, []uint64, []uintptr,
+		[]float32, []float64, []complex64, []complex128:
+		return true
+	default:
+		return false
+	}
+}
+
+// GobEncode implements gob.GobEncoder.
+func (f *fundamental) GobEncode() ([]byte, error) { return gobEncode(f) }
+
+// GobDecode implements gob.GobDecoder.
+func (f *fundamental) GobDecode(data []byte) error {
+	doc, err := gobDecode(data)
+	if err != nil {
+		return err
+	}
+
+	f.msg = doc.Layers[0].Message
+	f.stack = decodedStack(doc.Layers[0].Stack)
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder.
+func (w *withStack) GobEncode() ([]byte, error) { return gobEncode(w) }
+
+// GobDecode implements gob.GobDecoder.
+// The layers below the stack trace are decoded as RemoteError values.
+func (w *withStack) GobDecode(data []byte) error {
+	doc, err := gobDecode(data)
+	if err != nil {
+		return err
+	}
+
+	w.stack = decodedStack(doc.Layers[0].Stack)
+	doc.Layers[0].Stack = nil
+	w.error = FromDocument(doc)
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder.
+func (w *withMessage) GobEncode() ([]byte, error) { return gobEncode(w) }
+
+// GobDecode implements gob.GobDecoder.
+// The cause is decoded as a RemoteError.
+func (w *withMessage) GobDecode(data []byte) error {
+	doc, err := gobDecode(data)
+	if err != nil {
+		return err
+	}
+
+	if len(doc.Layers) < 2 { //nolint:gomnd // the message and its cause
+		return New("decoding error: missing cause")
+	}
+
+	w.msg = doc.Layers[0].Message
+	w.cause = FromDocument(Document{Layers: doc.Layers[1:]})
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder.
+func (w *withFields) GobEncode() ([]byte, error) { return gobEncode(w) }
+
+// GobDecode implements gob.GobDecoder.
+// The cause is decoded as a RemoteError.
+func (w *withFields) GobDecode(data []byte) error {
+	doc, err := gobDecode(data)
+	if err != nil {
+		return err
+	}
+
+	w.fields = newFieldSet(doc.Layers[0].Fields)
+	doc.Layers[0].Fields = nil
+	w.cause = FromDocument(doc)
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder.
+func (w *withFieldList) GobEncode() ([]byte, error) { return gobEncode(w) }
+
+// GobDecode implements gob.GobDecoder.
+// The fields are decoded as Any fields and the cause as a RemoteError.
+func (w *withFieldList) GobDecode(data []byte) error {
+	doc, err := gobDecode(data)
+	if err != nil {
+		return err
+	}
+
+	w.fields = make([]Field, 0, len(doc.Layers[0].Fields))
+
+	for k, v := range doc.Layers[0].Fields {
+		w.fields = append(w.fields, Any(k, v))
+	}
+
+	doc.Layers[0].Fields = nil
+	w.cause = FromDocument(doc)
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder.
+func (w *withKind) GobEncode() ([]byte, error) { return gobEncode(w) }
+
+// GobDecode implements gob.GobDecoder.
+// The cause is decoded as a RemoteError.
+func (w *withKind) GobDecode(data []byte) error {
+	doc, err := gobDecode(data)
+	if err != nil {
+		return err
+	}
+
+	w.kind = doc.Layers[0].Kind
+	doc.Layers[0].Kind = ""
+	w.cause = FromDocument(doc)
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder.
+func (w *withCode) GobEncode() ([]byte, error) { return gobEncode(w) }
+
+// GobDecode implements gob.GobDecoder.
+// The cause is decoded as a RemoteError.
+func (w *withCode) GobDecode(data []byte) error {
+	doc, err := gobDecode(data)
+	if err != nil {
+		return err
+	}
+
+	w.code = doc.Layers[0].Code
+	doc.Layers[0].Code = ""
+	w.cause = FromDocument(doc)
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder.
+func (w *withUserMessage) GobEncode() ([]byte, error) { return gobEncode(w) }
+
+// GobDecode implements gob.GobDecoder.
+// The cause is decoded as a RemoteError.
+func (w *withUserMessage) GobDecode(data []byte) error {
+	doc, err := gobDecode(data)
+	if err != nil {
+		return err
+	}
+
+	w.msg = doc.Layers[0].UserMessage
+	doc.Layers[0].UserMessage = ""
+	w.cause = FromDocument(doc)
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder.
+func (w *withHTTPStatus) GobEncode() ([]byte, error) { return gobEncode(w) }
+
+// GobDecode implements gob.GobDecoder.
+// The cause is decoded as a RemoteError.
+func (w *withHTTPStatus) GobDecode(data []byte) error {
+	doc, err := gobDecode(data)
+	if err != nil {
+		return err
+	}
+
+	w.status = doc.Layers[0].Status
+	doc.Layers[0].Status = 0
+	w.cause = FromDocument(doc)
+
+	return nil
+}
+
+// GobEncode implements gob.GobEncoder.
+func (r *RemoteError) GobEncode() ([]byte, error) { return gobEncode(r) }
+
+// GobDecode implements gob.GobDecoder.
+func (r *RemoteError) GobDecode(data []byte) error {
+	doc, err := gobDecode(data)
+	if err != nil {
+		return err
+	}
+
+	*r = *FromDocument(doc).(*RemoteError)
+
+	return nil
+}
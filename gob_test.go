@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type gobEnvelope struct {
+	Err error
+}
+
+func gobRoundTrip(t *testing.T, err error) error {
+	t.Helper()
+
+	var buf bytes.Buffer
+
+	assert.NoError(t, gob.NewEncoder(&buf).Encode(gobEnvelope{err}))
+
+	var got gobEnvelope
+
+	assert.NoError(t, gob.NewDecoder(&buf).Decode(&got))
+
+	return got.Err
+}
+
+func TestGob(t *testing.T) {
+	errs := []error{
+		New("boom"),
+		WithStack(io.EOF),
+		Wrap(New("boom"), "read error"),
+		WithMessage(WithField(io.EOF, "inner", 1), "read error"),
+		WithFields(Wrap(WithField(io.EOF, "inner", 1), "read error"), Fields{"outer": "2", "chan": make(chan int)}),
+		WithFieldList(Wrap(io.EOF, "read error"), String("name", "n"), Int("size", 2)),
+		WithKind(Wrap(io.EOF, "read error"), KindNotFound),
+		WithCode(WithKind(io.EOF, KindNotFound), "file.missing"),
+		WithUserMessage(Wrap(io.EOF, "read error"), "Please retry."),
+		WithHTTPStatus(WithUserMessage(io.EOF, "Please retry."), http.StatusTeapot),
+		FromDocument(Document{Layers: []Layer{{Message: "outer", Code: "code"}, {Message: "inner"}}}),
+	}
+
+	for i, err := range errs {
+		got := gobRoundTrip(t, err)
+
+		assert.IsType(t, err, got, "test %d", i+1)
+		assert.Equal(t, err.Error(), got.Error(), "test %d", i+1)
+		assert.Equal(t, len(GetFields(err)), len(GetFields(got)), "test %d", i+1)
+		assert.Equal(t, GetKind(err), GetKind(got), "test %d", i+1)
+		assert.Equal(t, GetCode(err), GetCode(got), "test %d", i+1)
+		assert.Equal(t, GetUserMessage(err), GetUserMessage(got), "test %d", i+1)
+		assert.Equal(t, HTTPStatus(err), HTTPStatus(got), "test %d", i+1)
+
+		want, e := ToJSON(WithMessage(err, "check"))
+		assert.NoError(t, e)
+
+		again, e := ToJSON(WithMessage(got, "check"))
+		assert.NoError(t, e)
+
+		if _, ok := GetFields(err)["chan"]; !ok {
+			assert.JSONEq(t, string(want), string(again), "test %d", i+1)
+		}
+	}
+}
+
+func TestGobStack(t *testing.T) {
+	requireStacks(t)
+
+	got := gobRoundTrip(t, Wrap(New("boom"), "read error"))
+
+	verbose := fmt.Sprintf("%+v", got)
+	assert.Contains(t, verbose, "boom\ngithub.com/hexbee-net/errors.TestGobStack\n\t")
+	assert.Contains(t, verbose, "\nread error\ngithub.com/hexbee-net/errors.TestGobStack\n\t")
+}
+
+func TestGobDecodeInvalid(t *testing.T) {
+	var f fundamental
+
+	assert.Error(t, f.GobDecode([]byte("invalid")))
+
+	data, err := gobEncode(io.EOF)
+	assert.NoError(t, err)
+
+	var w withMessage
+
+	assert.Error(t, w.GobDecode(data))
+}
@@ -0,0 +1,39 @@
+package errors
+
+// Go runs fn in a new goroutine and returns a channel receiving its error, or
+// the one describing its panic as by Recover, before being closed, so that
+// background tasks neither crash the process nor lose their failures:
+//
+//     done := errors.Go(func() error { return sync(ctx) })
+//
+//     ...
+//
+//     if err := <-done; err != nil {
+//            log.WithError(err).Error("sync failed")
+//     }
+//
+// The channel is buffered, so the goroutine returns even if it is never read.
+func Go(fn func() error) <-chan error {
+	ch := make(chan error, 1)
+
+	go func() {
+		defer close(ch)
+
+		ch <- runTask(fn)
+	}()
+
+	return ch
+}
+
+// GoFunc runs fn in a new goroutine like Go, and calls done with its error, or
+// the one describing its panic, from that goroutine.
+// If done is nil, the error is dropped.
+func GoFunc(fn func() error, done func(error)) {
+	go func() {
+		err := runTask(fn)
+
+		if done != nil {
+			done(err)
+		}
+	}()
+}
@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGo(t *testing.T) {
+	assert.Equal(t, io.EOF, <-Go(func() error { return io.EOF }))
+
+	done := Go(func() error { return nil })
+	err, ok := <-done
+	assert.True(t, ok)
+	assert.Nil(t, err)
+
+	_, ok = <-done
+	assert.False(t, ok, "the channel is closed")
+
+	err = <-Go(func() error { panic("boom") })
+	assert.Equal(t, "panic: boom", err.Error())
+
+	v, _ := PanicValue(err)
+	assert.Equal(t, "boom", v)
+}
+
+func TestGoFunc(t *testing.T) {
+	errs := make(chan error)
+
+	GoFunc(func() error { panic(io.EOF) }, func(err error) { errs <- err })
+
+	err := <-errs
+	assert.Equal(t, io.EOF, Cause(err))
+	assert.Equal(t, KindInternal, GetKind(err))
+
+	GoFunc(func() error { return nil }, nil)
+}
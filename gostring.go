@@ -0,0 +1,115 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// goString returns the Go-syntax representation of err printed by the %#v
+// verb: the type, own message, number of fields and number of frames of
+// every layer, nested like the chain.
+func goString(err error) string {
+	var b strings.Builder
+
+	writeGoString(&b, err, 1)
+
+	return b.String()
+}
+
+func writeGoString(b *strings.Builder, err error, depth int) {
+	type fielder interface {
+		Fields() Fields
+	}
+
+	type stacker interface {
+		Stack() []StackFrame
+	}
+
+	switch err.(type) {
+	case *fundamental, *withStack, *withMessage, *withFields, *withFieldList, *withKind, *withCode,
+		*withUserMessage, *withHTTPStatus, *RemoteError, *contextError, *MultiError:
+	default:
+		_, _ = fmt.Fprintf(b, "%#v", err)
+
+		return
+	}
+
+	parts := make([]string, 0, 4) //nolint:gomnd // message, fields, frames and cause
+
+	if m, ok := err.(*MultiError); ok {
+		parts = append(parts, fmt.Sprintf("errors:%d", len(m.errors)))
+	} else if msg, ok := ownMessage(err); ok {
+		parts = append(parts, fmt.Sprintf("msg:%q", msg))
+	}
+
+	if f, ok := err.(fielder); ok && len(f.Fields()) > 0 {
+		parts = append(parts, fmt.Sprintf("fields:%d", len(f.Fields())))
+	}
+
+	switch v := err.(type) {
+	case stackTracer:
+		parts = append(parts, fmt.Sprintf("frames:%d", len(v.StackTrace())))
+	case stacker:
+		parts = append(parts, fmt.Sprintf("frames:%d", len(v.Stack())))
+	}
+
+	_, _ = fmt.Fprintf(b, "&%s{%s", strings.TrimPrefix(fmt.Sprintf("%T", err), "*"), strings.Join(parts, ", "))
+
+	if c, ok := err.(causer); ok && c.Cause() != nil {
+		if len(parts) > 0 {
+			b.WriteString(", ")
+		}
+
+		b.WriteString("cause:")
+
+		if depth == MaxChainDepth() {
+			b.WriteString("...")
+		} else {
+			writeGoString(b, c.Cause(), depth+1)
+		}
+	}
+
+	b.WriteByte('}')
+}
+
+// formatGoString prints the Go-syntax representation of err.
+func formatGoString(w io.Writer, err error) {
+	_, _ = io.WriteString(w, goString(err))
+}
+
+// GoString implements fmt.GoStringer.
+func (f *fundamental) GoString() string { return goString(f) }
+
+// GoString implements fmt.GoStringer.
+func (w *withStack) GoString() string { return goString(w) }
+
+// GoString implements fmt.GoStringer.
+func (w *withMessage) GoString() string { return goString(w) }
+
+// GoString implements fmt.GoStringer.
+func (w *withFields) GoString() string { return goString(w) }
+
+// GoString implements fmt.GoStringer.
+func (w *withFieldList) GoString() string { return goString(w) }
+
+// GoString implements fmt.GoStringer.
+func (w *withKind) GoString() string { return goString(w) }
+
+// GoString implements fmt.GoStringer.
+func (w *withCode) GoString() string { return goString(w) }
+
+// GoString implements fmt.GoStringer.
+func (w *withUserMessage) GoString() string { return goString(w) }
+
+// GoString implements fmt.GoStringer.
+func (w *withHTTPStatus) GoString() string { return goString(w) }
+
+// GoString implements fmt.GoStringer.
+func (r *RemoteError) GoString() string { return goString(r) }
+
+// GoString implements fmt.GoStringer.
+func (e *contextError) GoString() string { return goString(e) }
+
+// GoString implements fmt.GoStringer.
+func (m *MultiError) GoString() string { return goString(m) }
@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGoString(t *testing.T) {
+	st, _ := StackOf(New("frames"))
+	frames := len(st)
+
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{
+			New("boom"),
+			fmt.Sprintf(`&errors.fundamental{msg:"boom", frames:%d}`, frames),
+		},
+		{
+			WithMessage(io.EOF, "reading"),
+			`&errors.withMessage{msg:"reading", cause:&errors.errorString{s:"EOF"}}`,
+		},
+		{
+			WithKind(WithFields(WithStack(io.EOF), Fields{"a": 1, "b": 2}), KindNotFound),
+			fmt.Sprintf(`&errors.withKind{cause:&errors.withFields{fields:2, cause:&errors.withStack{frames:%d, `+
+				`cause:&errors.errorString{s:"EOF"}}}}`, frames),
+		},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, fmt.Sprintf("%#v", tt.err))
+		assert.Equal(t, tt.want, tt.err.(fmt.GoStringer).GoString())
+	}
+}
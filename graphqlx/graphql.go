@@ -0,0 +1,109 @@
+// Package graphqlx converts error chains into GraphQL errors shaped like the
+// gqlerror.Error values of gqlparser and gqlgen, with the code, fields and
+// user message of the chain in the extensions.
+//
+// The package does not depend on gqlgen; an error presenter can be written as:
+//
+//     srv.SetErrorPresenter(func(ctx context.Context, err error) *gqlerror.Error {
+//         e := graphqlx.Convert(err, debug)
+//
+//         return &gqlerror.Error{
+//             Message:    e.Message,
+//             Path:       graphql.GetPath(ctx),
+//             Extensions: e.Extensions,
+//         }
+//     })
+package graphqlx
+
+import (
+	"strings"
+
+	"github.com/hexbee-net/errors"
+)
+
+// DefaultMessage is the message of errors without a user message when the
+// internal messages are hidden.
+const DefaultMessage = "internal system error"
+
+// Extension keys.
+const (
+	CodeKey     = "code"
+	FieldsKey   = "fields"
+	InternalKey = "internal"
+)
+
+// Error is a GraphQL error, as described by the GraphQL specification.
+type Error struct {
+	Message    string                 `json:"message"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Convert returns the GraphQL error describing err.
+// The message is the user message of the chain. Without user message, it is
+// DefaultMessage unless debug is set, in which case the internal message is
+// used instead.
+// The extensions hold the code of the chain, or its kind in upper case when
+// it has no code, and its fields registered with errors.RegisterPublicFields.
+// When debug is set, they hold all its fields and the internal message.
+// If err is nil, Convert returns nil.
+func Convert(err error, debug bool) *Error {
+	if err == nil {
+		return nil
+	}
+
+	e := &Error{
+		Message:    errors.GetUserMessage(err),
+		Extensions: make(map[string]interface{}),
+	}
+
+	if e.Message == "" {
+		e.Message = DefaultMessage
+
+		if debug {
+			e.Message = err.Error()
+		}
+	}
+
+	if code := Code(err); code != "" {
+		e.Extensions[CodeKey] = code
+	}
+
+	fields := errors.GetFields(err)
+	if !debug {
+		fields = errors.PublicFields(fields)
+	}
+
+	if len(fields) > 0 {
+		e.Extensions[FieldsKey] = map[string]interface{}(fields)
+	}
+
+	if debug {
+		e.Extensions[InternalKey] = err.Error()
+	}
+
+	if len(e.Extensions) == 0 {
+		e.Extensions = nil
+	}
+
+	return e
+}
+
+// Code returns the GraphQL error code of err: its code if it has one,
+// otherwise its kind in upper case, like "NOT_FOUND".
+// If err has neither code nor kind, an empty string will be returned.
+func Code(err error) string {
+	if code := errors.GetCode(err); code != "" {
+		return code
+	}
+
+	if kind := errors.GetKind(err); kind != errors.KindUnknown {
+		return strings.ToUpper(kind.String())
+	}
+
+	return ""
+}
@@ -0,0 +1,108 @@
+package graphqlx
+
+import (
+	"encoding/json"
+	"io"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConvert(t *testing.T) {
+	annotated := errors.WithUserMessage(
+		errors.WithCode(errors.WithField(errors.Wrap(io.EOF, "read error"), "id", 42), "user.not_found"),
+		"The user does not exist.",
+	)
+
+	validation := errors.NewValidation()
+	validation.Add("email", "required", "must be set", "")
+
+	tests := []struct {
+		name  string
+		err   error
+		debug bool
+		want  *Error
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: nil,
+		},
+		{
+			name: "internal",
+			err:  errors.Wrap(io.EOF, "read error"),
+			want: &Error{Message: DefaultMessage},
+		},
+		{
+			name:  "internal debug",
+			err:   errors.Wrap(io.EOF, "read error"),
+			debug: true,
+			want: &Error{
+				Message:    "read error: EOF",
+				Extensions: map[string]interface{}{InternalKey: "read error: EOF"},
+			},
+		},
+		{
+			name: "annotated",
+			err:  annotated,
+			want: &Error{
+				Message:    "The user does not exist.",
+				Extensions: map[string]interface{}{CodeKey: "user.not_found"},
+			},
+		},
+		{
+			name:  "annotated debug",
+			err:   annotated,
+			debug: true,
+			want: &Error{
+				Message: "The user does not exist.",
+				Extensions: map[string]interface{}{
+					CodeKey:     "user.not_found",
+					FieldsKey:   map[string]interface{}{"id": 42},
+					InternalKey: "read error: EOF",
+				},
+			},
+		},
+		{
+			name: "public fields",
+			err:  errors.WithField(validation, "query", "INSERT"),
+			want: &Error{
+				Message: "validation failed: email: must be set",
+				Extensions: map[string]interface{}{
+					CodeKey:   "INVALID_ARGUMENT",
+					FieldsKey: map[string]interface{}{errors.ViolationsField: validation.Violations()},
+				},
+			},
+		},
+		{
+			name: "kind",
+			err:  errors.WithKind(io.EOF, errors.KindPermissionDenied),
+			want: &Error{
+				Message:    DefaultMessage,
+				Extensions: map[string]interface{}{CodeKey: "PERMISSION_DENIED"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, Convert(tt.err, tt.debug))
+		})
+	}
+}
+
+func TestErrorJSON(t *testing.T) {
+	e := Convert(errors.WithKind(errors.WithUserMessage(io.EOF, "Not found."), errors.KindNotFound), false)
+	e.Path = []interface{}{"user", 0, "name"}
+
+	assert.Equal(t, "Not found.", e.Error())
+
+	data, err := json.Marshal(e)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"message": "Not found.",
+		"path": ["user", 0, "name"],
+		"extensions": {"code": "NOT_FOUND"}
+	}`, string(data))
+}
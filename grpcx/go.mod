@@ -0,0 +1,29 @@
+module github.com/hexbee-net/errors/grpcx
+
+go 1.20
+
+require (
+	github.com/apex/log v1.9.0
+	github.com/hexbee-net/errors v0.0.0
+	github.com/hexbee-net/errors/errorspb v0.0.0
+	github.com/stretchr/testify v1.6.1
+	google.golang.org/genproto/googleapis/rpc v0.0.0-20231016165738-49dd2c1f3d0b
+	google.golang.org/grpc v1.59.0
+	google.golang.org/protobuf v1.31.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/golang/protobuf v1.5.3 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	golang.org/x/net v0.14.0 // indirect
+	golang.org/x/sys v0.11.0 // indirect
+	golang.org/x/text v0.12.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace (
+	github.com/hexbee-net/errors => ../
+	github.com/hexbee-net/errors/errorspb => ../errorspb
+)
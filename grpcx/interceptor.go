@@ -0,0 +1,119 @@
+package grpcx
+
+import (
+	"context"
+	"io"
+
+	"github.com/apex/log"
+	"github.com/hexbee-net/errors"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+)
+
+// Fields added by the interceptors.
+const (
+	MethodField = "grpc.method"
+	CodeField   = "grpc.code"
+)
+
+// UnaryServerInterceptor returns a server interceptor converting the errors
+// returned by handlers to gRPC statuses with ToGRPCStatus.
+// The errors are logged on logger with their fields, the called method and
+// the status code. If logger is nil, they are not logged.
+func UnaryServerInterceptor(logger log.Interface) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		if err != nil {
+			return resp, serverError(logger, info.FullMethod, err)
+		}
+
+		return resp, nil
+	}
+}
+
+// StreamServerInterceptor returns a server interceptor converting the errors
+// returned by stream handlers to gRPC statuses with ToGRPCStatus.
+// The errors are logged like in UnaryServerInterceptor.
+func StreamServerInterceptor(logger log.Interface) grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if err := handler(srv, ss); err != nil {
+			return serverError(logger, info.FullMethod, err)
+		}
+
+		return nil
+	}
+}
+
+func serverError(logger log.Interface, method string, err error) error {
+	st := ToGRPCStatus(err)
+
+	if logger != nil {
+		fields := errors.GetFields(err)
+		fields[MethodField] = method
+		fields[CodeField] = st.Code().String()
+
+		logger.WithFields(fields).WithError(err).Error("grpc call failed")
+	}
+
+	return st.Err()
+}
+
+// UnaryClientInterceptor returns a client interceptor converting the statuses
+// received from servers back to error chains with FromGRPCStatus.
+// The called method is added to the errors as a field.
+func UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(
+		ctx context.Context, method string, req, reply interface{},
+		cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption,
+	) error {
+		return clientError(method, invoker(ctx, method, req, reply, cc, opts...))
+	}
+}
+
+// StreamClientInterceptor returns a client interceptor converting the statuses
+// received on streams back to error chains, like UnaryClientInterceptor.
+// The io.EOF error marking the end of a stream is returned as is.
+func StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(
+		ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string,
+		streamer grpc.Streamer, opts ...grpc.CallOption,
+	) (grpc.ClientStream, error) {
+		cs, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, clientError(method, err)
+		}
+
+		return &clientStream{ClientStream: cs, method: method}, nil
+	}
+}
+
+// clientError returns the error chain described by the status carried by err.
+// Errors without status, such as the ones of the context, are kept.
+func clientError(method string, err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+
+	if st, ok := status.FromError(err); ok {
+		err = FromGRPCStatus(st)
+	}
+
+	return errors.WithField(err, MethodField, method)
+}
+
+type clientStream struct {
+	grpc.ClientStream
+	method string
+}
+
+func (s *clientStream) SendMsg(m interface{}) error {
+	return clientError(s.method, s.ClientStream.SendMsg(m))
+}
+
+func (s *clientStream) RecvMsg(m interface{}) error {
+	return clientError(s.method, s.ClientStream.RecvMsg(m))
+}
+
+func (s *clientStream) CloseSend() error {
+	return clientError(s.method, s.ClientStream.CloseSend())
+}
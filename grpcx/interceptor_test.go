@@ -0,0 +1,123 @@
+package grpcx
+
+import (
+	"context"
+	"io"
+	"testing"
+
+	"github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+	"github.com/hexbee-net/errors"
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const method = "/users.v1.Users/Get"
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	handler := memory.New()
+	logger := &log.Logger{Handler: handler, Level: log.DebugLevel}
+	interceptor := UnaryServerInterceptor(logger)
+	info := &grpc.UnaryServerInfo{FullMethod: method}
+
+	resp, err := interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return "ok", nil
+	})
+	assert.Equal(t, "ok", resp)
+	assert.NoError(t, err)
+	assert.Empty(t, handler.Entries)
+
+	_, err = interceptor(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, errors.WithKind(errors.WithField(errors.New("no row"), "id", "42"), errors.KindNotFound)
+	})
+
+	st, ok := status.FromError(err)
+	assert.True(t, ok)
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "no row", st.Message())
+
+	if assert.Len(t, handler.Entries, 1) {
+		e := handler.Entries[0]
+		assert.Equal(t, log.ErrorLevel, e.Level)
+		assert.Equal(t, "grpc call failed", e.Message)
+		assert.Equal(t, "42", e.Fields["id"])
+		assert.Equal(t, method, e.Fields[MethodField])
+		assert.Equal(t, codes.NotFound.String(), e.Fields[CodeField])
+		assert.Equal(t, "no row", e.Fields["error"])
+	}
+
+	_, err = UnaryServerInterceptor(nil)(context.Background(), nil, info, func(context.Context, interface{}) (interface{}, error) {
+		return nil, io.EOF
+	})
+	assert.Equal(t, codes.Unknown, status.Code(err))
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	handler := memory.New()
+	interceptor := StreamServerInterceptor(&log.Logger{Handler: handler, Level: log.DebugLevel})
+	info := &grpc.StreamServerInfo{FullMethod: method}
+
+	assert.NoError(t, interceptor(nil, nil, info, func(interface{}, grpc.ServerStream) error { return nil }))
+
+	err := interceptor(nil, nil, info, func(interface{}, grpc.ServerStream) error {
+		return errors.WithKind(io.EOF, errors.KindUnavailable)
+	})
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	assert.Len(t, handler.Entries, 1)
+}
+
+func TestUnaryClientInterceptor(t *testing.T) {
+	interceptor := UnaryClientInterceptor()
+	sent := ToGRPCStatus(errors.WithCode(errors.WithKind(errors.New("no row"), errors.KindNotFound), "user.not_found")).Err()
+
+	err := interceptor(context.Background(), method, nil, nil, nil,
+		func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+			return sent
+		})
+
+	assert.Equal(t, "no row", err.Error())
+	assert.Equal(t, errors.KindNotFound, errors.GetKind(err))
+	assert.Equal(t, "user.not_found", errors.GetCode(err))
+	assert.Equal(t, errors.Fields{MethodField: method}, errors.GetFields(err))
+
+	err = interceptor(context.Background(), method, nil, nil, nil,
+		func(context.Context, string, interface{}, interface{}, *grpc.ClientConn, ...grpc.CallOption) error {
+			return nil
+		})
+	assert.NoError(t, err)
+}
+
+type stream struct {
+	grpc.ClientStream
+	recv error
+}
+
+func (s *stream) RecvMsg(interface{}) error { return s.recv }
+func (s *stream) SendMsg(interface{}) error { return status.New(codes.Aborted, "aborted").Err() }
+func (s *stream) CloseSend() error          { return nil }
+
+func TestStreamClientInterceptor(t *testing.T) {
+	interceptor := StreamClientInterceptor()
+	desc := &grpc.StreamDesc{ServerStreams: true}
+
+	_, err := interceptor(context.Background(), desc, nil, method,
+		func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+			return nil, status.New(codes.Unauthenticated, "no token").Err()
+		})
+	assert.Equal(t, errors.KindUnauthenticated, errors.GetKind(err))
+	assert.Equal(t, method, errors.GetFields(err)[MethodField])
+
+	cs, err := interceptor(context.Background(), desc, nil, method,
+		func(context.Context, *grpc.StreamDesc, *grpc.ClientConn, string, ...grpc.CallOption) (grpc.ClientStream, error) {
+			return &stream{recv: io.EOF}, nil
+		})
+	assert.NoError(t, err)
+	assert.Equal(t, io.EOF, cs.RecvMsg(nil))
+	assert.NoError(t, cs.CloseSend())
+
+	err = cs.SendMsg(nil)
+	assert.Equal(t, errors.KindAborted, errors.GetKind(err))
+	assert.Equal(t, method, errors.GetFields(err)[MethodField])
+}
@@ -0,0 +1,168 @@
+// Package grpcx converts error chains to and from gRPC statuses.
+//
+// The structured representation of the chain is attached to the status as a
+// detail, encoded with the errorspb schema, so that clients can restore the
+// code, user message and fields of the errors returned by a server.
+//
+// It lives in its own module so that the core package does not depend on gRPC.
+package grpcx
+
+import (
+	stderrors "errors"
+
+	"github.com/hexbee-net/errors"
+	"github.com/hexbee-net/errors/errorspb"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// ToGRPCStatus returns the gRPC status describing err.
+// The status code is derived from the kind of the chain, the message is the
+// complete message of err and the chain is attached as an errorspb.Error
+// detail.
+// If err has no kind but wraps a gRPC status, that status is returned as is.
+// If err is nil, a status with the OK code will be returned.
+func ToGRPCStatus(err error) *status.Status {
+	if err == nil {
+		return status.New(codes.OK, "")
+	}
+
+	kind := errors.GetKind(err)
+
+	var gs interface{ GRPCStatus() *status.Status }
+	if kind == errors.KindUnknown && stderrors.As(err, &gs) {
+		return gs.GRPCStatus()
+	}
+
+	st := &spb.Status{
+		Code:    int32(Code(kind)),
+		Message: err.Error(),
+	}
+
+	if detail, e := anypb.New(errorspb.ToProto(err)); e == nil {
+		st.Details = append(st.Details, detail)
+	}
+
+	return status.FromProto(st)
+}
+
+// FromGRPCStatus rebuilds the error described by st.
+// The chain is decoded from the errorspb.Error detail when st has one;
+// otherwise a single error is built from the message. The kind matching the
+// status code is added when the chain has none.
+// If st is nil or has the OK code, FromGRPCStatus returns nil.
+func FromGRPCStatus(st *status.Status) error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+
+	var err error
+
+	for _, d := range st.Proto().GetDetails() {
+		var m errorspb.Error
+		if !d.MessageIs(&m) {
+			continue
+		}
+
+		if d.UnmarshalTo(&m) == nil {
+			err = errorspb.FromProto(&m)
+		}
+
+		break
+	}
+
+	if err == nil {
+		return errors.FromDocument(errors.Document{Layers: []errors.Layer{{
+			Message: st.Message(),
+			Kind:    Kind(st.Code()),
+		}}})
+	}
+
+	if kind := Kind(st.Code()); kind != errors.KindUnknown && errors.GetKind(err) == errors.KindUnknown {
+		err = errors.WithKind(err, kind)
+	}
+
+	return err
+}
+
+// Code returns the gRPC status code matching k.
+func Code(k errors.Kind) codes.Code {
+	switch k {
+	case errors.KindCanceled:
+		return codes.Canceled
+	case errors.KindInvalidArgument:
+		return codes.InvalidArgument
+	case errors.KindDeadlineExceeded:
+		return codes.DeadlineExceeded
+	case errors.KindNotFound:
+		return codes.NotFound
+	case errors.KindAlreadyExists:
+		return codes.AlreadyExists
+	case errors.KindPermissionDenied:
+		return codes.PermissionDenied
+	case errors.KindResourceExhausted:
+		return codes.ResourceExhausted
+	case errors.KindFailedPrecondition:
+		return codes.FailedPrecondition
+	case errors.KindAborted:
+		return codes.Aborted
+	case errors.KindOutOfRange:
+		return codes.OutOfRange
+	case errors.KindUnimplemented:
+		return codes.Unimplemented
+	case errors.KindInternal:
+		return codes.Internal
+	case errors.KindUnavailable:
+		return codes.Unavailable
+	case errors.KindDataLoss:
+		return codes.DataLoss
+	case errors.KindUnauthenticated:
+		return codes.Unauthenticated
+	case errors.KindUnknown:
+		return codes.Unknown
+	}
+
+	return codes.Unknown
+}
+
+// Kind returns the kind matching the gRPC status code c.
+func Kind(c codes.Code) errors.Kind {
+	switch c {
+	case codes.Canceled:
+		return errors.KindCanceled
+	case codes.InvalidArgument:
+		return errors.KindInvalidArgument
+	case codes.DeadlineExceeded:
+		return errors.KindDeadlineExceeded
+	case codes.NotFound:
+		return errors.KindNotFound
+	case codes.AlreadyExists:
+		return errors.KindAlreadyExists
+	case codes.PermissionDenied:
+		return errors.KindPermissionDenied
+	case codes.ResourceExhausted:
+		return errors.KindResourceExhausted
+	case codes.FailedPrecondition:
+		return errors.KindFailedPrecondition
+	case codes.Aborted:
+		return errors.KindAborted
+	case codes.OutOfRange:
+		return errors.KindOutOfRange
+	case codes.Unimplemented:
+		return errors.KindUnimplemented
+	case codes.Internal:
+		return errors.KindInternal
+	case codes.Unavailable:
+		return errors.KindUnavailable
+	case codes.DataLoss:
+		return errors.KindDataLoss
+	case codes.Unauthenticated:
+		return errors.KindUnauthenticated
+	case codes.OK, codes.Unknown:
+		return errors.KindUnknown
+	}
+
+	return errors.KindUnknown
+}
@@ -0,0 +1,91 @@
+package grpcx
+
+import (
+	stderrors "errors"
+	"io"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/stretchr/testify/assert"
+	spb "google.golang.org/genproto/googleapis/rpc/status"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+func TestToGRPCStatusNil(t *testing.T) {
+	assert.Equal(t, codes.OK, ToGRPCStatus(nil).Code())
+	assert.Nil(t, FromGRPCStatus(nil))
+	assert.Nil(t, FromGRPCStatus(status.New(codes.OK, "")))
+}
+
+func TestGRPCStatusRoundTrip(t *testing.T) {
+	err := errors.WithUserMessage(
+		errors.WithCode(
+			errors.WithKind(errors.WithField(errors.Wrap(io.EOF, "read error"), "id", "42"), errors.KindNotFound),
+			"user.not_found",
+		),
+		"The user does not exist.",
+	)
+
+	st := ToGRPCStatus(err)
+
+	assert.Equal(t, codes.NotFound, st.Code())
+	assert.Equal(t, "read error: EOF", st.Message())
+	assert.Len(t, st.Proto().GetDetails(), 1)
+
+	got := FromGRPCStatus(st)
+
+	assert.Equal(t, "read error: EOF", got.Error())
+	assert.Equal(t, errors.KindNotFound, errors.GetKind(got))
+	assert.Equal(t, "user.not_found", errors.GetCode(got))
+	assert.Equal(t, "The user does not exist.", errors.GetUserMessage(got))
+	assert.Equal(t, errors.Fields{"id": "42"}, errors.GetFields(got))
+	assert.True(t, stderrors.Is(got, errors.WithCode(io.EOF, "user.not_found")))
+}
+
+func TestToGRPCStatusForeign(t *testing.T) {
+	st := status.New(codes.Unavailable, "try again")
+
+	assert.Equal(t, st, ToGRPCStatus(errors.Wrap(st.Err(), "call failed")))
+	assert.Equal(t, codes.Unknown, ToGRPCStatus(io.EOF).Code())
+}
+
+func TestFromGRPCStatusForeign(t *testing.T) {
+	tests := []struct {
+		st   *status.Status
+		kind errors.Kind
+	}{
+		{status.New(codes.PermissionDenied, "denied"), errors.KindPermissionDenied},
+		{status.FromProto(&spb.Status{
+			Code:    int32(codes.Aborted),
+			Message: "denied",
+			Details: []*anypb.Any{{TypeUrl: "type.googleapis.com/google.rpc.ErrorInfo"}},
+		}), errors.KindAborted},
+		{status.FromProto(&spb.Status{
+			Code:    int32(codes.Internal),
+			Message: "denied",
+			Details: []*anypb.Any{{TypeUrl: "type.googleapis.com/hexbee.errors.v1.Error", Value: []byte{0x0a}}},
+		}), errors.KindInternal},
+	}
+
+	for _, tt := range tests {
+		err := FromGRPCStatus(tt.st)
+
+		assert.Equal(t, "denied", err.Error())
+		assert.Equal(t, tt.kind, errors.GetKind(err))
+	}
+
+	err := FromGRPCStatus(ToGRPCStatus(errors.New("boom")))
+	assert.Equal(t, "boom", err.Error())
+	assert.IsType(t, &errors.RemoteError{}, err)
+}
+
+func TestKindRoundTrip(t *testing.T) {
+	for c := codes.Canceled; c <= codes.Unauthenticated; c++ {
+		assert.Equal(t, c, Code(Kind(c)), c)
+	}
+
+	assert.Equal(t, codes.Unknown, Code("custom"))
+	assert.Equal(t, errors.KindUnknown, Kind(codes.OK))
+}
@@ -0,0 +1,63 @@
+package errors
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+//nolint:gochecknoglobals // the trimming of stacks is enabled process-wide.
+var trimHarness int32
+
+// harnessPackages are the packages running the code of a program rather than
+// being part of it.
+//nolint:gochecknoglobals // immutable list of packages.
+var harnessPackages = []string{"runtime", "testing", "net/http"}
+
+// TrimHarnessFrames enables or disables the trimming of the frames of the
+// runtime, of the testing package and of the HTTP server from both ends of the
+// stacks captured from then on, such as runtime.goexit, testing.tRunner or
+// net/http.(*conn).serve.
+// Unlike the filter set by SetStackFilter, trimming applies to the stacks
+// themselves, as returned by StackOf and in the structured encodings.
+// The frames in the middle of a stack, such as the ones of a handler called
+// by a standard library function, are kept.
+// Stacks are not trimmed by default.
+func TrimHarnessFrames(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&trimHarness, v)
+}
+
+func trimmingHarness() bool {
+	return atomic.LoadInt32(&trimHarness) == 1
+}
+
+// trimHarnessFrames returns pcs without its leading and trailing harness
+// frames, keeping at least one frame.
+func trimHarnessFrames(pcs []uintptr) []uintptr {
+	for len(pcs) > 1 && isHarnessFrame(Frame(pcs[len(pcs)-1])) {
+		pcs = pcs[:len(pcs)-1]
+	}
+
+	for len(pcs) > 1 && isHarnessFrame(Frame(pcs[0])) {
+		pcs = pcs[1:]
+	}
+
+	return pcs
+}
+
+// isHarnessFrame reports whether f belongs to one of the harness packages.
+func isHarnessFrame(f Frame) bool {
+	pkg := f.Package()
+
+	for _, h := range harnessPackages {
+		if pkg == h || strings.HasPrefix(pkg, h+"/") {
+			return true
+		}
+	}
+
+	return false
+}
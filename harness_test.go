@@ -0,0 +1,41 @@
+package errors
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrimHarnessFrames(t *testing.T) {
+	requireStacks(t)
+
+	st, _ := StackOf(New("untrimmed"))
+	assert.Equal(t, "runtime", st[len(st)-1].Package())
+
+	TrimHarnessFrames(true)
+	defer TrimHarnessFrames(false)
+
+	st, _ = StackOf(New("trimmed"))
+	if assert.Len(t, st, 1) {
+		assert.Equal(t, "TestTrimHarnessFrames", st[0].Func())
+	}
+
+	st, _ = StackOf(func() error { return Wrap(New("trimmed"), "wrapped") }())
+	if assert.Len(t, st, 2) {
+		assert.Equal(t, "TestTrimHarnessFrames.func1", st[0].Func())
+		assert.Equal(t, "TestTrimHarnessFrames", st[1].Func())
+	}
+}
+
+func TestIsHarnessFrame(t *testing.T) {
+	requireStacks(t)
+
+	st, _ := StackOf(New("boom"))
+
+	var got []bool
+	for _, f := range st {
+		got = append(got, isHarnessFrame(f))
+	}
+
+	assert.Equal(t, []bool{false, true, true}, got)
+}
@@ -0,0 +1,101 @@
+package errors
+
+import (
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"unsafe"
+)
+
+// Hook is invoked with every error built by the constructors of this package
+// and returns the error handed back to the caller.
+// Returning nil keeps the error unchanged.
+type Hook func(err error) error
+
+//nolint:gochecknoglobals // hooks are registered process-wide.
+var (
+	hooksMu sync.Mutex
+	hooks   atomic.Value // []Hook
+	hooking sync.Map     // keys of the errors currently passed to a hook
+)
+
+// RegisterHook adds h to the hooks invoked by the constructors of this
+// package, such as New, Errorf, Wrap, WithStack, WithFields, WithFieldList,
+// WithKind or WithCode.
+// Hooks are called in registration order, possibly from many goroutines at
+// once, so they must be cheap and safe for concurrent use.
+// A hook may annotate the error with the constructors of this package: errors
+// built on top of an error being hooked are not hooked again.
+func RegisterHook(h Hook) {
+	if h == nil {
+		return
+	}
+
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+
+	current, _ := hooks.Load().([]Hook)
+
+	registered := make([]Hook, len(current), len(current)+1)
+	copy(registered, current)
+
+	hooks.Store(append(registered, h))
+}
+
+// runHooks passes err, built on top of cause, through the registered hooks.
+func runHooks(err, cause error) error {
+	registered, _ := hooks.Load().([]Hook)
+	if len(registered) == 0 {
+		return err
+	}
+
+	if key, ok := hookKey(cause); ok {
+		if _, ok := hooking.Load(key); ok {
+			return err
+		}
+	}
+
+	for _, h := range registered {
+		key, ok := hookKey(err)
+		if ok {
+			hooking.Store(key, struct{}{})
+		}
+
+		r := h(err)
+
+		if ok {
+			hooking.Delete(key)
+		}
+
+		if r != nil {
+			err = r
+		}
+	}
+
+	return err
+}
+
+// errorKey identifies an error by its address, whatever its type.
+type errorKey struct {
+	typ reflect.Type
+	ptr unsafe.Pointer
+}
+
+// hookKey returns the key of err in the errors being hooked.
+// The errors are keyed by their address, as the error values built on top of
+// by the constructors may be of types that cannot be hashed, such as structs
+// holding slices. Hooked errors are pointers to the types of this package, or
+// the errors their hooks return, so that an error that is not a pointer is
+// never being hooked.
+func hookKey(err error) (errorKey, bool) {
+	if err == nil {
+		return errorKey{}, false
+	}
+
+	v := reflect.ValueOf(err)
+	if v.Kind() != reflect.Ptr {
+		return errorKey{}, false
+	}
+
+	return errorKey{typ: v.Type(), ptr: v.UnsafePointer()}, true
+}
@@ -0,0 +1,134 @@
+package errors
+
+import (
+	"io"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withHooks(t *testing.T, hs ...Hook) {
+	t.Helper()
+
+	hooks.Store([]Hook(nil))
+
+	for _, h := range hs {
+		RegisterHook(h)
+	}
+
+	t.Cleanup(func() { hooks.Store([]Hook(nil)) })
+}
+
+func TestRegisterHook(t *testing.T) {
+	var calls int32
+
+	withHooks(t, func(err error) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+
+	constructors := []func() error{
+		func() error { return New("error") },
+		func() error { return Errorf("error %d", 1) },
+		func() error { return Wrap(io.EOF, "error") },
+		func() error { return Wrapf(io.EOF, "error %d", 1) },
+		func() error { return WithStack(io.EOF) },
+		func() error { return WithMessage(io.EOF, "error") },
+		func() error { return WithMessagef(io.EOF, "error %d", 1) },
+		func() error { return WithField(io.EOF, "key", "value") },
+		func() error { return WithFields(io.EOF, Fields{"key": "value"}) },
+		func() error { return WithKind(io.EOF, KindInternal) },
+		func() error { return WithCode(io.EOF, "code") },
+	}
+
+	for i, c := range constructors {
+		assert.NotNil(t, c())
+		assert.Equal(t, int32(i+1), atomic.LoadInt32(&calls))
+	}
+
+	assert.Nil(t, Wrap(nil, "error"))
+	assert.Equal(t, int32(len(constructors)), atomic.LoadInt32(&calls))
+}
+
+func TestRegisterHookAnnotate(t *testing.T) {
+	var calls int32
+
+	withHooks(t,
+		func(err error) error {
+			atomic.AddInt32(&calls, 1)
+			return WithField(err, "build", "v1.2.3")
+		},
+		func(err error) error {
+			atomic.AddInt32(&calls, 1)
+			return WithKind(err, KindInternal)
+		},
+	)
+
+	err := Wrap(io.EOF, "read error")
+
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, "read error: EOF", err.Error())
+	assert.Equal(t, Fields{"build": "v1.2.3"}, GetFields(err))
+	assert.Equal(t, KindInternal, GetKind(err))
+	assert.Equal(t, io.EOF, Cause(err))
+}
+
+func TestRegisterHookConcurrent(t *testing.T) {
+	var calls int32
+
+	withHooks(t, func(err error) error {
+		atomic.AddInt32(&calls, 1)
+		return WithField(err, "key", "value")
+	})
+
+	const n = 100
+
+	var wg sync.WaitGroup
+
+	wg.Add(n)
+
+	for i := 0; i < n; i++ {
+		go func() {
+			defer wg.Done()
+
+			err := New("error")
+			assert.Equal(t, Fields{"key": "value"}, GetFields(err))
+		}()
+	}
+
+	wg.Wait()
+	assert.Equal(t, int32(n), atomic.LoadInt32(&calls))
+}
+
+type valueError struct {
+	reasons []string
+}
+
+func (e valueError) Error() string {
+	return "value error"
+}
+
+func TestRegisterHookUnhashable(t *testing.T) {
+	var calls int32
+
+	withHooks(t, func(err error) error {
+		atomic.AddInt32(&calls, 1)
+		return WithField(err, "key", "value")
+	})
+
+	cause := valueError{reasons: []string{"a"}}
+
+	assert.NotPanics(t, func() {
+		err := Wrap(cause, "error")
+
+		assert.Equal(t, "error: value error", err.Error())
+		assert.Equal(t, Fields{"key": "value"}, GetFields(err))
+		assert.Equal(t, cause, Cause(err))
+
+		err = WithKind(cause, KindInternal)
+		assert.Equal(t, KindInternal, GetKind(err))
+	})
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
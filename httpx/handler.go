@@ -0,0 +1,174 @@
+// Package httpx integrates error chains with net/http servers and clients.
+//
+// On the server side, handlers return errors, which are logged and rendered
+// as Problem Details responses:
+//
+//     r := httpx.Renderer{Logger: log.Log}
+//
+//     http.Handle("/users", r.Handler(func(w http.ResponseWriter, req *http.Request) error {
+//         user, err := lookup(req)
+//         if err != nil {
+//             return errors.Wrap(err, "looking up user")
+//         }
+//
+//         return json.NewEncoder(w).Encode(user)
+//     }))
+package httpx
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/apex/log"
+	"github.com/hexbee-net/errors"
+)
+
+// Fields added to the logged errors.
+const (
+	MethodField = "http.method"
+	PathField   = "http.path"
+	StatusField = "http.status"
+	StackField  = "stack"
+	// RequestIDField holds the RequestIDHeader header of the requests whose
+	// handler panicked.
+	RequestIDField = "http.request_id"
+)
+
+// RequestIDHeader is the header of the ID of a request.
+const RequestIDHeader = "X-Request-Id"
+
+// ChainMember is the Problem Details extension member holding the chain of
+// the error in debug mode.
+const ChainMember = "chain"
+
+// HandlerFunc is an HTTP handler returning an error.
+// A HandlerFunc is an http.Handler rendering its errors with a zero Renderer.
+type HandlerFunc func(w http.ResponseWriter, r *http.Request) error
+
+func (f HandlerFunc) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	Renderer{}.Handler(f).ServeHTTP(w, r)
+}
+
+// Renderer logs errors and writes them as HTTP responses.
+type Renderer struct {
+	// Logger receives the errors, with their fields and stack trace.
+	// If Logger is nil, the errors are not logged.
+	Logger log.Interface
+	// Debug adds all the fields of the errors and the structured
+	// representation of their chain to the responses, instead of the public
+	// fields only.
+	// It exposes internal messages and must not be enabled in production.
+	Debug bool
+	// Limits cap the values of the fields logged and written as Problem
+	// Details extensions, on top of the limits set by errors.SetFieldLimits.
+	Limits errors.FieldLimits
+}
+
+// Handler returns an http.Handler calling f and rendering its error.
+func (rd Renderer) Handler(f HandlerFunc) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := f(w, r); err != nil {
+			rd.Render(w, r, err)
+		}
+	})
+}
+
+// Middleware returns a handler calling next and rendering its panics as
+// internal errors, recovered with errors.Recover so that they carry the stack
+// trace of the panic, annotated with the method, path and ID of the request
+// and the fields stashed on its context.
+// The http.ErrAbortHandler panic is propagated.
+func (rd Renderer) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var err error
+
+		defer func() {
+			if err == nil {
+				return
+			}
+
+			if v, _ := errors.PanicValue(err); v == http.ErrAbortHandler {
+				panic(v)
+			}
+
+			rd.Render(w, r, errors.WithFields(err, requestFields(r)))
+		}()
+
+		defer errors.Recover(&err)
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// requestFields returns the fields describing r.
+func requestFields(r *http.Request) errors.Fields {
+	fields := errors.FieldsFromContext(r.Context())
+	fields[MethodField] = r.Method
+	fields[PathField] = r.URL.Path
+
+	if id := r.Header.Get(RequestIDHeader); id != "" {
+		fields[RequestIDField] = id
+	}
+
+	return fields
+}
+
+// Render logs err and writes it as the response to r, as Problem Details
+// with the status given by errors.HTTPStatus.
+// Only the fields registered with errors.RegisterPublicFields are written as
+// extension members, and never the value of a recovered panic, unless Debug
+// is set.
+// Errors with a server error status are logged at the error level, the other
+// ones at the warning level.
+func (rd Renderer) Render(w http.ResponseWriter, r *http.Request, err error) {
+	p := errors.ToProblem(err)
+
+	if rd.Debug {
+		p.Extensions = errors.GetFields(err)
+	} else {
+		delete(p.Extensions, errors.PanicValueField)
+	}
+
+	if len(p.Extensions) > 0 {
+		p.Extensions = rd.Limits.LimitFields(p.Extensions)
+	} else {
+		p.Extensions = nil
+	}
+
+	if rd.Logger != nil {
+		fields := rd.Limits.LimitFields(errors.GetFields(err))
+		fields[MethodField] = r.Method
+		fields[PathField] = r.URL.Path
+		fields[StatusField] = p.Status
+		fields[StackField] = fmt.Sprintf("%+v", err)
+
+		entry := rd.Logger.WithFields(fields).WithError(err)
+		if p.Status >= http.StatusInternalServerError {
+			entry.Error("http request failed")
+		} else {
+			entry.Warn("http request failed")
+		}
+	}
+
+	if rd.Debug {
+		if data, e := errors.ToJSON(err); e == nil {
+			if p.Extensions == nil {
+				p.Extensions = make(map[string]interface{}, 1)
+			}
+
+			p.Extensions[ChainMember] = json.RawMessage(data)
+		}
+	}
+
+	data, e := json.Marshal(p)
+	if e != nil {
+		http.Error(w, http.StatusText(p.Status), p.Status)
+
+		return
+	}
+
+	w.Header().Set("Content-Type", errors.ProblemContentType)
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(data)
+}
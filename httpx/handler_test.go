@@ -0,0 +1,198 @@
+package httpx
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apex/log"
+	"github.com/apex/log/handlers/memory"
+	"github.com/hexbee-net/errors"
+	"github.com/hexbee-net/errors/internal/nostack"
+	"github.com/stretchr/testify/assert"
+)
+
+func serve(h http.Handler) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/users/42", nil))
+
+	return rec
+}
+
+func TestHandlerFunc(t *testing.T) {
+	rec := serve(HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		_, _ = io.WriteString(w, "ok")
+
+		return nil
+	}))
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "ok", rec.Body.String())
+
+	rec = serve(HandlerFunc(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.WithUserMessage(errors.WithKind(errors.New("no row"), errors.KindNotFound), "No such user.")
+	}))
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+	assert.Equal(t, errors.ProblemContentType, rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Not Found",
+		"status": 404,
+		"detail": "No such user."
+	}`, rec.Body.String())
+}
+
+func TestRendererLogging(t *testing.T) {
+	handler := memory.New()
+	rd := Renderer{Logger: &log.Logger{Handler: handler, Level: log.DebugLevel}}
+
+	serve(rd.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.WithField(errors.New("boom"), "id", "42")
+	}))
+	serve(rd.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.WithKind(errors.New("missing name"), errors.KindInvalidArgument)
+	}))
+
+	if assert.Len(t, handler.Entries, 2) {
+		e := handler.Entries[0]
+		assert.Equal(t, log.ErrorLevel, e.Level)
+		assert.Equal(t, "http request failed", e.Message)
+		assert.Equal(t, "boom", e.Fields["error"])
+		assert.Equal(t, "42", e.Fields["id"])
+		assert.Equal(t, http.MethodGet, e.Fields[MethodField])
+		assert.Equal(t, "/users/42", e.Fields[PathField])
+		assert.Equal(t, http.StatusInternalServerError, e.Fields[StatusField])
+
+		if !nostack.Enabled {
+			assert.Contains(t, e.Fields[StackField], "httpx.TestRendererLogging")
+		}
+
+		assert.Equal(t, log.WarnLevel, handler.Entries[1].Level)
+	}
+}
+
+func TestRendererLimits(t *testing.T) {
+	errors.RegisterPublicFields("body")
+
+	handler := memory.New()
+	rd := Renderer{
+		Logger: &log.Logger{Handler: handler, Level: log.DebugLevel},
+		Limits: errors.FieldLimits{MaxLength: 4},
+	}
+
+	rec := serve(rd.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.WithField(errors.New("boom"), "body", "truncated")
+	}))
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	assert.Equal(t, "trun…", body["body"])
+
+	if assert.Len(t, handler.Entries, 1) {
+		assert.Equal(t, "trun…", handler.Entries[0].Fields["body"])
+
+		if !nostack.Enabled {
+			assert.Contains(t, handler.Entries[0].Fields[StackField], "httpx.TestRendererLimits")
+		}
+	}
+}
+
+func TestRendererDebug(t *testing.T) {
+	rd := Renderer{Debug: true}
+
+	rec := serve(rd.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.WithField(errors.Wrap(errors.New("boom"), "saving user"), "query", "INSERT")
+	}))
+
+	var body map[string]interface{}
+	assert.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+
+	chain, ok := body[ChainMember].(map[string]interface{})
+	assert.True(t, ok)
+	assert.Equal(t, "saving user: boom", chain["message"])
+	assert.Equal(t, "INSERT", body["query"])
+
+	rec = serve(Renderer{}.Handler(func(w http.ResponseWriter, r *http.Request) error {
+		return errors.WithField(errors.Wrap(errors.New("boom"), "saving user"), "query", "INSERT")
+	}))
+	assert.NotContains(t, rec.Body.String(), "boom")
+	assert.NotContains(t, rec.Body.String(), "INSERT")
+}
+
+func TestMiddleware(t *testing.T) {
+	handler := memory.New()
+	rd := Renderer{Logger: &log.Logger{Handler: handler, Level: log.DebugLevel}}
+
+	rec := serve(rd.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("nil map")
+	})))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	rec = serve(rd.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(io.ErrUnexpectedEOF)
+	})))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+
+	if assert.Len(t, handler.Entries, 2) {
+		assert.Equal(t, "panic: nil map", handler.Entries[0].Fields["error"])
+		assert.Equal(t, "panic: unexpected EOF", handler.Entries[1].Fields["error"])
+
+		if !nostack.Enabled {
+			assert.True(t, strings.Contains(handler.Entries[1].Fields[StackField].(string),
+				"\npanic\ngithub.com/hexbee-net/errors/httpx.TestMiddleware.func2\n"), "the stack starts at the panic")
+		}
+
+		assert.Equal(t, "/users/42", handler.Entries[1].Fields[PathField])
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/users", nil)
+	req.Header.Set(RequestIDHeader, "r1")
+	req = req.WithContext(errors.WithContextFields(req.Context(), errors.Fields{"tenant": "t1"}))
+
+	rec = httptest.NewRecorder()
+	Renderer{Logger: &log.Logger{Handler: handler, Level: log.DebugLevel}}.Middleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic("boom")
+		}),
+	).ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.Equal(t, errors.ProblemContentType, rec.Header().Get("Content-Type"))
+
+	if assert.Len(t, handler.Entries, 3) {
+		fields := handler.Entries[2].Fields
+		assert.Equal(t, "r1", fields[RequestIDField])
+		assert.Equal(t, "t1", fields["tenant"])
+		assert.Equal(t, http.MethodPost, fields[MethodField])
+	}
+
+	errors.RegisterPublicFields(errors.PanicValueField)
+
+	rec = serve(rd.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("db password=hunter2 rejected")
+	})))
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Internal Server Error",
+		"status": 500
+	}`, rec.Body.String())
+
+	rec = serve(Renderer{Debug: true}.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("db password=hunter2 rejected")
+	})))
+	assert.Contains(t, rec.Body.String(), `"panic.value":"db password=hunter2 rejected"`)
+
+	rec = serve(rd.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNoContent)
+	})))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		serve(rd.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			panic(http.ErrAbortHandler)
+		})))
+	})
+}
@@ -0,0 +1,178 @@
+package httpx
+
+import (
+	"bytes"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+	"unicode/utf8"
+
+	"github.com/hexbee-net/errors"
+)
+
+// Fields added to the errors returned by FromResponse.
+const (
+	BodyField       = "http.body"
+	RetryAfterField = "http.retry_after"
+)
+
+// MaxBodySnippet is the number of bytes of the response body kept in the
+// BodyField field.
+const MaxBodySnippet = 512
+
+// FromResponse returns an error describing resp when its status is not a
+// success (2xx).
+// The error has the status, method and URL of the request, the beginning of
+// the body and the delay parsed from the Retry-After header as fields, and a
+// kind derived from the status.
+// The body snippet is put back in front of resp.Body, which can still be read
+// and must still be closed by the caller.
+// If resp is nil or successful, FromResponse returns nil.
+func FromResponse(resp *http.Response) error {
+	if resp == nil || resp.StatusCode >= http.StatusOK && resp.StatusCode < http.StatusMultipleChoices {
+		return nil
+	}
+
+	status := resp.Status
+	if status == "" {
+		status = strconv.Itoa(resp.StatusCode) + " " + http.StatusText(resp.StatusCode)
+	}
+
+	fields := errors.Fields{
+		StatusField: resp.StatusCode,
+	}
+
+	if req := resp.Request; req != nil {
+		u := *req.URL
+		u.User = nil
+
+		fields[MethodField] = req.Method
+		fields[URLField] = u.String()
+	}
+
+	if snippet := bodySnippet(resp); snippet != "" {
+		fields[BodyField] = snippet
+	}
+
+	if d, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+		fields[RetryAfterField] = d
+	}
+
+	err := errors.Errorf("unexpected response status %s", status)
+
+	return errors.WithFields(errors.WithKind(err, Kind(resp.StatusCode)), fields)
+}
+
+// RetryAfter returns the delay requested by the server that answered with
+// err, as parsed by FromResponse.
+func RetryAfter(err error) (time.Duration, bool) {
+	d, ok := errors.GetFields(err)[RetryAfterField].(time.Duration)
+
+	return d, ok
+}
+
+// Kind returns the kind matching the HTTP status code status.
+// The statuses without a specific kind are classified by their class.
+func Kind(status int) errors.Kind {
+	switch status {
+	case http.StatusBadRequest:
+		return errors.KindInvalidArgument
+	case http.StatusUnauthorized:
+		return errors.KindUnauthenticated
+	case http.StatusForbidden:
+		return errors.KindPermissionDenied
+	case http.StatusNotFound, http.StatusGone:
+		return errors.KindNotFound
+	case http.StatusConflict:
+		return errors.KindAborted
+	case http.StatusPreconditionFailed:
+		return errors.KindFailedPrecondition
+	case http.StatusRequestedRangeNotSatisfiable:
+		return errors.KindOutOfRange
+	case http.StatusTooManyRequests:
+		return errors.KindResourceExhausted
+	case statusClientClosedRequest:
+		return errors.KindCanceled
+	case http.StatusNotImplemented:
+		return errors.KindUnimplemented
+	case http.StatusBadGateway, http.StatusServiceUnavailable:
+		return errors.KindUnavailable
+	case http.StatusGatewayTimeout:
+		return errors.KindDeadlineExceeded
+	}
+
+	switch {
+	case status >= http.StatusBadRequest && status < http.StatusInternalServerError:
+		return errors.KindFailedPrecondition
+	case status >= http.StatusInternalServerError && status < maxStatus:
+		return errors.KindInternal
+	default:
+		return errors.KindUnknown
+	}
+}
+
+// statusClientClosedRequest is the non standard status used by nginx when
+// the client closes the connection before the response is sent.
+const statusClientClosedRequest = 499
+
+// maxStatus is the upper bound of the HTTP status codes.
+const maxStatus = 600
+
+// bodySnippet returns the beginning of the body of resp and puts it back in
+// front of the body.
+func bodySnippet(resp *http.Response) string {
+	if resp.Body == nil || resp.Body == http.NoBody {
+		return ""
+	}
+
+	buf := make([]byte, MaxBodySnippet+1)
+	n, _ := io.ReadFull(resp.Body, buf)
+	buf = buf[:n]
+
+	resp.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.MultiReader(bytes.NewReader(buf), resp.Body), resp.Body}
+
+	if n <= MaxBodySnippet {
+		return string(buf)
+	}
+
+	// Do not cut the last character in the middle.
+	snippet := buf[:MaxBodySnippet]
+	for i := 1; i < utf8.UTFMax && i <= len(snippet); i++ {
+		if utf8.RuneStart(snippet[len(snippet)-i]) {
+			if !utf8.FullRune(snippet[len(snippet)-i:]) {
+				snippet = snippet[:len(snippet)-i]
+			}
+
+			break
+		}
+	}
+
+	return string(snippet) + "…"
+}
+
+// parseRetryAfter parses the value of a Retry-After header, either a number
+// of seconds or an HTTP date, into a delay from now.
+func parseRetryAfter(v string, now time.Time) (time.Duration, bool) {
+	if v == "" {
+		return 0, false
+	}
+
+	if s, err := strconv.Atoi(v); err == nil && s >= 0 {
+		return time.Duration(s) * time.Second, true
+	}
+
+	t, err := http.ParseTime(v)
+	if err != nil {
+		return 0, false
+	}
+
+	if d := t.Sub(now); d > 0 {
+		return d, true
+	}
+
+	return 0, true
+}
@@ -0,0 +1,94 @@
+package httpx
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/hexbee-net/errors"
+	"github.com/hexbee-net/errors/netx"
+)
+
+// Fields added to the errors returned by Transport.
+const (
+	URLField     = "http.url"
+	HostField    = "http.host"
+	AttemptField = "http.attempt"
+	ClassField   = "http.error_class"
+)
+
+// Classes of the transport errors, reported in the ClassField field.
+// See package netx for the complete list.
+const (
+	ClassTimeout           = netx.ClassTimeout
+	ClassCanceled          = netx.ClassCanceled
+	ClassDNS               = netx.ClassDNS
+	ClassTLS               = netx.ClassTLS
+	ClassConnectionRefused = netx.ClassConnectionRefused
+)
+
+type attemptKey struct{}
+
+// WithAttempt returns a copy of ctx recording the attempt number of the
+// requests made with it, for the retrying clients to report their attempts.
+func WithAttempt(ctx context.Context, attempt int) context.Context {
+	return context.WithValue(ctx, attemptKey{}, attempt)
+}
+
+// Attempt returns the attempt number recorded in ctx by WithAttempt.
+// If none is recorded, the request is the first attempt and 1 is returned.
+func Attempt(ctx context.Context) int {
+	if n, ok := ctx.Value(attemptKey{}).(int); ok {
+		return n
+	}
+
+	return 1
+}
+
+// Transport is an http.RoundTripper annotating the errors of another one.
+// The errors get the method, URL (without credentials), host and attempt of
+// the request as fields, and their class as both a field and a kind.
+//
+// http.Client wraps the errors of its transport in *url.Error values, which
+// do not implement Cause: the annotated error is the Err member of the
+// *url.Error.
+type Transport struct {
+	// Base is the transport making the requests.
+	// If Base is nil, http.DefaultTransport is used.
+	Base http.RoundTripper
+}
+
+// RoundTrip implements http.RoundTripper.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.Base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		return nil, annotate(req, err)
+	}
+
+	return resp, nil
+}
+
+func annotate(req *http.Request, err error) error {
+	u := *req.URL
+	u.User = nil
+
+	fields := errors.Fields{
+		MethodField:  req.Method,
+		URLField:     u.String(),
+		HostField:    req.URL.Host,
+		AttemptField: Attempt(req.Context()),
+	}
+
+	err = errors.WithStack(err)
+
+	if class := netx.Class(err); class != "" {
+		fields[ClassField] = class
+		err = errors.WithKind(err, netx.Classify(err))
+	}
+
+	return errors.WithFields(err, fields)
+}
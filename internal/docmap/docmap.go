@@ -0,0 +1,219 @@
+// Package docmap converts error documents to and from trees of generic values,
+// for the encodings that have no direct support for Go structures.
+//
+// The trees only hold nil, bool, int64, uint64, float64, string, []byte,
+// []interface{} and map[string]interface{} values, and use the keys of the
+// JSON representation.
+package docmap
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+
+	"github.com/hexbee-net/errors"
+)
+
+// ErrInvalid is returned when a tree does not describe a document.
+const ErrInvalid = errors.Error("invalid error document")
+
+// Encode returns the tree describing doc.
+func Encode(doc errors.Document) map[string]interface{} {
+	layers := make([]interface{}, len(doc.Layers))
+
+	for i, l := range doc.Layers {
+		layer := map[string]interface{}{
+			"message": l.Message,
+		}
+
+		if l.Kind != "" {
+			layer["kind"] = string(l.Kind)
+		}
+
+		if l.Code != "" {
+			layer["code"] = l.Code
+		}
+
+		if l.UserMessage != "" {
+			layer["user_message"] = l.UserMessage
+		}
+
+		if l.Status != 0 {
+			layer["status"] = int64(l.Status)
+		}
+
+		if len(l.Fields) > 0 {
+			layer["fields"] = Normalize(map[string]interface{}(l.Fields))
+		}
+
+		if len(l.Stack) > 0 {
+			stack := make([]interface{}, len(l.Stack))
+			for j, f := range l.Stack {
+				stack[j] = map[string]interface{}{
+					"function": f.Function,
+					"file":     f.File,
+					"line":     int64(f.Line),
+				}
+			}
+
+			layer["stack"] = stack
+		}
+
+		layers[i] = layer
+	}
+
+	return map[string]interface{}{
+		"message": doc.Message,
+		"layers":  layers,
+	}
+}
+
+// Decode returns the document described by tree.
+func Decode(tree interface{}) (errors.Document, error) {
+	var doc errors.Document
+
+	root, ok := tree.(map[string]interface{})
+	if !ok {
+		return doc, errors.Wrapf(ErrInvalid, "document is a %T", tree)
+	}
+
+	doc.Message, _ = root["message"].(string)
+
+	layers, ok := root["layers"].([]interface{})
+	if !ok && root["layers"] != nil {
+		return doc, errors.Wrapf(ErrInvalid, "layers is a %T", root["layers"])
+	}
+
+	doc.Layers = make([]errors.Layer, len(layers))
+
+	for i, v := range layers {
+		l, err := decodeLayer(v)
+		if err != nil {
+			return doc, errors.WithMessagef(err, "layer %d", i)
+		}
+
+		doc.Layers[i] = l
+	}
+
+	return doc, nil
+}
+
+func decodeLayer(v interface{}) (errors.Layer, error) {
+	var l errors.Layer
+
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return l, errors.Wrapf(ErrInvalid, "layer is a %T", v)
+	}
+
+	l.Message, _ = m["message"].(string)
+
+	if kind, ok := m["kind"].(string); ok {
+		l.Kind = errors.Kind(kind)
+	}
+
+	l.Code, _ = m["code"].(string)
+	l.UserMessage, _ = m["user_message"].(string)
+	l.Status = toInt(m["status"])
+
+	if fields, ok := m["fields"].(map[string]interface{}); ok && len(fields) > 0 {
+		l.Fields = errors.Fields(fields)
+	}
+
+	stack, _ := m["stack"].([]interface{})
+	for _, fv := range stack {
+		f, ok := fv.(map[string]interface{})
+		if !ok {
+			return l, errors.Wrapf(ErrInvalid, "frame is a %T", fv)
+		}
+
+		frame := errors.StackFrame{}
+		frame.Function, _ = f["function"].(string)
+		frame.File, _ = f["file"].(string)
+		frame.Line = toInt(f["line"])
+
+		l.Stack = append(l.Stack, frame)
+	}
+
+	return l, nil
+}
+
+func toInt(v interface{}) int {
+	switch v := v.(type) {
+	case int64:
+		return int(v)
+	case uint64:
+		return int(v)
+	case float64:
+		return int(v)
+	default:
+		return 0
+	}
+}
+
+// Normalize converts v into a tree of generic values.
+// Values of unsupported types are replaced by their default format.
+func Normalize(v interface{}) interface{} {
+	switch v := v.(type) {
+	case nil, bool, int64, uint64, float64, string, []byte:
+		return v
+	case error:
+		return v.Error()
+	case fmt.Stringer:
+		return v.String()
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() { //nolint:exhaustive // other kinds are formatted
+	case reflect.Bool:
+		return rv.Bool()
+	case reflect.String:
+		return rv.String()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return rv.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return rv.Uint()
+	case reflect.Float32, reflect.Float64:
+		return rv.Float()
+	case reflect.Map:
+		if rv.Type().Key().Kind() != reflect.String {
+			break
+		}
+
+		m := make(map[string]interface{}, rv.Len())
+
+		iter := rv.MapRange()
+		for iter.Next() {
+			m[iter.Key().String()] = Normalize(iter.Value().Interface())
+		}
+
+		return m
+	case reflect.Slice, reflect.Array:
+		if rv.Kind() == reflect.Slice && rv.Type().Elem().Kind() == reflect.Uint8 {
+			return rv.Bytes()
+		}
+
+		list := make([]interface{}, rv.Len())
+		for i := range list {
+			list[i] = Normalize(rv.Index(i).Interface())
+		}
+
+		return list
+	}
+
+	return fmt.Sprint(v)
+}
+
+// SortedKeys returns the keys of m in increasing order, so that encodings
+// are deterministic.
+func SortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	return keys
+}
@@ -0,0 +1,9 @@
+//go:build !nostack
+
+// Package nostack reports whether the nostack build tag is set, for the tests
+// of the adapters checking the stack traces of the errors they convert.
+package nostack
+
+// Enabled reports whether the capture of stack traces is compiled out by the
+// nostack build tag.
+const Enabled = false
@@ -0,0 +1,7 @@
+//go:build nostack
+
+package nostack
+
+// Enabled reports whether the capture of stack traces is compiled out by the
+// nostack build tag.
+const Enabled = true
@@ -0,0 +1,228 @@
+package errors
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Document is the structured representation of an error chain.
+type Document struct {
+	// Message is the complete message of the error, as returned by Error.
+	Message string `json:"message" yaml:"message"`
+	// Layers lists the levels of the chain, outermost first.
+	Layers []Layer `json:"layers" yaml:"layers"`
+}
+
+// Layer is one level of an error chain: a message and the annotations
+// (fields, kind, code, user message, HTTP status and stack trace) attached
+// along with it.
+type Layer struct {
+	Message     string       `json:"message" yaml:"message"`
+	Kind        Kind         `json:"kind,omitempty" yaml:"kind,omitempty"`
+	Code        string       `json:"code,omitempty" yaml:"code,omitempty"`
+	UserMessage string       `json:"user_message,omitempty" yaml:"user_message,omitempty"`
+	Status      int          `json:"status,omitempty" yaml:"status,omitempty"`
+	Fields      Fields       `json:"fields,omitempty" yaml:"fields,omitempty"`
+	Stack       []StackFrame `json:"stack,omitempty" yaml:"stack,omitempty"`
+}
+
+// NewDocument returns the structured representation of err.
+// A layer starts at every error adding a message (Wrap, WithMessage, New, a
+// foreign error...) and includes the annotations found directly above it.
+// If err is nil, a document without layers will be returned.
+func NewDocument(err error) Document {
+	type fielder interface {
+		Fields() Fields
+	}
+
+	type kinder interface {
+		Kind() Kind
+	}
+
+	type coder interface {
+		Code() string
+	}
+
+	type userMessager interface {
+		UserMessage() string
+	}
+
+	type statuser interface {
+		HTTPStatus() int
+	}
+
+	doc := Document{Layers: make([]Layer, 0)}
+	if err == nil {
+		return doc
+	}
+
+	doc.Message = err.Error()
+
+	var cur Layer
+
+	for depth := 0; err != nil && depth < MaxChainDepth(); depth++ {
+		if f, ok := err.(fielder); ok {
+			if cur.Fields == nil {
+				cur.Fields = make(Fields)
+			}
+
+			for k, v := range f.Fields() {
+				cur.Fields[k] = v
+			}
+		}
+
+		if k, ok := err.(kinder); ok && cur.Kind == "" {
+			cur.Kind = k.Kind()
+		}
+
+		if c, ok := err.(coder); ok && cur.Code == "" {
+			cur.Code = c.Code()
+		}
+
+		if u, ok := err.(userMessager); ok && cur.UserMessage == "" {
+			cur.UserMessage = u.UserMessage()
+		}
+
+		if s, ok := err.(statuser); ok && cur.Status == 0 {
+			cur.Status = s.HTTPStatus()
+		}
+
+		if cur.Stack == nil {
+			cur.Stack = layerStack(err)
+		}
+
+		if msg, ok := ownMessage(err); ok {
+			cur.Message = msg
+			doc.Layers = append(doc.Layers, cur)
+			cur = Layer{}
+		}
+
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+
+		err = cause.Cause()
+	}
+
+	return doc
+}
+
+// layerStack returns the frames of the stack trace carried by err itself.
+func layerStack(err error) []StackFrame {
+	switch v := err.(type) {
+	case *fundamental:
+		return v.stack.resolved()
+	case *withStack:
+		return v.stack.resolved()
+	case *RemoteError:
+		return v.stack
+	case stackTracer:
+		return resolve(v.StackTrace())
+	}
+
+	return nil
+}
+
+// ownMessage returns the message err adds on top of its cause.
+// It reports false for the wrappers that only annotate their cause.
+func ownMessage(err error) (string, bool) {
+	switch v := err.(type) {
+	case *fundamental:
+		return v.message(), true
+	case *withMessage:
+		return v.message(), true
+	case *RemoteError:
+		return v.msg, true
+	case *withStack, *withFields, *withFieldList, *withKind, *withCode, *withUserMessage, *withHTTPStatus:
+		return "", false
+	}
+
+	cause, ok := err.(causer)
+	if !ok || cause.Cause() == nil {
+		return err.Error(), true
+	}
+
+	msg, inner := err.Error(), cause.Cause().Error()
+	if msg == inner {
+		return "", false
+	}
+
+	return strings.TrimSuffix(msg, ": "+inner), true
+}
+
+// ToJSON returns the JSON encoding of the structured representation of err.
+// Field values are capped by the limits set by SetFieldLimits, and the ones
+// that cannot be encoded are replaced by their default format.
+func ToJSON(err error) ([]byte, error) {
+	doc := NewDocument(err)
+
+	for i := range doc.Layers {
+		doc.Layers[i].Fields = jsonFields(doc.Layers[i].Fields)
+	}
+
+	return json.Marshal(doc)
+}
+
+// JSONFields returns a copy of f ready to be encoded in JSON, as found in the
+// documents of ToJSON: the values are capped by the limits set with
+// SetFieldLimits, and the ones that cannot be encoded, or whose encoding
+// panics, are replaced by their rendering by FormatField.
+// If f is empty, JSONFields returns nil.
+func JSONFields(f Fields) Fields {
+	if len(f) == 0 {
+		return nil
+	}
+
+	out := make(Fields, len(f))
+	for k, v := range f {
+		out[k] = v
+	}
+
+	return jsonFields(out)
+}
+
+func jsonFields(f Fields) Fields {
+	limits := GetFieldLimits()
+
+	for k, v := range f {
+		v = limits.Limit(k, v)
+		f[k] = v
+
+		switch ok, panicked := jsonEncodable(v); {
+		case panicked:
+			f[k] = panicPlaceholder(k)
+		case !ok:
+			f[k] = FormatField(k, v)
+		}
+	}
+
+	return f
+}
+
+// MarshalJSON implements json.Marshaler.
+func (f *fundamental) MarshalJSON() ([]byte, error) { return ToJSON(f) }
+
+// MarshalJSON implements json.Marshaler.
+func (w *withStack) MarshalJSON() ([]byte, error) { return ToJSON(w) }
+
+// MarshalJSON implements json.Marshaler.
+func (w *withMessage) MarshalJSON() ([]byte, error) { return ToJSON(w) }
+
+// MarshalJSON implements json.Marshaler.
+func (w *withFields) MarshalJSON() ([]byte, error) { return ToJSON(w) }
+
+// MarshalJSON implements json.Marshaler.
+func (w *withFieldList) MarshalJSON() ([]byte, error) { return ToJSON(w) }
+
+// MarshalJSON implements json.Marshaler.
+func (w *withKind) MarshalJSON() ([]byte, error) { return ToJSON(w) }
+
+// MarshalJSON implements json.Marshaler.
+func (w *withCode) MarshalJSON() ([]byte, error) { return ToJSON(w) }
+
+// MarshalJSON implements json.Marshaler.
+func (w *withUserMessage) MarshalJSON() ([]byte, error) { return ToJSON(w) }
+
+// MarshalJSON implements json.Marshaler.
+func (w *withHTTPStatus) MarshalJSON() ([]byte, error) { return ToJSON(w) }
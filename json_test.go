@@ -0,0 +1,130 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNewDocument(t *testing.T) {
+	tests := []struct {
+		err      error
+		message  string
+		messages []string
+	}{
+		{nil, "", []string{}},
+		{io.EOF, "EOF", []string{"EOF"}},
+		{New("boom"), "boom", []string{"boom"}},
+		{Wrap(io.EOF, "read error"), "read error: EOF", []string{"read error", "EOF"}},
+		{WithField(WithStack(io.EOF), "key", "value"), "EOF", []string{"EOF"}},
+		{WithMessage(fmt.Errorf("inner: %w", io.EOF), "outer"), "outer: inner: EOF", []string{"outer", "inner: EOF"}},
+	}
+
+	for i, tt := range tests {
+		doc := NewDocument(tt.err)
+
+		messages := make([]string, len(doc.Layers))
+		for j, l := range doc.Layers {
+			messages[j] = l.Message
+		}
+
+		assert.Equal(t, tt.message, doc.Message, "test %d", i+1)
+		assert.Equal(t, tt.messages, messages, "test %d", i+1)
+	}
+}
+
+func TestNewDocumentAnnotations(t *testing.T) {
+	err := WithCode(
+		WithKind(
+			WithField(Wrap(WithField(New("boom"), "inner", 1), "read error"), "outer", 2),
+			KindNotFound,
+		),
+		"file.missing",
+	)
+
+	doc := NewDocument(err)
+
+	assert.Len(t, doc.Layers, 2)
+
+	outer := doc.Layers[0]
+	assert.Equal(t, "read error", outer.Message)
+	assert.Equal(t, KindNotFound, outer.Kind)
+	assert.Equal(t, "file.missing", outer.Code)
+	assert.Equal(t, Fields{"outer": 2}, outer.Fields)
+
+	if stacksCompiled && assert.NotEmpty(t, outer.Stack) {
+		assert.Equal(t, "github.com/hexbee-net/errors.TestNewDocumentAnnotations", outer.Stack[0].Function)
+	}
+
+	inner := doc.Layers[1]
+	assert.Equal(t, "boom", inner.Message)
+	assert.Equal(t, Kind(""), inner.Kind)
+	assert.Equal(t, "", inner.Code)
+	assert.Equal(t, Fields{"inner": 1}, inner.Fields)
+	assert.Equal(t, stacksCompiled, len(inner.Stack) > 0)
+}
+
+func TestJSONFields(t *testing.T) {
+	f := Fields{"id": 42, "bad": panicky{}, "ch": make(chan int)}
+
+	out := JSONFields(f)
+
+	assert.Equal(t, 42, out["id"])
+	assert.Equal(t, `<panic rendering field "bad">`, out["bad"])
+	assert.IsType(t, "", out["ch"])
+	assert.Equal(t, panicky{}, f["bad"], "the fields given are not modified")
+	assert.Nil(t, JSONFields(nil))
+}
+
+func TestToJSON(t *testing.T) {
+	err := WithKind(WithFields(WithMessage(io.EOF, "read error"), Fields{
+		"file":    "data.txt",
+		"handler": make(chan int),
+	}), KindDataLoss)
+
+	b, e := ToJSON(err)
+	assert.NoError(t, e)
+
+	var doc map[string]interface{}
+
+	assert.NoError(t, json.Unmarshal(b, &doc))
+
+	handler := doc["layers"].([]interface{})[0].(map[string]interface{})["fields"].(map[string]interface{})["handler"]
+	assert.IsType(t, "", handler)
+
+	b, e = json.Marshal(struct {
+		Err error `json:"err"`
+	}{WithMessage(io.EOF, "read error")})
+	assert.NoError(t, e)
+	assert.JSONEq(t, `{"err": {
+		"message": "read error: EOF",
+		"layers": [{"message": "read error"}, {"message": "EOF"}]
+	}}`, string(b))
+}
+
+func TestMarshalJSON(t *testing.T) {
+	errs := []error{
+		New("boom"),
+		WithStack(io.EOF),
+		WithMessage(io.EOF, "read error"),
+		WithField(io.EOF, "key", "value"),
+		WithKind(io.EOF, KindInternal),
+		WithCode(io.EOF, "code"),
+		WithUserMessage(io.EOF, "message"),
+		WithHTTPStatus(io.EOF, http.StatusTeapot),
+	}
+
+	for _, err := range errs {
+		want, e := ToJSON(err)
+		assert.NoError(t, e)
+
+		got, e := json.Marshal(err)
+		assert.NoError(t, e)
+		assert.Equal(t, want, got)
+		assert.NotEqual(t, "{}", string(got))
+	}
+}
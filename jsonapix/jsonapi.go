@@ -0,0 +1,232 @@
+// Package jsonapix renders error chains as JSON:API error objects
+// (https://jsonapi.org/format/#error-objects):
+//
+//     w.Header().Set("Content-Type", jsonapix.ContentType)
+//     w.WriteHeader(errors.HTTPStatus(err))
+//     _ = json.NewEncoder(w).Encode(jsonapix.NewDocument(err))
+//
+// Errors aggregating several errors, through an `Unwrap() []error` or an
+// `Errors() []error` method, are fanned out into one object per member.
+package jsonapix
+
+import (
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/hexbee-net/errors"
+)
+
+// ContentType is the media type of JSON:API documents.
+const ContentType = "application/vnd.api+json"
+
+// Error is a JSON:API error object.
+type Error struct {
+	ID     string                 `json:"id,omitempty"`
+	Status string                 `json:"status,omitempty"`
+	Code   string                 `json:"code,omitempty"`
+	Title  string                 `json:"title,omitempty"`
+	Detail string                 `json:"detail,omitempty"`
+	Meta   map[string]interface{} `json:"meta,omitempty"`
+}
+
+// Document is a JSON:API top-level document holding errors.
+type Document struct {
+	Errors []Error `json:"errors"`
+}
+
+// NewDocument returns the JSON:API document describing err.
+func NewDocument(err error) Document {
+	return Document{Errors: Errors(err)}
+}
+
+// Errors returns the JSON:API error objects describing err.
+// Every object gets a random ID, the HTTP status and code of the error, the
+// text of the status as title, the user message as detail and the fields as
+// meta. Internal messages are never included.
+// The members of aggregated errors inherit the annotations they lack from the
+// layers wrapping the aggregate.
+// If err is nil, an empty slice will be returned.
+func Errors(err error) []Error {
+	if err == nil {
+		return make([]Error, 0)
+	}
+
+	return appendErrors(make([]Error, 0, 1), err, annotations{})
+}
+
+func appendErrors(dst []Error, err error, outer annotations) []Error {
+	a, members := collect(err)
+	a.inherit(outer)
+
+	if members == nil {
+		return append(dst, a.object())
+	}
+
+	for _, m := range members {
+		if m != nil {
+			dst = appendErrors(dst, m, a)
+		}
+	}
+
+	return dst
+}
+
+// annotations are the parts of a chain described by an error object.
+type annotations struct {
+	kind   errors.Kind
+	code   string
+	status int
+	detail string
+	fields errors.Fields
+}
+
+type causer interface {
+	Cause() error
+}
+
+// collect returns the annotations of the layers of err down to the first
+// aggregate, along with the members of that aggregate.
+// The outermost kind, code, status and user message win, while the fields of
+// the inner layers override the outer ones, like in errors.GetFields.
+func collect(err error) (annotations, []error) {
+	type kinder interface {
+		Kind() errors.Kind
+	}
+
+	type coder interface {
+		Code() string
+	}
+
+	type statuser interface {
+		HTTPStatus() int
+	}
+
+	type userMessager interface {
+		UserMessage() string
+	}
+
+	type fielder interface {
+		Fields() errors.Fields
+	}
+
+	var a annotations
+
+	for depth := 0; err != nil && depth < errors.MaxChainDepth(); depth++ {
+		if k, ok := err.(kinder); ok && a.kind == "" {
+			a.kind = k.Kind()
+		}
+
+		if c, ok := err.(coder); ok && a.code == "" {
+			a.code = c.Code()
+		}
+
+		if s, ok := err.(statuser); ok && a.status == 0 {
+			a.status = s.HTTPStatus()
+		}
+
+		if u, ok := err.(userMessager); ok && a.detail == "" {
+			a.detail = u.UserMessage()
+		}
+
+		if f, ok := err.(fielder); ok {
+			if a.fields == nil {
+				a.fields = make(errors.Fields)
+			}
+
+			for k, v := range f.Fields() {
+				a.fields[k] = v
+			}
+		}
+
+		if members := unwrapMulti(err); members != nil {
+			return a, members
+		}
+
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+
+		err = cause.Cause()
+	}
+
+	return a, nil
+}
+
+// unwrapMulti returns the errors aggregated by err, if any.
+func unwrapMulti(err error) []error {
+	switch v := err.(type) {
+	case interface{ Unwrap() []error }:
+		return v.Unwrap()
+	case interface{ Errors() []error }:
+		return v.Errors()
+	}
+
+	return nil
+}
+
+// inherit fills the annotations missing from a with the ones of outer.
+func (a *annotations) inherit(outer annotations) {
+	// The status of a member comes from its own kind before the outer layers.
+	if a.status == 0 && (a.kind == "" || a.kind == errors.KindUnknown) {
+		a.kind = outer.kind
+		a.status = outer.status
+	}
+
+	if a.code == "" {
+		a.code = outer.code
+	}
+
+	if a.detail == "" {
+		a.detail = outer.detail
+	}
+
+	if len(outer.fields) > 0 {
+		fields := make(errors.Fields, len(outer.fields)+len(a.fields))
+
+		for k, v := range outer.fields {
+			fields[k] = v
+		}
+
+		for k, v := range a.fields {
+			fields[k] = v
+		}
+
+		a.fields = fields
+	}
+}
+
+func (a *annotations) object() Error {
+	status := a.status
+	if status == 0 {
+		status = errors.KindHTTPStatus(a.kind)
+	}
+
+	e := Error{
+		ID:     newID(),
+		Status: strconv.Itoa(status),
+		Code:   a.code,
+		Title:  http.StatusText(status),
+		Detail: a.detail,
+	}
+
+	if len(a.fields) > 0 {
+		e.Meta = a.fields
+	}
+
+	return e
+}
+
+// newID returns a random (version 4) UUID.
+func newID() string {
+	var b [16]byte
+
+	_, _ = rand.Read(b[:])
+
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:])
+}
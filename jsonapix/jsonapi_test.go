@@ -0,0 +1,117 @@
+package jsonapix
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+type multi []error
+
+func (m multi) Error() string   { return "multiple errors" }
+func (m multi) Unwrap() []error { return m }
+
+type legacyMulti []error
+
+func (m legacyMulti) Error() string   { return "multiple errors" }
+func (m legacyMulti) Errors() []error { return m }
+
+var uuid = regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+
+// withoutIDs checks the IDs of errs and clears them.
+func withoutIDs(t *testing.T, errs []Error) []Error {
+	t.Helper()
+
+	for i := range errs {
+		assert.Regexp(t, uuid, errs[i].ID)
+		errs[i].ID = ""
+	}
+
+	return errs
+}
+
+func TestErrorsNil(t *testing.T) {
+	assert.Equal(t, []Error{}, Errors(nil))
+}
+
+func TestErrors(t *testing.T) {
+	err := errors.WithUserMessage(
+		errors.WithCode(
+			errors.WithKind(errors.WithField(errors.Wrap(io.EOF, "read error"), "file", "data.txt"), errors.KindNotFound),
+			"file.missing",
+		),
+		"The file does not exist.",
+	)
+
+	assert.Equal(t, []Error{{
+		Status: "404",
+		Code:   "file.missing",
+		Title:  "Not Found",
+		Detail: "The file does not exist.",
+		Meta:   map[string]interface{}{"file": "data.txt"},
+	}}, withoutIDs(t, Errors(err)))
+
+	assert.Equal(t, []Error{{
+		Status: "500",
+		Title:  "Internal Server Error",
+	}}, withoutIDs(t, Errors(io.EOF)))
+}
+
+func TestErrorsMulti(t *testing.T) {
+	err := errors.WithHTTPStatus(
+		errors.WithField(multi{
+			errors.WithField(errors.WithKind(io.EOF, errors.KindInvalidArgument), "field", "name"),
+			nil,
+			legacyMulti{
+				errors.WithCode(io.EOF, "age.missing"),
+				errors.WithField(io.EOF, "request", "overridden"),
+			},
+		}, "request", "42"),
+		http.StatusUnprocessableEntity,
+	)
+	err = errors.WithCode(err, "validation")
+
+	assert.Equal(t, []Error{
+		{
+			Status: "400",
+			Code:   "validation",
+			Title:  "Bad Request",
+			Meta:   map[string]interface{}{"field": "name", "request": "42"},
+		},
+		{
+			Status: "422",
+			Code:   "age.missing",
+			Title:  "Unprocessable Entity",
+			Meta:   map[string]interface{}{"request": "42"},
+		},
+		{
+			Status: "422",
+			Code:   "validation",
+			Title:  "Unprocessable Entity",
+			Meta:   map[string]interface{}{"request": "overridden"},
+		},
+	}, withoutIDs(t, Errors(err)))
+}
+
+func TestNewDocument(t *testing.T) {
+	doc := NewDocument(errors.WithKind(io.EOF, errors.KindUnavailable))
+	doc.Errors = withoutIDs(t, doc.Errors)
+
+	data, err := json.Marshal(doc)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"errors": [{"status": "503", "title": "Service Unavailable"}]}`, string(data))
+
+	data, err = json.Marshal(NewDocument(nil))
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"errors": []}`, string(data))
+}
+
+func TestNewID(t *testing.T) {
+	assert.Regexp(t, uuid, newID())
+	assert.NotEqual(t, newID(), newID())
+}
@@ -0,0 +1,121 @@
+// Package jsonrpcx converts error chains to and from JSON-RPC 2.0 error
+// objects (https://www.jsonrpc.org/specification#error_object).
+//
+// The structured representation of the chain, as built by errors.NewDocument,
+// is carried in the data member so that clients can rebuild it with FromError.
+package jsonrpcx
+
+import (
+	"encoding/json"
+
+	"github.com/hexbee-net/errors"
+)
+
+// Error codes defined by the specification.
+const (
+	CodeParseError     = -32700
+	CodeInvalidRequest = -32600
+	CodeMethodNotFound = -32601
+	CodeInvalidParams  = -32602
+	CodeInternalError  = -32603
+)
+
+// Error is a JSON-RPC error object.
+type Error struct {
+	Code    int             `json:"code"`
+	Message string          `json:"message"`
+	Data    json.RawMessage `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// ToError returns the JSON-RPC error object describing err.
+// The code is derived from the kind of the chain and the message is its user
+// message or, without one, its complete message. The data member holds the
+// structured representation of the chain, without stack traces unless
+// withStack is set.
+// If err is nil, ToError returns nil.
+func ToError(err error, withStack bool) *Error {
+	if err == nil {
+		return nil
+	}
+
+	obj := &Error{
+		Code:    Code(errors.GetKind(err)),
+		Message: errors.GetUserMessage(err),
+	}
+
+	if obj.Message == "" {
+		obj.Message = err.Error()
+	}
+
+	doc := errors.NewDocument(err)
+
+	if !withStack {
+		for i := range doc.Layers {
+			doc.Layers[i].Stack = nil
+		}
+	}
+
+	// The document is encoded through its remote counterpart, which
+	// replaces the field values that cannot be encoded.
+	data, e := errors.ToJSON(errors.FromDocument(doc))
+	if e == nil {
+		obj.Data = data
+	}
+
+	return obj
+}
+
+// FromError rebuilds the error chain described by e.
+// The chain is decoded from the data member when it holds a document;
+// otherwise a single error is built from the message. If the outermost layer
+// has no kind, the one matching the code of e is used.
+// If e is nil, FromError returns nil.
+func FromError(e *Error) error {
+	if e == nil {
+		return nil
+	}
+
+	var doc errors.Document
+	if len(e.Data) == 0 || json.Unmarshal(e.Data, &doc) != nil || len(doc.Layers) == 0 {
+		doc = errors.Document{Layers: []errors.Layer{{Message: e.Message}}}
+	}
+
+	if doc.Layers[0].Kind == "" {
+		doc.Layers[0].Kind = Kind(e.Code)
+	}
+
+	return errors.FromDocument(doc)
+}
+
+// Code returns the JSON-RPC error code matching k.
+// The kinds without a matching code are reported as internal errors.
+func Code(k errors.Kind) int {
+	switch k { //nolint:exhaustive // other kinds are internal errors
+	case errors.KindInvalidArgument:
+		return CodeInvalidParams
+	case errors.KindUnimplemented:
+		return CodeMethodNotFound
+	default:
+		return CodeInternalError
+	}
+}
+
+// Kind returns the kind matching the JSON-RPC error code c.
+// Codes reserved for implementation-defined server errors, and the codes
+// defined by applications, are reported as KindUnknown.
+func Kind(c int) errors.Kind {
+	switch c {
+	case CodeParseError, CodeInvalidRequest, CodeInvalidParams:
+		return errors.KindInvalidArgument
+	case CodeMethodNotFound:
+		return errors.KindUnimplemented
+	case CodeInternalError:
+		return errors.KindInternal
+	default:
+		return errors.KindUnknown
+	}
+}
@@ -0,0 +1,92 @@
+package jsonrpcx
+
+import (
+	"encoding/json"
+	stderrors "errors"
+	"io"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/hexbee-net/errors/internal/nostack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestToErrorNil(t *testing.T) {
+	assert.Nil(t, ToError(nil, false))
+	assert.Nil(t, FromError(nil))
+}
+
+func TestToError(t *testing.T) {
+	err := errors.WithCode(
+		errors.WithKind(errors.WithField(errors.Wrap(io.EOF, "read error"), "ch", make(chan int)), errors.KindInvalidArgument),
+		"params.missing",
+	)
+
+	obj := ToError(err, false)
+
+	assert.Equal(t, CodeInvalidParams, obj.Code)
+	assert.Equal(t, "read error: EOF", obj.Message)
+	assert.Equal(t, "read error: EOF", obj.Error())
+	assert.NotContains(t, string(obj.Data), `"stack"`)
+
+	var doc errors.Document
+	assert.NoError(t, json.Unmarshal(obj.Data, &doc))
+	assert.Len(t, doc.Layers, 2)
+	assert.Equal(t, "params.missing", doc.Layers[0].Code)
+	assert.IsType(t, "", doc.Layers[0].Fields["ch"])
+
+	obj = ToError(errors.WithUserMessage(err, "The name is missing."), true)
+	assert.Equal(t, "The name is missing.", obj.Message)
+
+	if !nostack.Enabled {
+		assert.Contains(t, string(obj.Data), `"stack"`)
+	}
+
+	assert.Equal(t, CodeInternalError, ToError(io.EOF, false).Code)
+}
+
+func TestFromError(t *testing.T) {
+	err := errors.WithCode(errors.WithField(errors.Wrap(io.EOF, "read error"), "id", "42"), "user.not_found")
+
+	data, e := json.Marshal(ToError(err, false))
+	assert.NoError(t, e)
+
+	var obj Error
+	assert.NoError(t, json.Unmarshal(data, &obj))
+
+	got := FromError(&obj)
+
+	assert.Equal(t, "read error: EOF", got.Error())
+	assert.Equal(t, errors.KindInternal, errors.GetKind(got))
+	assert.Equal(t, "user.not_found", errors.GetCode(got))
+	assert.Equal(t, errors.Fields{"id": "42"}, errors.GetFields(got))
+	assert.True(t, stderrors.Is(got, errors.WithCode(io.EOF, "user.not_found")))
+}
+
+func TestFromErrorForeign(t *testing.T) {
+	tests := []struct {
+		obj  Error
+		kind errors.Kind
+	}{
+		{Error{Code: CodeMethodNotFound, Message: "Method not found"}, errors.KindUnimplemented},
+		{Error{Code: CodeParseError, Message: "Parse error", Data: json.RawMessage(`"unexpected EOF"`)}, errors.KindInvalidArgument},
+		{Error{Code: -32000, Message: "Server error", Data: json.RawMessage(`{"layers": []}`)}, errors.KindUnknown},
+	}
+
+	for _, tt := range tests {
+		err := FromError(&tt.obj)
+
+		assert.Equal(t, tt.obj.Message, err.Error())
+		assert.Equal(t, tt.kind, errors.GetKind(err))
+	}
+}
+
+func TestCodeKind(t *testing.T) {
+	assert.Equal(t, CodeInvalidParams, Code(errors.KindInvalidArgument))
+	assert.Equal(t, CodeMethodNotFound, Code(errors.KindUnimplemented))
+	assert.Equal(t, CodeInternalError, Code(errors.KindNotFound))
+
+	assert.Equal(t, errors.KindInvalidArgument, Kind(CodeInvalidRequest))
+	assert.Equal(t, errors.KindInternal, Kind(CodeInternalError))
+	assert.Equal(t, errors.KindUnknown, Kind(42))
+}
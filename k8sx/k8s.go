@@ -0,0 +1,210 @@
+// Package k8sx converts error chains to and from the Status errors of the
+// Kubernetes API.
+//
+// Errors returned by client-go are translated into kinds and codes, with the
+// details of the affected object as fields, and error chains can be turned
+// into the Status carried by the responses of admission and conversion
+// webhooks:
+//
+//     return &admissionv1.AdmissionResponse{
+//         UID:     req.UID,
+//         Allowed: false,
+//         Result:  k8sx.ToStatus(err),
+//     }
+//
+// It lives in its own module so that the core package does not depend on the
+// Kubernetes libraries.
+package k8sx
+
+import (
+	stderrors "errors"
+	"net/http"
+
+	"github.com/hexbee-net/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// Fields describing a Status error and the object it affects.
+// The Status of the Kubernetes API does not carry the namespace of the object.
+const (
+	ReasonField     = "k8s.reason"
+	GroupField      = "k8s.group"
+	KindField       = "k8s.kind"
+	NameField       = "k8s.name"
+	RetryAfterField = "k8s.retry_after_seconds"
+)
+
+// Translate annotates err with the kind matching the reason of the Status
+// error found in its chain, the reason as code, and the group, kind and name
+// of the affected object as fields.
+// If err holds no Status error, it is returned as is.
+// If err is nil, Translate returns nil.
+func Translate(err error) error {
+	var apiStatus apierrors.APIStatus
+	if err == nil || !stderrors.As(err, &apiStatus) {
+		return err
+	}
+
+	st := apiStatus.Status()
+	fields := errors.Fields{ReasonField: string(st.Reason)}
+
+	if d := st.Details; d != nil {
+		setField(fields, GroupField, d.Group)
+		setField(fields, KindField, d.Kind)
+		setField(fields, NameField, d.Name)
+
+		if d.RetryAfterSeconds > 0 {
+			fields[RetryAfterField] = int(d.RetryAfterSeconds)
+		}
+	}
+
+	err = errors.WithFields(errors.WithStack(err), fields)
+
+	if st.Reason != metav1.StatusReasonUnknown {
+		err = errors.WithCode(err, string(st.Reason))
+	}
+
+	return errors.WithKind(err, Kind(st.Reason, st.Code))
+}
+
+func setField(fields errors.Fields, key, value string) {
+	if value != "" {
+		fields[key] = value
+	}
+}
+
+// ToStatus returns the failure Status describing err.
+// The reason is derived from the kind of the chain, the code is its HTTP
+// status, the message is its user message or, without one, its complete
+// message, and the object fields set by Translate give the details.
+// If err is nil, a success Status will be returned.
+func ToStatus(err error) *metav1.Status {
+	if err == nil {
+		return &metav1.Status{Status: metav1.StatusSuccess, Code: http.StatusOK}
+	}
+
+	fields := errors.GetFields(err)
+
+	msg := errors.GetUserMessage(err)
+	if msg == "" {
+		msg = err.Error()
+	}
+
+	st := &metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: msg,
+		Reason:  Reason(errors.GetKind(err)),
+		Code:    int32(errors.HTTPStatus(err)),
+	}
+
+	d := &metav1.StatusDetails{}
+	d.Group, _ = fields[GroupField].(string)
+	d.Kind, _ = fields[KindField].(string)
+	d.Name, _ = fields[NameField].(string)
+
+	if n, ok := fields[RetryAfterField].(int); ok {
+		d.RetryAfterSeconds = int32(n)
+	}
+
+	if d.Group != "" || d.Kind != "" || d.Name != "" || d.RetryAfterSeconds != 0 {
+		st.Details = d
+	}
+
+	return st
+}
+
+// ToStatusError returns the Status error describing err, as returned by the
+// Kubernetes API server.
+// If err is nil, ToStatusError returns nil.
+func ToStatusError(err error) *apierrors.StatusError {
+	if err == nil {
+		return nil
+	}
+
+	return &apierrors.StatusError{ErrStatus: *ToStatus(err)}
+}
+
+// Kind returns the kind matching the reason of a Status error.
+// When the reason is unknown, the kind is derived from the HTTP status code.
+func Kind(reason metav1.StatusReason, code int32) errors.Kind {
+	switch reason {
+	case metav1.StatusReasonUnauthorized:
+		return errors.KindUnauthenticated
+	case metav1.StatusReasonForbidden:
+		return errors.KindPermissionDenied
+	case metav1.StatusReasonNotFound:
+		return errors.KindNotFound
+	case metav1.StatusReasonAlreadyExists:
+		return errors.KindAlreadyExists
+	case metav1.StatusReasonConflict:
+		return errors.KindAborted
+	case metav1.StatusReasonGone, metav1.StatusReasonExpired:
+		return errors.KindFailedPrecondition
+	case metav1.StatusReasonInvalid, metav1.StatusReasonBadRequest,
+		metav1.StatusReasonNotAcceptable, metav1.StatusReasonUnsupportedMediaType,
+		metav1.StatusReasonRequestEntityTooLarge:
+		return errors.KindInvalidArgument
+	case metav1.StatusReasonServerTimeout, metav1.StatusReasonTimeout:
+		return errors.KindDeadlineExceeded
+	case metav1.StatusReasonTooManyRequests:
+		return errors.KindResourceExhausted
+	case metav1.StatusReasonMethodNotAllowed:
+		return errors.KindUnimplemented
+	case metav1.StatusReasonInternalError:
+		return errors.KindInternal
+	case metav1.StatusReasonServiceUnavailable:
+		return errors.KindUnavailable
+	}
+
+	switch code {
+	case http.StatusNotFound:
+		return errors.KindNotFound
+	case http.StatusConflict:
+		return errors.KindAborted
+	case http.StatusForbidden:
+		return errors.KindPermissionDenied
+	case http.StatusUnauthorized:
+		return errors.KindUnauthenticated
+	case http.StatusTooManyRequests:
+		return errors.KindResourceExhausted
+	case http.StatusServiceUnavailable:
+		return errors.KindUnavailable
+	}
+
+	return errors.KindUnknown
+}
+
+// Reason returns the reason of the Status errors matching k.
+func Reason(k errors.Kind) metav1.StatusReason {
+	switch k {
+	case errors.KindUnauthenticated:
+		return metav1.StatusReasonUnauthorized
+	case errors.KindPermissionDenied:
+		return metav1.StatusReasonForbidden
+	case errors.KindNotFound:
+		return metav1.StatusReasonNotFound
+	case errors.KindAlreadyExists:
+		return metav1.StatusReasonAlreadyExists
+	case errors.KindAborted:
+		return metav1.StatusReasonConflict
+	case errors.KindFailedPrecondition:
+		return metav1.StatusReasonExpired
+	case errors.KindInvalidArgument, errors.KindOutOfRange:
+		return metav1.StatusReasonInvalid
+	case errors.KindDeadlineExceeded:
+		return metav1.StatusReasonTimeout
+	case errors.KindResourceExhausted:
+		return metav1.StatusReasonTooManyRequests
+	case errors.KindUnimplemented:
+		return metav1.StatusReasonMethodNotAllowed
+	case errors.KindInternal, errors.KindDataLoss:
+		return metav1.StatusReasonInternalError
+	case errors.KindUnavailable:
+		return metav1.StatusReasonServiceUnavailable
+	case errors.KindUnknown, errors.KindCanceled:
+		return metav1.StatusReasonUnknown
+	}
+
+	return metav1.StatusReasonUnknown
+}
@@ -0,0 +1,104 @@
+package k8sx
+
+import (
+	stderrors "errors"
+	"net/http"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/stretchr/testify/assert"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func notFound() *apierrors.StatusError {
+	return &apierrors.StatusError{ErrStatus: metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: `deployments.apps "web" not found`,
+		Reason:  metav1.StatusReasonNotFound,
+		Details: &metav1.StatusDetails{Group: "apps", Kind: "deployments", Name: "web"},
+		Code:    http.StatusNotFound,
+	}}
+}
+
+func TestTranslate(t *testing.T) {
+	assert.Nil(t, Translate(nil))
+
+	plain := errors.New("boom")
+	assert.Equal(t, plain, Translate(plain))
+
+	cause := notFound()
+	err := Translate(errors.Wrap(cause, "getting deployment"))
+
+	assert.Equal(t, `getting deployment: deployments.apps "web" not found`, err.Error())
+	assert.Equal(t, errors.KindNotFound, errors.GetKind(err))
+	assert.Equal(t, "NotFound", errors.GetCode(err))
+	assert.Equal(t, errors.Fields{
+		ReasonField: "NotFound",
+		GroupField:  "apps",
+		KindField:   "deployments",
+		NameField:   "web",
+	}, errors.GetFields(err))
+	assert.True(t, stderrors.Is(err, cause))
+
+	err = Translate(&apierrors.StatusError{ErrStatus: metav1.Status{
+		Message: "slow down",
+		Reason:  metav1.StatusReasonTooManyRequests,
+		Details: &metav1.StatusDetails{RetryAfterSeconds: 5},
+		Code:    http.StatusTooManyRequests,
+	}})
+	assert.Equal(t, errors.KindResourceExhausted, errors.GetKind(err))
+	assert.Equal(t, 5, errors.GetFields(err)[RetryAfterField])
+
+	err = Translate(&apierrors.StatusError{ErrStatus: metav1.Status{Message: "conflict", Code: http.StatusConflict}})
+	assert.Equal(t, errors.KindAborted, errors.GetKind(err))
+	assert.Equal(t, "", errors.GetCode(err))
+}
+
+func TestToStatus(t *testing.T) {
+	assert.Equal(t, &metav1.Status{Status: metav1.StatusSuccess, Code: http.StatusOK}, ToStatus(nil))
+	assert.Nil(t, ToStatusError(nil))
+
+	err := errors.WithUserMessage(
+		errors.WithKind(errors.New("replicas must be positive"), errors.KindInvalidArgument),
+		"spec.replicas: must be greater than or equal to 0",
+	)
+
+	assert.Equal(t, &metav1.Status{
+		Status:  metav1.StatusFailure,
+		Message: "spec.replicas: must be greater than or equal to 0",
+		Reason:  metav1.StatusReasonInvalid,
+		Code:    http.StatusBadRequest,
+	}, ToStatus(err))
+
+	assert.Equal(t, notFound().ErrStatus.Details, ToStatus(Translate(notFound())).Details)
+
+	statusErr := ToStatusError(errors.WithKind(errors.New("denied by policy"), errors.KindPermissionDenied))
+	assert.Equal(t, metav1.StatusReasonForbidden, statusErr.ErrStatus.Reason)
+	assert.Equal(t, int32(http.StatusForbidden), statusErr.ErrStatus.Code)
+}
+
+func TestReasonRoundTrip(t *testing.T) {
+	kinds := []errors.Kind{
+		errors.KindUnknown,
+		errors.KindInvalidArgument,
+		errors.KindDeadlineExceeded,
+		errors.KindNotFound,
+		errors.KindAlreadyExists,
+		errors.KindPermissionDenied,
+		errors.KindResourceExhausted,
+		errors.KindFailedPrecondition,
+		errors.KindAborted,
+		errors.KindUnimplemented,
+		errors.KindInternal,
+		errors.KindUnavailable,
+		errors.KindUnauthenticated,
+	}
+
+	for _, k := range kinds {
+		assert.Equal(t, k, Kind(Reason(k), 0), k)
+	}
+
+	assert.Equal(t, errors.KindUnavailable, Kind(metav1.StatusReasonUnknown, http.StatusServiceUnavailable))
+	assert.Equal(t, errors.KindUnknown, Kind(metav1.StatusReasonUnknown, http.StatusTeapot))
+}
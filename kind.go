@@ -0,0 +1,209 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// Kind classifies an error by the nature of the failure.
+// The predefined kinds mirror the canonical gRPC status codes so that they can
+// be translated by transport integrations.
+type Kind string
+
+// Predefined error kinds.
+const (
+	KindUnknown            Kind = "unknown"
+	KindCanceled           Kind = "canceled"
+	KindInvalidArgument    Kind = "invalid_argument"
+	KindDeadlineExceeded   Kind = "deadline_exceeded"
+	KindNotFound           Kind = "not_found"
+	KindAlreadyExists      Kind = "already_exists"
+	KindPermissionDenied   Kind = "permission_denied"
+	KindResourceExhausted  Kind = "resource_exhausted"
+	KindFailedPrecondition Kind = "failed_precondition"
+	KindAborted            Kind = "aborted"
+	KindOutOfRange         Kind = "out_of_range"
+	KindUnimplemented      Kind = "unimplemented"
+	KindInternal           Kind = "internal"
+	KindUnavailable        Kind = "unavailable"
+	KindDataLoss           Kind = "data_loss"
+	KindUnauthenticated    Kind = "unauthenticated"
+)
+
+func (k Kind) String() string { return string(k) }
+
+// GetKind returns the outermost kind annotating the error stack.
+// Layers reporting an empty kind are skipped.
+// If no kind is found, KindUnknown will be returned.
+func GetKind(err error) Kind {
+	type kinder interface {
+		Kind() Kind
+	}
+
+	for depth := 0; err != nil && depth < MaxChainDepth(); depth++ {
+		if k, ok := err.(kinder); ok && k.Kind() != "" {
+			return k.Kind()
+		}
+
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+
+		err = cause.Cause()
+	}
+
+	return KindUnknown
+}
+
+// GetCode returns the outermost code annotating the error stack.
+// Layers reporting an empty code are skipped.
+// If no code is found, an empty string will be returned.
+func GetCode(err error) string {
+	type coder interface {
+		Code() string
+	}
+
+	for depth := 0; err != nil && depth < MaxChainDepth(); depth++ {
+		if c, ok := err.(coder); ok && c.Code() != "" {
+			return c.Code()
+		}
+
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+
+		err = cause.Cause()
+	}
+
+	return ""
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+
+type withKind struct {
+	cause error
+	kind  Kind
+}
+
+// WithKind annotates err with the specified kind.
+// If err is nil, WithKind returns nil.
+func WithKind(err error, kind Kind) error {
+	if err == nil {
+		return nil
+	}
+
+	return runHooks(&withKind{
+		cause: err,
+		kind:  kind,
+	}, err)
+}
+
+func (w *withKind) Error() string {
+	return w.cause.Error()
+}
+
+func (w *withKind) Cause() error {
+	return w.cause
+}
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withKind) Unwrap() error {
+	return w.cause
+}
+
+func (w *withKind) Kind() Kind {
+	return w.kind
+}
+
+func (w *withKind) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			formatGoString(s, w)
+
+			return
+		}
+
+		if s.Flag('+') {
+			if customFormat(s, w) {
+				return
+			}
+
+			formatCause(s, w.Cause())
+			_, _ = io.WriteString(s, "\n")
+			formatAnnotation(s, "kind", w.kind.String())
+
+			return
+		}
+
+		fallthrough
+	case 's', 'q':
+		_, _ = io.WriteString(s, w.Error())
+	}
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+
+type withCode struct {
+	cause error
+	code  string
+}
+
+// WithCode annotates err with an application defined code.
+// If err is nil, WithCode returns nil.
+func WithCode(err error, code string) error {
+	if err == nil {
+		return nil
+	}
+
+	return runHooks(&withCode{
+		cause: err,
+		code:  code,
+	}, err)
+}
+
+func (w *withCode) Error() string {
+	return w.cause.Error()
+}
+
+func (w *withCode) Cause() error {
+	return w.cause
+}
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withCode) Unwrap() error {
+	return w.cause
+}
+
+func (w *withCode) Code() string {
+	return w.code
+}
+
+func (w *withCode) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			formatGoString(s, w)
+
+			return
+		}
+
+		if s.Flag('+') {
+			if customFormat(s, w) {
+				return
+			}
+
+			formatCause(s, w.Cause())
+			_, _ = io.WriteString(s, "\n")
+			formatAnnotation(s, "code", w.code)
+
+			return
+		}
+
+		fallthrough
+	case 's', 'q':
+		_, _ = io.WriteString(s, w.Error())
+	}
+}
@@ -0,0 +1,62 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithKindNil(t *testing.T) {
+	assert.Nil(t, WithKind(nil, KindNotFound))
+}
+
+func TestGetKind(t *testing.T) {
+	tests := []struct {
+		err  error
+		want Kind
+	}{
+		{nil, KindUnknown},
+		{io.EOF, KindUnknown},
+		{WithKind(io.EOF, KindNotFound), KindNotFound},
+		{Wrap(WithKind(io.EOF, KindNotFound), "read error"), KindNotFound},
+		{WithKind(WithKind(io.EOF, KindNotFound), KindInternal), KindInternal},
+		{WithField(WithKind(io.EOF, KindUnavailable), "key", "value"), KindUnavailable},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, GetKind(tt.err))
+	}
+}
+
+func TestWithCodeNil(t *testing.T) {
+	assert.Nil(t, WithCode(nil, "code"))
+}
+
+func TestGetCode(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{io.EOF, ""},
+		{WithCode(io.EOF, "user.not_found"), "user.not_found"},
+		{Wrap(WithCode(io.EOF, "user.not_found"), "read error"), "user.not_found"},
+		{WithCode(WithCode(io.EOF, "inner"), "outer"), "outer"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, GetCode(tt.err))
+	}
+}
+
+func TestKindAndCodeFormat(t *testing.T) {
+	err := WithCode(WithKind(io.EOF, KindNotFound), "user.not_found")
+
+	assert.Equal(t, "EOF", err.Error())
+	assert.Equal(t, "EOF", fmt.Sprintf("%s", err))
+	assert.Equal(t, "EOF", fmt.Sprintf("%v", err))
+	assert.Equal(t, "EOF\n  kind: not_found\n\n  code: user.not_found\n", fmt.Sprintf("%+v", err))
+	assert.Equal(t, []error{err}, Unpack(err))
+}
@@ -0,0 +1,50 @@
+package errors
+
+// Lazy is a field value computed only when the fields of an error are read,
+// that is when the error is formatted, logged or serialized, so that
+// expensive diagnostics cost nothing as long as the error is handled silently:
+//
+//     err = errors.WithField(err, "config", errors.Lazy(func() interface{} {
+//            return cfg.Dump()
+//     }))
+//
+// Field values of type func() interface{} are evaluated the same way.
+// The function is called every time the fields are read.
+type Lazy func() interface{}
+
+// resolveField returns the value of the field v, evaluating it if it is lazy.
+func resolveField(v interface{}) interface{} {
+	switch f := v.(type) {
+	case Lazy:
+		return f()
+	case func() interface{}:
+		return f()
+	default:
+		return v
+	}
+}
+
+// resolveFields returns fields with their lazy values evaluated.
+// fields is returned as is when it holds no lazy value.
+func resolveFields(fields Fields) Fields {
+	lazy := false
+
+	for _, v := range fields {
+		switch v.(type) {
+		case Lazy, func() interface{}:
+			lazy = true
+		}
+	}
+
+	if !lazy {
+		return fields
+	}
+
+	out := make(Fields, len(fields))
+
+	for k, v := range fields {
+		out[k] = resolveField(v)
+	}
+
+	return out
+}
@@ -0,0 +1,38 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLazyFields(t *testing.T) {
+	calls := 0
+
+	err := WithFields(io.EOF, Fields{
+		"size": Lazy(func() interface{} {
+			calls++
+			return 42
+		}),
+		"dump": func() interface{} { return "config" },
+		"name": "static",
+	})
+	err = Wrap(err, "loading")
+
+	assert.Equal(t, 0, calls)
+
+	want := Fields{"size": 42, "dump": "config", "name": "static"}
+	assert.Equal(t, want, GetFields(err))
+	assert.Equal(t, 1, calls)
+
+	assert.Contains(t, fmt.Sprintf("%+v", err), "  size: 42\n")
+
+	b, jerr := json.Marshal(err)
+	assert.NoError(t, jerr)
+	assert.Contains(t, string(b), `"size":42`)
+
+	assert.Equal(t, 42, Fields{"size": Lazy(func() interface{} { return 42 })}.Fields()["size"])
+}
@@ -0,0 +1,53 @@
+package errors
+
+import (
+	"fmt"
+	"sync"
+)
+
+// lazyFormat is a message formatted on first use.
+type lazyFormat struct {
+	once   sync.Once
+	format string
+	args   []interface{}
+	msg    string
+}
+
+func (l *lazyFormat) String() string {
+	l.once.Do(func() {
+		l.msg = fmt.Sprintf(l.format, l.args...)
+		l.args = nil
+	})
+
+	return l.msg
+}
+
+// LazyErrorf is like Errorf, except that the message is only formatted when
+// first needed, such as by Error or Format, and then kept: most errors are
+// handled without ever being printed.
+// The arguments are kept until then, so they must not be modified after the
+// call.
+func LazyErrorf(format string, args ...interface{}) error {
+	err := runHooks(&fundamental{
+		lazy:  &lazyFormat{format: format, args: args},
+		stack: callers(),
+	}, nil)
+
+	publishCreated(err, nil)
+
+	return err
+}
+
+// LazyWrapf is like Wrapf, except that the message is only formatted when
+// first needed, like with LazyErrorf.
+// If err is nil, LazyWrapf returns nil.
+func LazyWrapf(err error, format string, args ...interface{}) error {
+	if err == nil {
+		return nil
+	}
+
+	return withStackOn(&withMessage{
+		cause: err,
+		lazy:  &lazyFormat{format: format, args: args},
+	}, err, callers())
+}
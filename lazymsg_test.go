@@ -0,0 +1,49 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type countingStringer struct {
+	calls *int
+}
+
+func (c countingStringer) String() string {
+	*c.calls++
+
+	return "value"
+}
+
+func TestLazyErrorf(t *testing.T) {
+	calls := 0
+	err := LazyErrorf("bad %s", countingStringer{&calls})
+
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, "bad value", err.Error())
+	assert.Equal(t, "bad value", err.Error())
+	assert.Equal(t, 1, calls)
+
+	if stacksCompiled {
+		assert.Contains(t, fmt.Sprintf("%+v", err), "bad value\ngithub.com/hexbee-net/errors.TestLazyErrorf\n")
+	}
+
+	assert.Equal(t, []LayerFields{{Message: "bad value", Fields: Fields{}}}, GetFieldsByLayer(err))
+}
+
+func TestLazyWrapf(t *testing.T) {
+	assert.Nil(t, LazyWrapf(nil, "reading %d", 1))
+
+	calls := 0
+	err := LazyWrapf(io.EOF, "reading %s", countingStringer{&calls})
+
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, "reading value: EOF", err.Error())
+	assert.Equal(t, "reading value: EOF", fmt.Sprint(err))
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, io.EOF, Cause(err))
+	assert.Equal(t, []error{io.EOF, err}, Unpack(err))
+}
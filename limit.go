@@ -0,0 +1,151 @@
+package errors
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"sync/atomic"
+	"unicode/utf8"
+)
+
+// TruncationMarker ends the field values truncated by FieldLimits.
+const TruncationMarker = "…"
+
+// FieldLimits cap the size of field values when they are rendered, so that a
+// byte slice or a huge structure accidentally attached to an error does not
+// flood the logs and error reports.
+// A zero limit means no limit.
+type FieldLimits struct {
+	// MaxLength is the maximum number of bytes of a value, strings, byte
+	// slices and other values being cut to this length once formatted.
+	MaxLength int
+	// MaxElements is the maximum number of elements of a slice, an array or a
+	// map, the elements beyond being replaced by a truncation marker.
+	MaxElements int
+}
+
+//nolint:gochecknoglobals // the limits are configured process-wide.
+var fieldLimits atomic.Value // FieldLimits
+
+// SetFieldLimits sets the limits applied when formatting errors and encoding
+// them in JSON, YAML or log fields.
+// No limit is applied by default.
+func SetFieldLimits(l FieldLimits) {
+	fieldLimits.Store(l)
+}
+
+// GetFieldLimits returns the limits set by SetFieldLimits.
+func GetFieldLimits() FieldLimits {
+	l, _ := fieldLimits.Load().(FieldLimits)
+
+	return l
+}
+
+// LimitFields returns a copy of fields with their values capped by l, for
+// adapters applying their own limits.
+func (l FieldLimits) LimitFields(fields Fields) Fields {
+	out := make(Fields, len(fields))
+
+	for k, v := range fields {
+		out[k] = l.Limit(k, v)
+	}
+
+	return out
+}
+
+// Limit returns the value of the field key capped by l.
+// Truncated collections become []interface{} or map[string]interface{}
+// values, and other values too long once formatted become strings.
+func (l FieldLimits) Limit(key string, value interface{}) interface{} {
+	if l == (FieldLimits{}) {
+		return value
+	}
+
+	switch v := value.(type) {
+	case string:
+		return l.limitString(v)
+	case []byte:
+		if l.MaxLength > 0 && len(v) > l.MaxLength {
+			return append(v[:l.MaxLength:l.MaxLength], TruncationMarker...)
+		}
+
+		return v
+	}
+
+	value = l.limitElements(value)
+
+	if l.MaxLength > 0 && !isGobBasic(value) {
+		if s := FormatField(key, value); len(s) > l.MaxLength {
+			return l.limitString(s)
+		}
+	}
+
+	return value
+}
+
+func (l FieldLimits) limitString(s string) string {
+	if l.MaxLength <= 0 || len(s) <= l.MaxLength {
+		return s
+	}
+
+	s = s[:l.MaxLength]
+
+	// Do not end in the middle of a character.
+	for i := 0; i < utf8.UTFMax && s != ""; i++ {
+		if r, size := utf8.DecodeLastRuneInString(s); r != utf8.RuneError || size != 1 {
+			break
+		}
+
+		s = s[:len(s)-1]
+	}
+
+	return s + TruncationMarker
+}
+
+func (l FieldLimits) limitElements(value interface{}) interface{} {
+	if l.MaxElements <= 0 {
+		return value
+	}
+
+	rv := reflect.ValueOf(value)
+
+	switch rv.Kind() { //nolint:exhaustive // only collections have elements
+	case reflect.Slice, reflect.Array:
+		if rv.Len() <= l.MaxElements {
+			return value
+		}
+
+		out := make([]interface{}, 0, l.MaxElements+1)
+		for i := 0; i < l.MaxElements; i++ {
+			out = append(out, rv.Index(i).Interface())
+		}
+
+		return append(out, fmt.Sprintf("%s %d more", TruncationMarker, rv.Len()-l.MaxElements))
+	case reflect.Map:
+		if rv.Len() <= l.MaxElements {
+			return value
+		}
+
+		keys := make([]string, 0, rv.Len())
+		values := make(map[string]interface{}, rv.Len())
+
+		for it := rv.MapRange(); it.Next(); {
+			k := fmt.Sprint(it.Key().Interface())
+			keys = append(keys, k)
+			values[k] = it.Value().Interface()
+		}
+
+		sort.Strings(keys)
+
+		out := make(map[string]interface{}, l.MaxElements+1)
+		for _, k := range keys[:l.MaxElements] {
+			out[k] = values[k]
+		}
+
+		out[TruncationMarker] = fmt.Sprintf("%d more", len(keys)-l.MaxElements)
+
+		return out
+	default:
+		return value
+	}
+}
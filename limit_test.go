@@ -0,0 +1,83 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withFieldLimits(t *testing.T, l FieldLimits) {
+	t.Helper()
+
+	SetFieldLimits(l)
+	t.Cleanup(func() { SetFieldLimits(FieldLimits{}) })
+}
+
+func TestFieldLimitsLimit(t *testing.T) {
+	l := FieldLimits{MaxLength: 5}
+
+	tests := []struct {
+		value interface{}
+		want  interface{}
+	}{
+		{"short", "short"},
+		{"too long", "too l…"},
+		{"ééé", "éé…"},
+		{[]byte("too long"), []byte("too l…")},
+		{123456789, 123456789},
+		{struct{ A string }{"long value"}, "{long…"},
+	}
+
+	for i, tt := range tests {
+		assert.Equal(t, tt.want, l.Limit("key", tt.value), "test %d", i+1)
+	}
+
+	l = FieldLimits{MaxElements: 2}
+
+	tests = []struct {
+		value interface{}
+		want  interface{}
+	}{
+		{[]int{1, 2, 3}, []interface{}{1, 2, "… 1 more"}},
+		{[2]int{1, 2}, [2]int{1, 2}},
+		{map[string]int{"c": 3, "a": 1, "b": 2}, map[string]interface{}{"a": 1, "b": 2, "…": "1 more"}},
+		{"long strings are kept", "long strings are kept"},
+	}
+
+	for i, tt := range tests {
+		assert.Equal(t, tt.want, l.Limit("key", tt.value), "test %d", i+1)
+	}
+
+	l = FieldLimits{MaxLength: 5, MaxElements: 2}
+
+	long := strings.Repeat("x", 10)
+	assert.Equal(t, long, FieldLimits{}.Limit("key", long))
+	assert.Equal(t, Fields{"a": "xxxxx…", "b": 1}, l.LimitFields(Fields{"a": long, "b": 1}))
+}
+
+func TestFieldLimitsRendering(t *testing.T) {
+	withFieldLimits(t, FieldLimits{MaxLength: 5})
+
+	err := WithField(io.EOF, "body", "a very long body")
+
+	assert.Equal(t, "EOF\n  body: a ver…\n", fmt.Sprintf("%+v", err))
+
+	b, jerr := json.Marshal(err)
+	assert.NoError(t, jerr)
+
+	var doc Document
+
+	assert.NoError(t, json.Unmarshal(b, &doc))
+	assert.Equal(t, "a ver…", doc.Layers[0].Fields["body"])
+
+	b, jerr = ToYAML(err)
+	assert.NoError(t, jerr)
+	assert.Contains(t, string(b), "body: a ver…\n")
+
+	assert.Equal(t, "a ver…", GetFields(err).Fields()["body"])
+	assert.Equal(t, "a very long body", GetFields(err)["body"])
+}
@@ -0,0 +1,359 @@
+// Package msgpackx encodes the structured representation of error chains
+// with MessagePack.
+//
+// The document has the same shape as the JSON one produced by errors.ToJSON,
+// with maps keyed by strings, so it can be decoded by any MessagePack library.
+package msgpackx
+
+import (
+	"encoding/binary"
+	"math"
+
+	"github.com/hexbee-net/errors"
+	"github.com/hexbee-net/errors/internal/docmap"
+)
+
+// ErrInvalid is returned when decoding malformed MessagePack data.
+const ErrInvalid = errors.Error("msgpackx: invalid data")
+
+// Format bytes of the MessagePack specification.
+const (
+	fixIntMax   = 0x7f
+	fixMap      = 0x80
+	fixArray    = 0x90
+	fixStr      = 0xa0
+	fixMapMax   = 0x8f
+	fixArrayMax = 0x9f
+	fixStrMax   = 0xbf
+	fixLenMax   = 0x0f
+	fixStrLen   = 0x1f
+	negFixInt   = 0xe0
+	negFixMin   = -32
+
+	formatNil     = 0xc0
+	formatFalse   = 0xc2
+	formatTrue    = 0xc3
+	formatBin8    = 0xc4
+	formatBin16   = 0xc5
+	formatBin32   = 0xc6
+	formatFloat32 = 0xca
+	formatFloat64 = 0xcb
+	formatUint8   = 0xcc
+	formatUint16  = 0xcd
+	formatUint32  = 0xce
+	formatUint64  = 0xcf
+	formatInt8    = 0xd0
+	formatInt16   = 0xd1
+	formatInt32   = 0xd2
+	formatInt64   = 0xd3
+	formatStr8    = 0xd9
+	formatStr16   = 0xda
+	formatStr32   = 0xdb
+	formatArray16 = 0xdc
+	formatArray32 = 0xdd
+	formatMap16   = 0xde
+	formatMap32   = 0xdf
+)
+
+// Encode returns the MessagePack encoding of the structured representation of err.
+func Encode(err error) ([]byte, error) {
+	return appendValue(nil, docmap.Encode(errors.NewDocument(err))), nil
+}
+
+// Decode rebuilds the error chain encoded by Encode as errors.RemoteError values.
+// If data is malformed, the decoding error is returned.
+// If the document has no layers, Decode returns nil.
+func Decode(data []byte) error {
+	d := &decoder{data: data}
+
+	tree, err := d.value()
+	if err != nil {
+		return errors.WithMessage(err, "decoding remote error")
+	}
+
+	doc, err := docmap.Decode(tree)
+	if err != nil {
+		return errors.WithMessage(err, "decoding remote error")
+	}
+
+	return errors.FromDocument(doc)
+}
+
+func appendValue(b []byte, v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return append(b, formatNil)
+	case bool:
+		if v {
+			return append(b, formatTrue)
+		}
+
+		return append(b, formatFalse)
+	case int64:
+		return appendInt(b, v)
+	case uint64:
+		return appendUint(b, v)
+	case float64:
+		return appendUint64(append(b, formatFloat64), math.Float64bits(v))
+	case string:
+		b = appendLength(b, len(v), fixStr, fixStrLen, formatStr8, formatStr16, formatStr32)
+		return append(b, v...)
+	case []byte:
+		b = appendLength(b, len(v), 0, -1, formatBin8, formatBin16, formatBin32)
+		return append(b, v...)
+	case []interface{}:
+		b = appendLength(b, len(v), fixArray, fixLenMax, 0, formatArray16, formatArray32)
+		for _, e := range v {
+			b = appendValue(b, e)
+		}
+
+		return b
+	case map[string]interface{}:
+		b = appendLength(b, len(v), fixMap, fixLenMax, 0, formatMap16, formatMap32)
+		for _, k := range docmap.SortedKeys(v) {
+			b = appendValue(b, k)
+			b = appendValue(b, v[k])
+		}
+
+		return b
+	default:
+		return appendValue(b, docmap.Normalize(v))
+	}
+}
+
+// appendLength appends the header of a string, binary, array or map.
+// Formats that do not exist for the type are given as 0, or -1 for fixMax.
+func appendLength(b []byte, n int, fix byte, fixMax int, f8, f16, f32 byte) []byte {
+	switch {
+	case n <= fixMax:
+		return append(b, fix|byte(n))
+	case f8 != 0 && n <= math.MaxUint8:
+		return append(b, f8, byte(n))
+	case n <= math.MaxUint16:
+		return appendUint16(append(b, f16), uint16(n))
+	default:
+		return appendUint32(append(b, f32), uint32(n))
+	}
+}
+
+func appendInt(b []byte, v int64) []byte {
+	switch {
+	case v >= 0:
+		return appendUint(b, uint64(v))
+	case v >= negFixMin:
+		return append(b, byte(v))
+	case v >= math.MinInt8:
+		return append(b, formatInt8, byte(v))
+	case v >= math.MinInt16:
+		return appendUint16(append(b, formatInt16), uint16(v))
+	case v >= math.MinInt32:
+		return appendUint32(append(b, formatInt32), uint32(v))
+	default:
+		return appendUint64(append(b, formatInt64), uint64(v))
+	}
+}
+
+func appendUint(b []byte, v uint64) []byte {
+	switch {
+	case v <= fixIntMax:
+		return append(b, byte(v))
+	case v <= math.MaxUint8:
+		return append(b, formatUint8, byte(v))
+	case v <= math.MaxUint16:
+		return appendUint16(append(b, formatUint16), uint16(v))
+	case v <= math.MaxUint32:
+		return appendUint32(append(b, formatUint32), uint32(v))
+	default:
+		return appendUint64(append(b, formatUint64), v)
+	}
+}
+
+func appendUint16(b []byte, v uint16) []byte {
+	var buf [2]byte
+
+	binary.BigEndian.PutUint16(buf[:], v)
+
+	return append(b, buf[:]...)
+}
+
+func appendUint32(b []byte, v uint32) []byte {
+	var buf [4]byte
+
+	binary.BigEndian.PutUint32(buf[:], v)
+
+	return append(b, buf[:]...)
+}
+
+func appendUint64(b []byte, v uint64) []byte {
+	var buf [8]byte
+
+	binary.BigEndian.PutUint64(buf[:], v)
+
+	return append(b, buf[:]...)
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+
+type decoder struct {
+	data []byte
+}
+
+func (d *decoder) next(n int) ([]byte, error) {
+	if n < 0 || n > len(d.data) {
+		return nil, errors.Wrap(ErrInvalid, "unexpected end of data")
+	}
+
+	b := d.data[:n]
+	d.data = d.data[n:]
+
+	return b, nil
+}
+
+func (d *decoder) uint(size int) (uint64, error) {
+	b, err := d.next(size)
+	if err != nil {
+		return 0, err
+	}
+
+	var v uint64
+	for _, c := range b {
+		v = v<<8 | uint64(c)
+	}
+
+	return v, nil
+}
+
+//nolint:gocyclo,funlen // one case per format.
+func (d *decoder) value() (interface{}, error) {
+	b, err := d.next(1)
+	if err != nil {
+		return nil, err
+	}
+
+	c := b[0]
+
+	switch {
+	case c <= fixIntMax:
+		return int64(c), nil
+	case c >= negFixInt:
+		return int64(int8(c)), nil
+	case c <= fixMapMax:
+		return d.mapOf(int(c & fixLenMax))
+	case c <= fixArrayMax:
+		return d.arrayOf(int(c & fixLenMax))
+	case c <= fixStrMax:
+		return d.str(int(c & fixStrLen))
+	}
+
+	switch c {
+	case formatNil:
+		return nil, nil
+	case formatFalse:
+		return false, nil
+	case formatTrue:
+		return true, nil
+	case formatBin8, formatBin16, formatBin32:
+		n, err := d.uint(1 << (c - formatBin8))
+		if err != nil {
+			return nil, err
+		}
+
+		bin, err := d.next(int(n))
+
+		return append([]byte(nil), bin...), err
+	case formatFloat32:
+		v, err := d.uint(4)
+		return float64(math.Float32frombits(uint32(v))), err
+	case formatFloat64:
+		v, err := d.uint(8)
+		return math.Float64frombits(v), err
+	case formatUint8, formatUint16, formatUint32, formatUint64:
+		v, err := d.uint(1 << (c - formatUint8))
+		if err == nil && v <= math.MaxInt64 {
+			return int64(v), nil
+		}
+
+		return v, err
+	case formatInt8, formatInt16, formatInt32, formatInt64:
+		size := 1 << (c - formatInt8)
+
+		v, err := d.uint(size)
+		shift := 64 - 8*size
+
+		return int64(v<<shift) >> shift, err
+	case formatStr8, formatStr16, formatStr32:
+		n, err := d.uint(1 << (c - formatStr8))
+		if err != nil {
+			return nil, err
+		}
+
+		return d.str(int(n))
+	case formatArray16, formatArray32:
+		n, err := d.uint(2 << (c - formatArray16))
+		if err != nil {
+			return nil, err
+		}
+
+		return d.arrayOf(int(n))
+	case formatMap16, formatMap32:
+		n, err := d.uint(2 << (c - formatMap16))
+		if err != nil {
+			return nil, err
+		}
+
+		return d.mapOf(int(n))
+	default:
+		return nil, errors.Wrapf(ErrInvalid, "unsupported format 0x%02x", c)
+	}
+}
+
+func (d *decoder) str(n int) (string, error) {
+	b, err := d.next(n)
+
+	return string(b), err
+}
+
+func (d *decoder) arrayOf(n int) ([]interface{}, error) {
+	if n > len(d.data) {
+		return nil, errors.Wrap(ErrInvalid, "unexpected end of data")
+	}
+
+	list := make([]interface{}, n)
+
+	for i := range list {
+		v, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+
+		list[i] = v
+	}
+
+	return list, nil
+}
+
+func (d *decoder) mapOf(n int) (map[string]interface{}, error) {
+	if n > len(d.data) {
+		return nil, errors.Wrap(ErrInvalid, "unexpected end of data")
+	}
+
+	m := make(map[string]interface{}, n)
+
+	for i := 0; i < n; i++ {
+		k, err := d.value()
+		if err != nil {
+			return nil, err
+		}
+
+		key, ok := k.(string)
+		if !ok {
+			return nil, errors.Wrapf(ErrInvalid, "map key is a %T", k)
+		}
+
+		if m[key], err = d.value(); err != nil {
+			return nil, err
+		}
+	}
+
+	return m, nil
+}
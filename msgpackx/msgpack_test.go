@@ -0,0 +1,112 @@
+package msgpackx
+
+import (
+	"io"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/hexbee-net/errors/internal/nostack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRoundTrip(t *testing.T) {
+	err := errors.WithCode(
+		errors.WithKind(
+			errors.WithFields(errors.Wrap(errors.New("boom"), "read error"), errors.Fields{
+				"file":   "data.txt",
+				"size":   42,
+				"offset": -1 << 40,
+				"ratio":  0.5,
+				"raw":    []byte{1, 2},
+				"tags":   []string{"a", strings.Repeat("b", 300)},
+				"nested": map[string]interface{}{"ok": true, "none": nil},
+			}),
+			errors.KindDataLoss,
+		),
+		"file.truncated",
+	)
+
+	data, e := Encode(err)
+	assert.NoError(t, e)
+
+	got := Decode(data)
+
+	assert.IsType(t, &errors.RemoteError{}, got)
+	assert.Equal(t, "read error: boom", got.Error())
+	assert.Equal(t, errors.KindDataLoss, errors.GetKind(got))
+	assert.Equal(t, "file.truncated", errors.GetCode(got))
+	assert.Equal(t, errors.Fields{
+		"file":   "data.txt",
+		"size":   int64(42),
+		"offset": int64(-1 << 40),
+		"ratio":  0.5,
+		"raw":    []byte{1, 2},
+		"tags":   []interface{}{"a", strings.Repeat("b", 300)},
+		"nested": map[string]interface{}{"ok": true, "none": nil},
+	}, errors.GetFields(got))
+
+	if stack := got.(*errors.RemoteError).Stack(); !nostack.Enabled && assert.NotEmpty(t, stack) {
+		assert.Equal(t, "github.com/hexbee-net/errors/msgpackx.TestRoundTrip", stack[0].Function)
+	}
+
+	again, e := Encode(got)
+	assert.NoError(t, e)
+	assert.Equal(t, data, again)
+}
+
+func TestDecodeNil(t *testing.T) {
+	data, err := Encode(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, Decode(data))
+}
+
+func TestEncoding(t *testing.T) {
+	tests := []struct {
+		v    interface{}
+		want []byte
+	}{
+		{nil, []byte{0xc0}},
+		{true, []byte{0xc3}},
+		{int64(1), []byte{0x01}},
+		{int64(-1), []byte{0xff}},
+		{int64(200), []byte{0xcc, 0xc8}},
+		{int64(-200), []byte{0xd1, 0xff, 0x38}},
+		{uint64(math.MaxUint64), []byte{0xcf, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}},
+		{1.5, []byte{0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0}},
+		{"a", []byte{0xa1, 'a'}},
+		{[]byte{1}, []byte{0xc4, 0x01, 0x01}},
+		{[]interface{}{int64(1)}, []byte{0x91, 0x01}},
+		{map[string]interface{}{"b": int64(2), "a": int64(1)}, []byte{0x82, 0xa1, 'a', 0x01, 0xa1, 'b', 0x02}},
+		{io.EOF, []byte{0xa3, 'E', 'O', 'F'}},
+	}
+
+	for i, tt := range tests {
+		got := appendValue(nil, tt.v)
+		assert.Equal(t, tt.want, got, "test %d", i+1)
+
+		d := &decoder{data: got}
+		v, err := d.value()
+		assert.NoError(t, err, "test %d", i+1)
+		assert.Equal(t, appendValue(nil, v), got, "test %d", i+1)
+	}
+}
+
+func TestDecodeInvalid(t *testing.T) {
+	tests := [][]byte{
+		{},
+		{0xc1},
+		{0xa3, 'E'},
+		{0x81, 0x01, 0x01},
+		{0xdd, 0xff, 0xff, 0xff, 0xff},
+		{0x91},
+		{0xa3, 'E', 'O', 'F'},
+	}
+
+	for i, data := range tests {
+		err := Decode(data)
+		assert.Error(t, err, "test %d", i+1)
+		assert.Contains(t, err.Error(), "decoding remote error", "test %d", i+1)
+	}
+}
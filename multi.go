@@ -0,0 +1,179 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// MultiError is an error accumulating several errors, as built by Append.
+// Its members are matched by errors.Is and errors.As.
+type MultiError struct {
+	stack
+	errors []error
+}
+
+// Append returns an error accumulating err and errs, like the Append function
+// of github.com/hashicorp/go-multierror:
+//
+//     var result error
+//
+//     for _, f := range files {
+//            result = errors.Append(result, f.Close())
+//     }
+//
+//     return result
+//
+// The members of the multi-errors given are accumulated one by one instead of
+// being nested, and nil errors, nil multi-errors included, are skipped.
+// The multi-error given as err is not modified: a new one is returned, keeping
+// the stack trace recorded by the Append call that created the first one.
+// If there is no error to accumulate, Append returns nil.
+func Append(err error, errs ...error) error {
+	var members []error
+
+	m, _ := err.(*MultiError)
+	if m != nil {
+		// Cap the members so that appending never overwrites the ones of
+		// another multi-error sharing the array.
+		members = m.errors[:len(m.errors):len(m.errors)]
+	} else {
+		members = appendFlat(members, err)
+	}
+
+	for _, e := range errs {
+		members = appendFlat(members, e)
+	}
+
+	if len(members) == 0 {
+		return nil
+	}
+
+	if m != nil && !m.stack.isZero() {
+		return &MultiError{errors: members, stack: m.stack}
+	}
+
+	return newMulti(members, callers())
+}
+
+// newMulti returns a MultiError holding members with the stack trace st,
+// passed through the hooks and published as created.
+func newMulti(members []error, st stack) error {
+	m := runHooks(&MultiError{errors: members, stack: st}, nil)
+
+	publishCreated(m, nil)
+
+	return m
+}
+
+// appendFlat appends err to dst, or its members if it is a multi-error.
+func appendFlat(dst []error, err error) []error {
+	switch v := err.(type) {
+	case nil:
+		return dst
+	case *MultiError:
+		if v == nil {
+			return dst
+		}
+
+		return append(dst, v.errors...)
+	default:
+		return append(dst, err)
+	}
+}
+
+// Error returns the messages of the errors as a numbered list.
+func (m *MultiError) Error() string {
+	var b strings.Builder
+
+	b.WriteString(m.title())
+
+	for i, e := range m.errors {
+		_, _ = fmt.Fprintf(&b, "\n\t%d. %s", i+1, strings.ReplaceAll(e.Error(), "\n", "\n\t   "))
+	}
+
+	return b.String()
+}
+
+// title returns the first line of the message of m.
+func (m *MultiError) title() string {
+	if len(m.errors) == 1 {
+		return "1 error occurred:"
+	}
+
+	return fmt.Sprintf("%d errors occurred:", len(m.errors))
+}
+
+// ErrorOrNil returns m, or nil if m is nil or holds no errors, so that a
+// function accumulating errors in a *MultiError variable does not return a
+// non-nil error holding a nil or empty multi-error:
+//
+//     var result *errors.MultiError
+//
+//     ...
+//
+//     return result.ErrorOrNil()
+func (m *MultiError) ErrorOrNil() error {
+	if m == nil || len(m.errors) == 0 {
+		return nil
+	}
+
+	return m
+}
+
+// Errors returns the accumulated errors, which must not be modified.
+func (m *MultiError) Errors() []error {
+	return m.errors
+}
+
+// Unwrap provides compatibility for Go 1.20 multi-error chains, so that
+// errors.Is and errors.As match the accumulated errors.
+func (m *MultiError) Unwrap() []error {
+	return m.errors
+}
+
+// StackTrace returns the stack trace recorded by the Append call that created
+// m, or nil for the zero MultiError.
+func (m *MultiError) StackTrace() errors.StackTrace {
+	if m.stack.isZero() {
+		return nil
+	}
+
+	return m.stack.StackTrace()
+}
+
+func (m *MultiError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			formatGoString(s, m)
+
+			return
+		}
+
+		if s.Flag('+') {
+			if customFormat(s, m) {
+				return
+			}
+
+			st := verbose(s)
+
+			_, _ = io.WriteString(st, m.title())
+			formatBranches(st, m.errors)
+
+			if !m.stack.isZero() {
+				m.stack.Format(s, verb)
+			}
+
+			return
+		}
+
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, m.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", m.Error())
+	}
+}
@@ -0,0 +1,119 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAppend(t *testing.T) {
+	assert.Nil(t, Append(nil))
+	assert.Nil(t, Append(nil, nil, nil))
+
+	first := Append(nil, io.EOF)
+	if assert.IsType(t, &MultiError{}, first) {
+		assert.Equal(t, []error{io.EOF}, first.(*MultiError).Errors())
+	}
+
+	nested := Append(io.ErrClosedPipe, New("second"))
+	err := Append(first, nil, nested, io.ErrUnexpectedEOF)
+
+	m, ok := err.(*MultiError)
+	if assert.True(t, ok) {
+		assert.Len(t, m.Errors(), 4)
+		assert.Equal(t, []error{io.EOF, io.ErrClosedPipe}, m.Errors()[:2])
+		assert.Equal(t, first.(*MultiError).stack, m.stack)
+	}
+
+	assert.Len(t, first.(*MultiError).Errors(), 1, "the appended multi-error is not modified")
+	assert.Equal(t, "4 errors occurred:\n\t1. EOF\n\t2. io: read/write on closed pipe\n\t3. second\n\t4. unexpected EOF", err.Error())
+	assert.Equal(t, "1 error occurred:\n\t1. EOF", first.Error())
+}
+
+func TestAppendSharedMembers(t *testing.T) {
+	base := Append(nil, io.EOF, io.ErrUnexpectedEOF)
+
+	a := Append(base, New("a"))
+	b := Append(base, New("b"))
+
+	assert.Equal(t, "a", a.(*MultiError).Errors()[2].Error())
+	assert.Equal(t, "b", b.(*MultiError).Errors()[2].Error())
+}
+
+func TestMultiErrorIsAs(t *testing.T) {
+	err := Wrap(Append(nil, io.EOF, WithKind(New("denied"), KindPermissionDenied)), "closing")
+
+	assert.True(t, errors.Is(err, io.EOF))
+	assert.False(t, errors.Is(err, io.ErrUnexpectedEOF))
+
+	var k *withKind
+	if assert.True(t, errors.As(err, &k)) {
+		assert.Equal(t, KindPermissionDenied, k.Kind())
+	}
+}
+
+func TestMultiErrorFormat(t *testing.T) {
+	err := Append(nil, io.EOF, New("multi\nline"))
+
+	assert.Equal(t, "2 errors occurred:\n\t1. EOF\n\t2. multi\n\t   line", fmt.Sprintf("%v", err))
+	assert.Equal(t, `"2 errors occurred:\n\t1. EOF\n\t2. multi\n\t   line"`, fmt.Sprintf("%q", err))
+
+	got := fmt.Sprintf("%+v", err)
+	lines := strings.Split(got, "\n")
+
+	assert.Equal(t, "2 errors occurred:", lines[0])
+	assert.Equal(t, "├─ EOF", lines[1])
+	assert.Equal(t, "└─ multi", lines[2])
+	assert.Equal(t, "   line", lines[3])
+
+	if stacksCompiled {
+		assert.Equal(t, "   github.com/hexbee-net/errors.TestMultiErrorFormat", lines[4])
+		assert.Contains(t, got, "\ngithub.com/hexbee-net/errors.TestMultiErrorFormat\n")
+	}
+
+	assert.Regexp(t, `^&errors.MultiError\{errors:2, frames:\d+\}$`, fmt.Sprintf("%#v", err))
+}
+
+func TestMultiErrorZeroFormat(t *testing.T) {
+	m := &MultiError{}
+
+	assert.Equal(t, "0 errors occurred:", fmt.Sprintf("%v", m))
+	assert.Equal(t, "0 errors occurred:", fmt.Sprintf("%+v", m))
+	assert.Equal(t, "&errors.MultiError{errors:0, frames:0}", fmt.Sprintf("%#v", m))
+	assert.Nil(t, m.StackTrace())
+	assert.Nil(t, m.Fields())
+}
+
+func TestMultiErrorErrorOrNil(t *testing.T) {
+	var m *MultiError
+
+	assert.Nil(t, m.ErrorOrNil())
+	assert.Nil(t, (&MultiError{}).ErrorOrNil())
+
+	err := Append(m, io.EOF)
+	m, _ = err.(*MultiError)
+
+	assert.Equal(t, err, m.ErrorOrNil())
+}
+
+func TestAppendEmpty(t *testing.T) {
+	var m *MultiError
+
+	assert.Nil(t, Append(nil))
+	assert.Nil(t, Append(m))
+	assert.Nil(t, Append(&MultiError{}, nil))
+
+	err := Append(m, io.EOF)
+	if assert.IsType(t, &MultiError{}, err) {
+		assert.Equal(t, []error{io.EOF}, err.(*MultiError).Errors())
+	}
+
+	err = Append(&MultiError{}, io.EOF)
+	if assert.NotNil(t, err) {
+		assert.NotNil(t, err.(*MultiError).stack, "an empty multi-error gets a stack trace")
+	}
+}
@@ -0,0 +1,26 @@
+package errors
+
+// Must returns v, or panics with err annotated with a stack trace at the
+// point Must is called if err is not nil, for initialization code that cannot
+// recover from a failure:
+//
+//     var tmpl = errors.Must(template.ParseFiles("index.html"))
+//
+// The panic value can be recovered as an error, such as by Recover.
+func Must[T any](v T, err error) T {
+	if err != nil {
+		panic(withStackOn(err, err, callers()))
+	}
+
+	return v
+}
+
+// Must2 returns v and u like Must does, for the functions returning two
+// values and an error.
+func Must2[T, U any](v T, u U, err error) (T, U) {
+	if err != nil {
+		panic(withStackOn(err, err, callers()))
+	}
+
+	return v, u
+}
@@ -0,0 +1,37 @@
+package errors
+
+import (
+	"io"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMust(t *testing.T) {
+	assert.Equal(t, 42, Must(strconv.Atoi("42")))
+
+	v, u := Must2(1, "a", nil)
+	assert.Equal(t, 1, v)
+	assert.Equal(t, "a", u)
+
+	err := func() (err error) {
+		defer Recover(&err)
+
+		Must(0, io.EOF)
+
+		return nil
+	}()
+
+	assert.Equal(t, io.EOF, Cause(err))
+	assert.Equal(t, "panic: EOF", err.Error())
+
+	v2, _ := PanicValue(err)
+	st := v2.(stackTracer).StackTrace()
+
+	if stacksCompiled && assert.NotEmpty(t, st) {
+		assert.Equal(t, "github.com/hexbee-net/errors.TestMust.func1", Frame(st[0]).Function())
+	}
+
+	assert.Panics(t, func() { Must2(0, 0, io.EOF) })
+}
@@ -0,0 +1,156 @@
+// Package netx classifies network errors by looking for the error values of
+// the net, crypto/tls and syscall packages along the chain, so that callers
+// do not need to match error messages:
+//
+//     if err != nil {
+//         return netx.Annotate(err)
+//     }
+package netx
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	stderrors "errors"
+	"net"
+	"syscall"
+
+	"github.com/hexbee-net/errors"
+)
+
+// Classes of network errors.
+const (
+	ClassCanceled          = "canceled"
+	ClassTimeout           = "timeout"
+	ClassDNS               = "dns"
+	ClassTLS               = "tls"
+	ClassConnectionRefused = "connection_refused"
+	ClassConnectionReset   = "connection_reset"
+	ClassUnreachable       = "unreachable"
+)
+
+// Fields added by Annotate.
+const (
+	ClassField      = "net.class"
+	HostField       = "net.host"
+	ServerNameField = "net.server_name"
+	ErrnoField      = "net.errno"
+)
+
+// Class returns the class of the network error err.
+// If err is not recognized, an empty string will be returned.
+func Class(err error) string {
+	class, _ := classify(err)
+
+	return class
+}
+
+// Classify returns the kind of the network error err.
+// If err is not recognized, KindUnknown will be returned.
+func Classify(err error) errors.Kind {
+	_, kind := classify(err)
+
+	return kind
+}
+
+// Fields returns the fields describing the network error err: its class, the
+// host it was about, the server name the TLS certificate was checked against
+// and the system error number.
+// Only the details found along the chain are returned.
+func Fields(err error) errors.Fields {
+	fields := make(errors.Fields)
+
+	if class := Class(err); class != "" {
+		fields[ClassField] = class
+	}
+
+	var (
+		dnsErr   *net.DNSError
+		opErr    *net.OpError
+		hostname x509.HostnameError
+		errno    syscall.Errno
+	)
+
+	switch {
+	case stderrors.As(err, &dnsErr):
+		fields[HostField] = dnsErr.Name
+	case stderrors.As(err, &opErr) && opErr.Addr != nil:
+		fields[HostField] = opErr.Addr.String()
+	}
+
+	if stderrors.As(err, &hostname) {
+		fields[ServerNameField] = hostname.Host
+	}
+
+	if stderrors.As(err, &errno) {
+		fields[ErrnoField] = int(errno)
+	}
+
+	return fields
+}
+
+// Annotate returns err with a stack trace, the kind given by Classify and the
+// fields given by Fields.
+// If err is nil, Annotate returns nil.
+func Annotate(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	fields := Fields(err)
+	kind := Classify(err)
+
+	err = errors.WithStack(err)
+	if kind != errors.KindUnknown {
+		err = errors.WithKind(err, kind)
+	}
+
+	if len(fields) > 0 {
+		err = errors.WithFields(err, fields)
+	}
+
+	return err
+}
+
+func classify(err error) (string, errors.Kind) {
+	var (
+		netErr      net.Error
+		dnsErr      *net.DNSError
+		unknownAuth x509.UnknownAuthorityError
+		invalidCert x509.CertificateInvalidError
+		hostname    x509.HostnameError
+		header      tls.RecordHeaderError
+	)
+
+	switch {
+	case err == nil:
+		return "", errors.KindUnknown
+	case stderrors.Is(err, context.Canceled):
+		return ClassCanceled, errors.KindCanceled
+	case stderrors.Is(err, context.DeadlineExceeded),
+		stderrors.As(err, &netErr) && netErr.Timeout():
+		return ClassTimeout, errors.KindDeadlineExceeded
+	case stderrors.As(err, &dnsErr):
+		if dnsErr.IsNotFound {
+			return ClassDNS, errors.KindNotFound
+		}
+
+		return ClassDNS, errors.KindUnavailable
+	case stderrors.As(err, &unknownAuth),
+		stderrors.As(err, &invalidCert),
+		stderrors.As(err, &hostname),
+		stderrors.As(err, &header):
+		return ClassTLS, errors.KindFailedPrecondition
+	case stderrors.Is(err, syscall.ECONNREFUSED):
+		return ClassConnectionRefused, errors.KindUnavailable
+	case stderrors.Is(err, syscall.ECONNRESET),
+		stderrors.Is(err, syscall.ECONNABORTED),
+		stderrors.Is(err, syscall.EPIPE):
+		return ClassConnectionReset, errors.KindUnavailable
+	case stderrors.Is(err, syscall.ENETUNREACH),
+		stderrors.Is(err, syscall.EHOSTUNREACH):
+		return ClassUnreachable, errors.KindUnavailable
+	}
+
+	return "", errors.KindUnknown
+}
@@ -0,0 +1,102 @@
+package netx
+
+import (
+	"context"
+	"crypto/x509"
+	stderrors "errors"
+	"io"
+	"net"
+	"os"
+	"syscall"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func opError(errno syscall.Errno) error {
+	return &net.OpError{
+		Op:   "dial",
+		Net:  "tcp",
+		Addr: &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 5432},
+		Err:  os.NewSyscallError("connect", errno),
+	}
+}
+
+func TestClassify(t *testing.T) {
+	tests := []struct {
+		name  string
+		err   error
+		class string
+		kind  errors.Kind
+	}{
+		{"nil", nil, "", errors.KindUnknown},
+		{"unknown", io.EOF, "", errors.KindUnknown},
+		{"canceled", errors.Wrap(context.Canceled, "dial"), ClassCanceled, errors.KindCanceled},
+		{"deadline", context.DeadlineExceeded, ClassTimeout, errors.KindDeadlineExceeded},
+		{"dns timeout", &net.DNSError{Name: "db.internal", IsTimeout: true}, ClassTimeout, errors.KindDeadlineExceeded},
+		{"dns not found", &net.DNSError{Name: "db.internal", IsNotFound: true}, ClassDNS, errors.KindNotFound},
+		{"dns", &net.DNSError{Name: "db.internal"}, ClassDNS, errors.KindUnavailable},
+		{"tls hostname", x509.HostnameError{Certificate: &x509.Certificate{}, Host: "db.internal"}, ClassTLS, errors.KindFailedPrecondition},
+		{"tls authority", errors.Wrap(x509.UnknownAuthorityError{}, "handshake"), ClassTLS, errors.KindFailedPrecondition},
+		{"refused", opError(syscall.ECONNREFUSED), ClassConnectionRefused, errors.KindUnavailable},
+		{"reset", opError(syscall.ECONNRESET), ClassConnectionReset, errors.KindUnavailable},
+		{"broken pipe", opError(syscall.EPIPE), ClassConnectionReset, errors.KindUnavailable},
+		{"unreachable", opError(syscall.EHOSTUNREACH), ClassUnreachable, errors.KindUnavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.class, Class(tt.err))
+			assert.Equal(t, tt.kind, Classify(tt.err))
+		})
+	}
+}
+
+func TestFields(t *testing.T) {
+	assert.Equal(t, errors.Fields{
+		ClassField: ClassConnectionRefused,
+		HostField:  "10.0.0.1:5432",
+		ErrnoField: int(syscall.ECONNREFUSED),
+	}, Fields(errors.Wrap(opError(syscall.ECONNREFUSED), "connecting to database")))
+
+	assert.Equal(t, errors.Fields{
+		ClassField: ClassDNS,
+		HostField:  "db.internal",
+	}, Fields(&net.DNSError{Name: "db.internal"}))
+
+	assert.Equal(t, errors.Fields{
+		ClassField:      ClassTLS,
+		ServerNameField: "db.internal",
+	}, Fields(x509.HostnameError{Certificate: &x509.Certificate{}, Host: "db.internal"}))
+
+	assert.Equal(t, errors.Fields{}, Fields(io.EOF))
+}
+
+func TestAnnotate(t *testing.T) {
+	assert.Nil(t, Annotate(nil))
+
+	cause := opError(syscall.ECONNRESET)
+	err := Annotate(cause)
+
+	assert.Equal(t, cause.Error(), err.Error())
+	assert.True(t, stderrors.Is(err, syscall.ECONNRESET))
+	assert.Equal(t, errors.KindUnavailable, errors.GetKind(err))
+	assert.Equal(t, ClassConnectionReset, errors.GetFields(err)[ClassField])
+
+	err = Annotate(io.EOF)
+	assert.Equal(t, errors.KindUnknown, errors.GetKind(err))
+	assert.Empty(t, errors.GetFields(err))
+}
+
+func TestClassifyDial(t *testing.T) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	assert.NoError(t, err)
+
+	addr := l.Addr().String()
+	assert.NoError(t, l.Close())
+
+	_, err = net.Dial("tcp", addr)
+	assert.Equal(t, ClassConnectionRefused, Class(err))
+	assert.Equal(t, addr, Fields(err)[HostField])
+}
@@ -0,0 +1,160 @@
+package otelx
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/hexbee-net/errors"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+// OTelAttributes returns the fields of the chain of err as attributes, for
+// spans as well as for the measurements of metric instruments.
+// Nested maps are flattened into dotted keys ("request.method"), slices of
+// basic values become slice attributes and the members of other slices get
+// their index as key suffix ("items.0.id").
+// Values without an attribute type are recorded in their default format.
+// Attributes are sorted by key.
+func OTelAttributes(err error) []attribute.KeyValue {
+	fields := errors.GetFields(err)
+	attrs := make([]attribute.KeyValue, 0, len(fields))
+
+	for _, e := range sortedEntries(reflect.ValueOf(fields)) {
+		attrs = appendAttributes(attrs, e.key, e.value)
+	}
+
+	return attrs
+}
+
+func appendAttributes(attrs []attribute.KeyValue, k string, v interface{}) []attribute.KeyValue {
+	if kv, ok := scalarAttribute(k, v); ok {
+		return append(attrs, kv)
+	}
+
+	rv := reflect.ValueOf(v)
+
+	switch rv.Kind() { //nolint:exhaustive // other kinds are formatted
+	case reflect.Map:
+		for _, e := range sortedEntries(rv) {
+			attrs = appendAttributes(attrs, k+"."+e.key, e.value)
+		}
+
+		return attrs
+	case reflect.Slice, reflect.Array:
+		if kv, ok := sliceAttribute(k, rv); ok {
+			return append(attrs, kv)
+		}
+
+		for i := 0; i < rv.Len(); i++ {
+			attrs = appendAttributes(attrs, k+"."+strconv.Itoa(i), rv.Index(i).Interface())
+		}
+
+		return attrs
+	default:
+		return append(attrs, attribute.String(k, errors.FormatField(k, v)))
+	}
+}
+
+func scalarAttribute(k string, v interface{}) (attribute.KeyValue, bool) {
+	switch v := v.(type) {
+	case string:
+		return attribute.String(k, v), true
+	case []byte:
+		return attribute.String(k, string(v)), true
+	case bool:
+		return attribute.Bool(k, v), true
+	case time.Duration:
+		return attribute.String(k, v.String()), true
+	case time.Time:
+		return attribute.String(k, v.Format(time.RFC3339Nano)), true
+	case int:
+		return attribute.Int(k, v), true
+	case int8:
+		return attribute.Int64(k, int64(v)), true
+	case int16:
+		return attribute.Int64(k, int64(v)), true
+	case int32:
+		return attribute.Int64(k, int64(v)), true
+	case int64:
+		return attribute.Int64(k, v), true
+	case uint8:
+		return attribute.Int64(k, int64(v)), true
+	case uint16:
+		return attribute.Int64(k, int64(v)), true
+	case uint32:
+		return attribute.Int64(k, int64(v)), true
+	case float32:
+		return attribute.Float64(k, float64(v)), true
+	case float64:
+		return attribute.Float64(k, v), true
+	default:
+		return attribute.KeyValue{}, false
+	}
+}
+
+// sliceAttribute returns the slice attribute holding the elements of rv when
+// they are all of the same basic type.
+func sliceAttribute(k string, rv reflect.Value) (attribute.KeyValue, bool) {
+	n := rv.Len()
+
+	switch rv.Type().Elem().Kind() { //nolint:exhaustive // other kinds are flattened
+	case reflect.String:
+		s := make([]string, n)
+		for i := range s {
+			s[i] = rv.Index(i).String()
+		}
+
+		return attribute.StringSlice(k, s), true
+	case reflect.Bool:
+		s := make([]bool, n)
+		for i := range s {
+			s[i] = rv.Index(i).Bool()
+		}
+
+		return attribute.BoolSlice(k, s), true
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if rv.Type().Elem() == reflect.TypeOf(time.Duration(0)) {
+			return attribute.KeyValue{}, false
+		}
+
+		s := make([]int64, n)
+		for i := range s {
+			s[i] = rv.Index(i).Int()
+		}
+
+		return attribute.Int64Slice(k, s), true
+	case reflect.Float32, reflect.Float64:
+		s := make([]float64, n)
+		for i := range s {
+			s[i] = rv.Index(i).Float()
+		}
+
+		return attribute.Float64Slice(k, s), true
+	default:
+		return attribute.KeyValue{}, false
+	}
+}
+
+type entry struct {
+	key   string
+	value interface{}
+}
+
+// sortedEntries returns the entries of the map rv, with their keys in their
+// default format, sorted by key.
+func sortedEntries(rv reflect.Value) []entry {
+	entries := make([]entry, 0, rv.Len())
+
+	for it := rv.MapRange(); it.Next(); {
+		entries = append(entries, entry{
+			key:   errors.FormatField("", it.Key().Interface()),
+			value: it.Value().Interface(),
+		})
+	}
+
+	sort.Slice(entries, func(i, j int) bool { return entries[i].key < entries[j].key })
+
+	return entries
+}
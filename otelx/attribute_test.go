@@ -0,0 +1,51 @@
+package otelx
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/hexbee-net/errors"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+)
+
+func TestOTelAttributes(t *testing.T) {
+	err := errors.WithFields(io.EOF, errors.Fields{
+		"string":   "s",
+		"int":      1,
+		"uint8":    uint8(2),
+		"float":    1.5,
+		"bool":     true,
+		"duration": time.Second,
+		"time":     time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC),
+		"bytes":    []byte("b"),
+		"strings":  []string{"a", "b"},
+		"ints":     []int{1, 2},
+		"request":  map[string]interface{}{"method": "GET", "headers": errors.Fields{"accept": "*/*"}},
+		"items":    []interface{}{map[string]int{"id": 1}, "x"},
+		"other":    struct{ A int }{1},
+		"nil":      nil,
+	})
+
+	assert.Equal(t, []attribute.KeyValue{
+		attribute.Bool("bool", true),
+		attribute.String("bytes", "b"),
+		attribute.String("duration", "1s"),
+		attribute.Float64("float", 1.5),
+		attribute.Int("int", 1),
+		attribute.Int64Slice("ints", []int64{1, 2}),
+		attribute.Int64("items.0.id", 1),
+		attribute.String("items.1", "x"),
+		attribute.String("nil", "<nil>"),
+		attribute.String("other", "{1}"),
+		attribute.String("request.headers.accept", "*/*"),
+		attribute.String("request.method", "GET"),
+		attribute.String("string", "s"),
+		attribute.StringSlice("strings", []string{"a", "b"}),
+		attribute.String("time", "2020-06-01T00:00:00Z"),
+		attribute.Int64("uint8", 2),
+	}, OTelAttributes(err))
+
+	assert.Empty(t, OTelAttributes(io.EOF))
+}
@@ -0,0 +1,109 @@
+// Package otelx records errors on OpenTelemetry spans and converts their
+// fields into attributes.
+//
+// It lives in its own module so that the core package does not depend on the
+// OpenTelemetry SDK.
+package otelx
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/hexbee-net/errors"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// MaxFrames is the number of frames, starting from the top of the stack,
+// attached to the recorded exception event.
+const MaxFrames = 10
+
+// Attribute keys set on the exception event in addition to the error fields.
+const (
+	KindKey       = attribute.Key("error.kind")
+	CodeKey       = attribute.Key("error.code")
+	MessageKey    = attribute.Key("error.message")
+	StacktraceKey = attribute.Key("exception.stacktrace")
+)
+
+// RecordError records the root cause of err as an exception event on span and
+// sets the span status to Error.
+// The event carries the fields of the chain, its kind and code, the complete
+// message and the top frames of the deepest stack trace.
+// If err is nil or the span is not recording, RecordError does nothing.
+func RecordError(span trace.Span, err error, opts ...trace.EventOption) {
+	if err == nil || !span.IsRecording() {
+		return
+	}
+
+	opts = append(opts, trace.WithAttributes(Attributes(err)...))
+
+	span.RecordError(errors.Cause(err), opts...)
+	span.SetStatus(codes.Error, statusDescription(err))
+}
+
+// Attributes returns the event attributes describing err: the attributes of
+// its fields given by OTelAttributes, its kind and code, its complete message
+// and the top frames of its deepest stack trace.
+func Attributes(err error) []attribute.KeyValue {
+	attrs := OTelAttributes(err)
+
+	if kind := errors.GetKind(err); kind != errors.KindUnknown {
+		attrs = append(attrs, KindKey.String(kind.String()))
+	}
+
+	if code := errors.GetCode(err); code != "" {
+		attrs = append(attrs, CodeKey.String(code))
+	}
+
+	attrs = append(attrs, MessageKey.String(err.Error()))
+
+	if st := stacktrace(err); st != "" {
+		attrs = append(attrs, StacktraceKey.String(st))
+	}
+
+	return attrs
+}
+
+func statusDescription(err error) string {
+	if code := errors.GetCode(err); code != "" {
+		return code
+	}
+
+	if kind := errors.GetKind(err); kind != errors.KindUnknown {
+		return kind.String()
+	}
+
+	return err.Error()
+}
+
+// stacktrace renders the top frames of the deepest stack trace in the chain.
+func stacktrace(err error) string {
+	stack, ok := errors.StackOf(err)
+	if !ok {
+		return ""
+	}
+
+	if len(stack) > MaxFrames {
+		stack = stack[:MaxFrames]
+	}
+
+	pcs := stack.PCs()
+
+	var sb strings.Builder
+
+	frames := runtime.CallersFrames(pcs)
+
+	for {
+		f, more := frames.Next()
+		_, _ = fmt.Fprintf(&sb, "%s\n\t%s:%d\n", f.Function, f.File, f.Line)
+
+		if !more {
+			break
+		}
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,74 @@
+package otelx
+
+import (
+	"io"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type recordingSpan struct {
+	trace.Span
+	recording   bool
+	recorded    error
+	code        codes.Code
+	description string
+}
+
+func (s *recordingSpan) IsRecording() bool { return s.recording }
+
+func (s *recordingSpan) RecordError(err error, _ ...trace.EventOption) { s.recorded = err }
+
+func (s *recordingSpan) SetStatus(code codes.Code, description string) {
+	s.code, s.description = code, description
+}
+
+func TestRecordError(t *testing.T) {
+	tests := []struct {
+		err         error
+		description string
+	}{
+		{errors.Wrap(io.EOF, "read error"), "read error: EOF"},
+		{errors.WithKind(io.EOF, errors.KindNotFound), "not_found"},
+		{errors.WithCode(errors.WithKind(io.EOF, errors.KindNotFound), "file.missing"), "file.missing"},
+	}
+
+	for _, tt := range tests {
+		span := &recordingSpan{recording: true}
+		RecordError(span, tt.err)
+
+		assert.Equal(t, io.EOF, span.recorded)
+		assert.Equal(t, codes.Error, span.code)
+		assert.Equal(t, tt.description, span.description)
+	}
+}
+
+func TestRecordErrorSkipped(t *testing.T) {
+	span := &recordingSpan{recording: true}
+	RecordError(span, nil)
+	assert.Nil(t, span.recorded)
+
+	span = &recordingSpan{recording: false}
+	RecordError(span, io.EOF)
+	assert.Nil(t, span.recorded)
+}
+
+func TestAttributes(t *testing.T) {
+	err := errors.WithKind(errors.WithField(errors.New("boom"), "key", "value"), errors.KindInternal)
+
+	keys := make(map[attribute.Key]bool)
+	for _, kv := range Attributes(err) {
+		keys[kv.Key] = true
+	}
+
+	assert.Equal(t, map[attribute.Key]bool{
+		"key":         true,
+		KindKey:       true,
+		MessageKey:    true,
+		StacktraceKey: true,
+	}, keys)
+}
@@ -0,0 +1,78 @@
+package errors
+
+// Partial is the result of a batch operation that may succeed for some items
+// and fail for others, pairing the items that succeeded with the errors of
+// the ones that did not:
+//
+//     var res errors.Partial[User]
+//
+//     for _, id := range ids {
+//            u, err := load(id)
+//            if err != nil {
+//                   res.Fail(errors.WithFields(err, errors.Fields{"id": id}))
+//
+//                   continue
+//            }
+//
+//            res.Succeed(u)
+//     }
+//
+//     return res.Result()
+//
+// The zero Partial is empty and ready to use.
+// A Partial is not safe for concurrent use.
+type Partial[T any] struct {
+	// Items are the items that succeeded, in the order they were added.
+	Items []T
+
+	errs  []error
+	stack stack
+	err   error
+}
+
+// Succeed adds item to the items that succeeded.
+func (p *Partial[T]) Succeed(item T) {
+	p.Items = append(p.Items, item)
+}
+
+// Fail adds err to the failures, the first call recording the stack trace of
+// the error returned by Err.
+// If err is nil, Fail does nothing.
+func (p *Partial[T]) Fail(err error) {
+	if err == nil {
+		return
+	}
+
+	if p.stack.isZero() {
+		p.stack = callers()
+	}
+
+	p.errs = appendFlat(p.errs, err)
+	p.err = nil
+}
+
+// Failed returns the number of failures.
+func (p *Partial[T]) Failed() int {
+	return len(p.errs)
+}
+
+// Err returns a MultiError holding the failures, in the order they were
+// added and flattened like by Append.
+// The error is built by the first call, the next ones returning the same
+// error until Fail is called again.
+// If nothing failed, Err returns nil.
+func (p *Partial[T]) Err() error {
+	if len(p.errs) == 0 || p.err != nil {
+		return p.err
+	}
+
+	p.err = newMulti(append([]error(nil), p.errs...), p.stack)
+
+	return p.err
+}
+
+// Result returns the items that succeeded along with the error returned by
+// Err.
+func (p *Partial[T]) Result() ([]T, error) {
+	return p.Items, p.Err()
+}
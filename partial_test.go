@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPartial(t *testing.T) {
+	var p Partial[int]
+
+	items, err := p.Result()
+	assert.Nil(t, items)
+	assert.Nil(t, err)
+
+	p.Succeed(1)
+	p.Fail(nil)
+	p.Fail(io.EOF)
+	p.Succeed(3)
+	p.Fail(Append(nil, io.ErrUnexpectedEOF, io.ErrClosedPipe))
+
+	items, err = p.Result()
+	assert.Equal(t, []int{1, 3}, items)
+	assert.Equal(t, 3, p.Failed())
+
+	m, ok := err.(*MultiError)
+	if assert.True(t, ok) {
+		assert.Equal(t, []error{io.EOF, io.ErrUnexpectedEOF, io.ErrClosedPipe}, m.Errors())
+	}
+
+	st, hasStack := StackOf(err)
+	if stacksCompiled && assert.True(t, hasStack) {
+		assert.Equal(t, "github.com/hexbee-net/errors.TestPartial", st[0].Function())
+	}
+
+	assert.Same(t, err, p.Err())
+
+	p.Fail(io.ErrNoProgress)
+	assert.Len(t, m.Errors(), 3, "the returned errors are not modified")
+	assert.Len(t, p.Err().(*MultiError).Errors(), 4)
+}
+
+func TestPartialHooks(t *testing.T) {
+	var calls int32
+
+	withHooks(t, func(err error) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+
+	var p Partial[int]
+
+	p.Fail(io.EOF)
+
+	assert.Same(t, p.Err(), p.Err())
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
@@ -0,0 +1,110 @@
+package errors
+
+import (
+	"path"
+	"runtime/debug"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+//nolint:gochecknoglobals // the prefix is configured process-wide.
+var pathPrefix atomic.Value // string
+
+//nolint:gochecknoglobals // relative paths are enabled process-wide.
+var relativePaths int32
+
+//nolint:gochecknoglobals // clickable frames are enabled process-wide.
+var clickableFrames int32
+
+//nolint:gochecknoglobals // the build information never changes.
+var (
+	mainModuleOnce sync.Once
+	mainModulePath string
+)
+
+// SetPathPrefix sets the prefix trimmed from the file paths printed when
+// formatting the stack traces of errors with the %+v verb, such as the
+// directory a program is built in.
+// No prefix is trimmed by default.
+func SetPathPrefix(prefix string) {
+	pathPrefix.Store(prefix)
+	formatOptionsChanged()
+}
+
+// RelativePaths enables or disables the printing of the files of the main
+// module relative to the root of the module, as found in the build information
+// of the program, when formatting the stack traces of errors with the %+v verb,
+// making them stable across build machines.
+// The files of other modules keep their paths.
+// Paths are printed in full by default.
+func RelativePaths(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&relativePaths, v)
+	formatOptionsChanged()
+}
+
+// ClickableFrames enables or disables the printing of every frame as a single
+// line starting with its location, such as "pkg/file.go:123: pkg.Func", when
+// formatting the stack traces of errors with the %+v verb: the format editors
+// and IDEs turn into links to the source in terminal output.
+// Frames are printed on two lines, the function then the location, by default.
+func ClickableFrames(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&clickableFrames, v)
+	formatOptionsChanged()
+}
+
+func clickable() bool {
+	return atomic.LoadInt32(&clickableFrames) == 1
+}
+
+// displayPath returns the path of file, of the function function, as printed
+// in stack traces.
+func displayPath(function, file string) string {
+	if prefix, _ := pathPrefix.Load().(string); prefix != "" && strings.HasPrefix(file, prefix) {
+		return strings.TrimPrefix(file, prefix)
+	}
+
+	if atomic.LoadInt32(&relativePaths) == 1 {
+		if rel, ok := moduleRelative(mainModule(), function, file); ok {
+			return rel
+		}
+	}
+
+	return file
+}
+
+// moduleRelative returns the path of file relative to the root of module,
+// derived from the import path of the package of function.
+// It returns false if function is not part of module.
+func moduleRelative(module, function, file string) (string, bool) {
+	pkg := strings.TrimSuffix(funcPackage(function), "_test")
+	if module == "" || (pkg != module && !strings.HasPrefix(pkg, module+"/")) {
+		return "", false
+	}
+
+	dir := strings.TrimPrefix(strings.TrimPrefix(pkg, module), "/")
+
+	// The runtime reports paths with forward slashes on every platform.
+	return path.Join(dir, path.Base(file)), true
+}
+
+// mainModule returns the path of the main module of the program.
+func mainModule() string {
+	mainModuleOnce.Do(func() {
+		if bi, ok := debug.ReadBuildInfo(); ok {
+			mainModulePath = bi.Main.Path
+		}
+	})
+
+	return mainModulePath
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestModuleRelative(t *testing.T) {
+	tests := []struct {
+		function string
+		file     string
+		want     string
+		ok       bool
+	}{
+		{"github.com/org/app.main", "/build/app/main.go", "main.go", true},
+		{"github.com/org/app/cmd/srv.(*Server).Run", "/build/app/cmd/srv/server.go", "cmd/srv/server.go", true},
+		{"github.com/org/app_test.TestRun", "/build/app/run_test.go", "run_test.go", true},
+		{"github.com/org/application.main", "/build/application/main.go", "", false},
+		{"runtime.goexit", "/usr/local/go/src/runtime/asm_amd64.s", "", false},
+	}
+
+	for _, tt := range tests {
+		got, ok := moduleRelative("github.com/org/app", tt.function, tt.file)
+		assert.Equal(t, tt.ok, ok, tt.function)
+		assert.Equal(t, tt.want, got, tt.function)
+	}
+}
+
+func TestRelativePaths(t *testing.T) {
+	requireStacks(t)
+
+	err := New("boom")
+
+	RelativePaths(true)
+	defer RelativePaths(false)
+
+	assert.Contains(t, fmt.Sprintf("%+v", err), "errors.TestRelativePaths\n\tpath_test.go:")
+
+	RelativePaths(false)
+	assert.NotContains(t, fmt.Sprintf("%+v", err), "\n\tpath_test.go:")
+}
+
+func TestSetPathPrefix(t *testing.T) {
+	requireStacks(t)
+
+	err := New("boom")
+	file := Caller(0).File()
+
+	SetPathPrefix(file[:len(file)-len("path_test.go")])
+	defer SetPathPrefix("")
+
+	assert.Contains(t, fmt.Sprintf("%+v", err), "errors.TestSetPathPrefix\n\tpath_test.go:")
+}
+
+func TestClickableFrames(t *testing.T) {
+	requireStacks(t)
+
+	err := New("boom")
+	line := Caller(0).Line() - 1
+
+	ClickableFrames(true)
+	defer ClickableFrames(false)
+
+	assert.Contains(t, fmt.Sprintf("%+v", err),
+		fmt.Sprintf("\n%s:%d: github.com/hexbee-net/errors.TestClickableFrames", Caller(0).File(), line))
+
+	RelativePaths(true)
+	defer RelativePaths(false)
+
+	assert.Contains(t, fmt.Sprintf("%+v", err),
+		fmt.Sprintf("\npath_test.go:%d: github.com/hexbee-net/errors.TestClickableFrames", line))
+}
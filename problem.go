@@ -0,0 +1,265 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// ProblemContentType is the media type of Problem Details documents.
+const ProblemContentType = "application/problem+json"
+
+// ProblemTypePrefix prefixes the codes of errors to form the type URI of
+// their Problem Details, unless the code is already a URI.
+const ProblemTypePrefix = "urn:problem-type:"
+
+// problemBlankType is the type of problems that carry no code.
+const problemBlankType = "about:blank"
+
+// Problem is a Problem Details document, as described by RFC 9457.
+// Extension members are held in Extensions and encoded alongside the
+// standard members.
+type Problem struct {
+	Type       string
+	Title      string
+	Status     int
+	Detail     string
+	Instance   string
+	Extensions map[string]interface{}
+}
+
+// ToProblem returns the Problem Details describing err.
+// The code of the error gives the problem type, the HTTP status gives the
+// status and title, the user message gives the detail and the public fields
+// become extension members.
+// Internal messages and the fields not registered with RegisterPublicFields
+// are never included.
+// If err is nil, an empty problem will be returned.
+func ToProblem(err error) Problem {
+	if err == nil {
+		return Problem{}
+	}
+
+	p := Problem{
+		Type:   problemType(GetCode(err)),
+		Status: HTTPStatus(err),
+		Detail: GetUserMessage(err),
+	}
+
+	p.Title = http.StatusText(p.Status)
+
+	if fields := PublicFields(GetFields(err)); len(fields) > 0 {
+		p.Extensions = fields
+	}
+
+	return p
+}
+
+//nolint:gochecknoglobals // public fields are registered process-wide.
+var (
+	publicFieldsMu sync.Mutex
+	publicFields   atomic.Value // map[string]struct{}
+)
+
+// RegisterPublicFields marks the fields with the given keys as safe to expose
+// to clients, such as an ID the client sent or a retry delay, so that
+// ToProblem includes them as extension members.
+// The fields are private by default, as they often hold internal details such
+// as queries, URLs or the output of commands; only ViolationsField is public
+// out of the box.
+func RegisterPublicFields(keys ...string) {
+	publicFieldsMu.Lock()
+	defer publicFieldsMu.Unlock()
+
+	current, _ := publicFields.Load().(map[string]struct{})
+
+	registered := make(map[string]struct{}, len(current)+len(keys))
+	for k := range current {
+		registered[k] = struct{}{}
+	}
+
+	for _, k := range keys {
+		registered[k] = struct{}{}
+	}
+
+	publicFields.Store(registered)
+}
+
+// PublicFields returns the fields of fields registered with
+// RegisterPublicFields.
+// If none of the fields is public, PublicFields returns nil.
+func PublicFields(fields Fields) Fields {
+	var public Fields
+
+	for k, v := range fields {
+		if !isPublicField(k) {
+			continue
+		}
+
+		if public == nil {
+			public = make(Fields, len(fields))
+		}
+
+		public[k] = v
+	}
+
+	return public
+}
+
+func isPublicField(key string) bool {
+	if key == ViolationsField {
+		return true
+	}
+
+	registered, _ := publicFields.Load().(map[string]struct{})
+	_, ok := registered[key]
+
+	return ok
+}
+
+// FromProblem rebuilds an error from the Problem Details p, typically
+// received from another service.
+// The returned error carries the code, user message, HTTP status and fields
+// described by the problem and matches local errors with the same code.
+// If p is empty, FromProblem returns nil.
+func FromProblem(p Problem) error {
+	if p.Type == "" && p.Title == "" && p.Status == 0 && p.Detail == "" {
+		return nil
+	}
+
+	msg := p.Detail
+	if msg == "" {
+		msg = p.Title
+	}
+
+	if msg == "" {
+		msg = http.StatusText(p.Status)
+	}
+
+	r := &RemoteError{
+		msg:         msg,
+		code:        problemCode(p.Type),
+		userMessage: p.Detail,
+		status:      p.Status,
+	}
+
+	if len(p.Extensions) > 0 {
+		r.fields = Fields(p.Extensions)
+	}
+
+	return r
+}
+
+// WriteProblem writes the Problem Details describing err as the response
+// to an HTTP request, with the status of the error.
+// If err is nil, there is no problem to describe: WriteProblem only writes
+// the 204 No Content status, without body.
+func WriteProblem(w http.ResponseWriter, err error) error {
+	if err == nil {
+		w.WriteHeader(http.StatusNoContent)
+
+		return nil
+	}
+
+	p := ToProblem(err)
+
+	data, e := json.Marshal(p)
+	if e != nil {
+		return Wrap(e, "encoding problem")
+	}
+
+	w.Header().Set("Content-Type", ProblemContentType)
+	w.WriteHeader(p.Status)
+
+	_, e = w.Write(data)
+
+	return e
+}
+
+func problemType(code string) string {
+	switch {
+	case code == "":
+		return problemBlankType
+	case strings.Contains(code, ":"):
+		return code
+	default:
+		return ProblemTypePrefix + code
+	}
+}
+
+func problemCode(typ string) string {
+	if typ == problemBlankType {
+		return ""
+	}
+
+	return strings.TrimPrefix(typ, ProblemTypePrefix)
+}
+
+// MarshalJSON implements json.Marshaler.
+// Extension values that cannot be encoded are replaced by their default
+// format, and extensions never override the standard members.
+func (p Problem) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{}, len(p.Extensions)+len(problemMembers))
+
+	for k, v := range p.Extensions {
+		m[k] = v
+	}
+
+	jsonFields(m)
+
+	for _, k := range problemMembers {
+		delete(m, k)
+	}
+
+	setString := func(k, v string) {
+		if v != "" {
+			m[k] = v
+		}
+	}
+
+	setString("type", p.Type)
+	setString("title", p.Title)
+	setString("detail", p.Detail)
+	setString("instance", p.Instance)
+
+	if p.Status != 0 {
+		m["status"] = p.Status
+	}
+
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON implements json.Unmarshaler.
+// Members whose value does not have the expected type are ignored.
+func (p *Problem) UnmarshalJSON(data []byte) error {
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return err
+	}
+
+	*p = Problem{}
+	p.Type, _ = m["type"].(string)
+	p.Title, _ = m["title"].(string)
+	p.Detail, _ = m["detail"].(string)
+	p.Instance, _ = m["instance"].(string)
+
+	if status, ok := m["status"].(float64); ok {
+		p.Status = int(status)
+	}
+
+	for _, k := range problemMembers {
+		delete(m, k)
+	}
+
+	if len(m) > 0 {
+		p.Extensions = m
+	}
+
+	return nil
+}
+
+// problemMembers lists the standard members of Problem Details documents.
+//nolint:gochecknoglobals // read-only list of member names.
+var problemMembers = []string{"type", "title", "status", "detail", "instance"}
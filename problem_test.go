@@ -0,0 +1,184 @@
+package errors
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withPublicFields(t *testing.T, keys ...string) {
+	t.Helper()
+
+	publicFields.Store(map[string]struct{}(nil))
+	RegisterPublicFields(keys...)
+
+	t.Cleanup(func() { publicFields.Store(map[string]struct{}(nil)) })
+}
+
+func TestToProblem(t *testing.T) {
+	withPublicFields(t, "id")
+
+	tests := []struct {
+		name string
+		err  error
+		want Problem
+	}{
+		{
+			name: "nil",
+			err:  nil,
+			want: Problem{},
+		},
+		{
+			name: "plain",
+			err:  New("boom"),
+			want: Problem{
+				Type:   "about:blank",
+				Title:  "Internal Server Error",
+				Status: http.StatusInternalServerError,
+			},
+		},
+		{
+			name: "annotated",
+			err: WithUserMessage(
+				WithCode(WithKind(WithFields(New("no row"), Fields{"id": 42, "query": "SELECT"}), KindNotFound), "user.not_found"),
+				"The user does not exist.",
+			),
+			want: Problem{
+				Type:       "urn:problem-type:user.not_found",
+				Title:      "Not Found",
+				Status:     http.StatusNotFound,
+				Detail:     "The user does not exist.",
+				Extensions: map[string]interface{}{"id": 42},
+			},
+		},
+		{
+			name: "private fields",
+			err:  WithFields(New("no row"), Fields{"query": "SELECT", "url": "http://internal"}),
+			want: Problem{
+				Type:   "about:blank",
+				Title:  "Internal Server Error",
+				Status: http.StatusInternalServerError,
+			},
+		},
+		{
+			name: "uri code",
+			err:  WithHTTPStatus(WithCode(New("boom"), "https://example.com/probs/out-of-credit"), http.StatusForbidden),
+			want: Problem{
+				Type:   "https://example.com/probs/out-of-credit",
+				Title:  "Forbidden",
+				Status: http.StatusForbidden,
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, ToProblem(tt.err))
+		})
+	}
+}
+
+func TestPublicFields(t *testing.T) {
+	withPublicFields(t)
+
+	fields := Fields{"id": 42, "retry": "1s", ViolationsField: []Violation{}, "query": "SELECT"}
+
+	assert.Equal(t, Fields{ViolationsField: []Violation{}}, PublicFields(fields))
+
+	RegisterPublicFields("id")
+	RegisterPublicFields("retry")
+
+	assert.Equal(t, Fields{"id": 42, "retry": "1s", ViolationsField: []Violation{}}, PublicFields(fields))
+	assert.Nil(t, PublicFields(Fields{"query": "SELECT"}))
+	assert.Nil(t, PublicFields(nil))
+}
+
+func TestFromProblem(t *testing.T) {
+	assert.Nil(t, FromProblem(Problem{}))
+
+	err := FromProblem(Problem{
+		Type:       "urn:problem-type:user.not_found",
+		Title:      "Not Found",
+		Status:     http.StatusNotFound,
+		Detail:     "The user does not exist.",
+		Extensions: map[string]interface{}{"id": 42.0},
+	})
+
+	assert.Equal(t, "The user does not exist.", err.Error())
+	assert.Equal(t, "user.not_found", GetCode(err))
+	assert.Equal(t, "The user does not exist.", GetUserMessage(err))
+	assert.Equal(t, http.StatusNotFound, HTTPStatus(err))
+	assert.Equal(t, Fields{"id": 42.0}, GetFields(err))
+	assert.True(t, errors.Is(err, WithCode(New("not found"), "user.not_found")))
+
+	err = FromProblem(Problem{Type: "about:blank", Title: "Bad Gateway", Status: http.StatusBadGateway})
+	assert.Equal(t, "Bad Gateway", err.Error())
+	assert.Equal(t, "", GetCode(err))
+	assert.Equal(t, "", GetUserMessage(err))
+}
+
+func TestProblemJSON(t *testing.T) {
+	p := Problem{
+		Type:     "urn:problem-type:user.not_found",
+		Title:    "Not Found",
+		Status:   http.StatusNotFound,
+		Instance: "/users/42",
+		Extensions: map[string]interface{}{
+			"id":      42,
+			"handler": unencodable{},
+			"status":  "ignored",
+		},
+	}
+
+	data, err := json.Marshal(p)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"type": "urn:problem-type:user.not_found",
+		"title": "Not Found",
+		"status": 404,
+		"instance": "/users/42",
+		"id": 42,
+		"handler": "unencodable"
+	}`, string(data))
+
+	var decoded Problem
+	assert.NoError(t, json.Unmarshal(data, &decoded))
+	assert.Equal(t, Problem{
+		Type:       "urn:problem-type:user.not_found",
+		Title:      "Not Found",
+		Status:     http.StatusNotFound,
+		Instance:   "/users/42",
+		Extensions: map[string]interface{}{"id": 42.0, "handler": "unencodable"},
+	}, decoded)
+
+	assert.Error(t, json.Unmarshal([]byte(`[]`), &decoded))
+}
+
+func TestWriteProblem(t *testing.T) {
+	rec := httptest.NewRecorder()
+	err := WithUserMessage(WithKind(New("constraint violated"), KindInvalidArgument), "The name is required.")
+
+	assert.NoError(t, WriteProblem(rec, err))
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Equal(t, ProblemContentType, rec.Header().Get("Content-Type"))
+	assert.JSONEq(t, `{
+		"type": "about:blank",
+		"title": "Bad Request",
+		"status": 400,
+		"detail": "The name is required."
+	}`, rec.Body.String())
+	assert.NotContains(t, rec.Body.String(), "constraint violated")
+}
+
+func TestWriteProblemNil(t *testing.T) {
+	rec := httptest.NewRecorder()
+
+	assert.NoError(t, WriteProblem(rec, nil))
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Empty(t, rec.Header().Get("Content-Type"))
+	assert.Empty(t, rec.Body.String())
+}
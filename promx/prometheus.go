@@ -0,0 +1,175 @@
+// Package promx counts errors with Prometheus.
+//
+// It lives in its own module so that the core package does not depend on the
+// Prometheus client:
+//
+//     metrics := promx.New(prometheus.CounterOpts{Namespace: "app"})
+//     prometheus.MustRegister(metrics)
+//
+//     metrics.Count(err)
+//
+// Errors can also be counted as they enter the package by registering the
+// Hook method:
+//
+//     errors.RegisterHook(metrics.Hook)
+//
+// The errors discarded with errors.Ignore are counted apart, from the events
+// of a subscription:
+//
+//     sub := errors.Subscribe(64)
+//     defer sub.Close()
+//
+//     go metrics.Watch(sub)
+package promx
+
+import (
+	"strings"
+
+	"github.com/hexbee-net/errors"
+	pkgerrors "github.com/pkg/errors"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Labels of the error counter.
+const (
+	CodeLabel    = "code"
+	KindLabel    = "kind"
+	PackageLabel = "package"
+)
+
+// DefaultName is the name of the counter when none is given in its options.
+const DefaultName = "errors_total"
+
+// ignoredSuffix replaces the "_total" suffix of the name of the counter to
+// name the counter of the ignored errors, such as "errors_ignored_total".
+const ignoredSuffix = "_ignored_total"
+
+type causer interface {
+	Cause() error
+}
+
+type stackTracer interface {
+	StackTrace() pkgerrors.StackTrace
+}
+
+// Metrics counts errors by code, kind and package of the frame that created them.
+// Metrics implements prometheus.Collector.
+type Metrics struct {
+	errors  *prometheus.CounterVec
+	ignored *prometheus.CounterVec
+}
+
+// New returns a Metrics counting errors with a counter built from opts.
+// The ignored errors are counted with a counter of the same options, named
+// after the first one with "_ignored_total" in place of its "_total" suffix.
+func New(opts prometheus.CounterOpts) *Metrics {
+	if opts.Name == "" {
+		opts.Name = DefaultName
+	}
+
+	ignoredOpts := opts
+	ignoredOpts.Name = strings.TrimSuffix(opts.Name, "_total") + ignoredSuffix
+	ignoredOpts.Help = "Number of errors discarded with errors.Ignore by code, kind and package of origin."
+
+	if opts.Help == "" {
+		opts.Help = "Number of errors by code, kind and package of origin."
+	}
+
+	labels := []string{CodeLabel, KindLabel, PackageLabel}
+
+	return &Metrics{
+		errors:  prometheus.NewCounterVec(opts, labels),
+		ignored: prometheus.NewCounterVec(ignoredOpts, labels),
+	}
+}
+
+// Count increments the counter matching the code, kind and origin of err.
+// If err is nil, Count does nothing.
+func (m *Metrics) Count(err error) {
+	if err == nil {
+		return
+	}
+
+	m.errors.WithLabelValues(labels(err)...).Inc()
+}
+
+// CountIgnored increments the counter of the ignored errors matching the
+// code, kind and origin of err.
+// If err is nil, CountIgnored does nothing.
+func (m *Metrics) CountIgnored(err error) {
+	if err == nil {
+		return
+	}
+
+	m.ignored.WithLabelValues(labels(err)...).Inc()
+}
+
+// Watch counts the errors of the errors.EventIgnored events received from
+// sub with CountIgnored, until sub is closed.
+func (m *Metrics) Watch(sub *errors.Subscription) {
+	for e := range sub.C {
+		if e.Type == errors.EventIgnored {
+			m.CountIgnored(e.Err)
+		}
+	}
+}
+
+// labels returns the values of the labels of the counters for err.
+func labels(err error) []string {
+	return []string{errors.GetCode(err), errors.GetKind(err).String(), Package(err)}
+}
+
+// Hook counts err when its outermost layer records the only stack trace of the
+// chain, that is once per error created by New and Errorf or first wrapped by
+// Wrap, Wrapf and WithStack.
+// Hook is meant to be registered with errors.RegisterHook.
+func (m *Metrics) Hook(err error) error {
+	if _, ok := err.(stackTracer); ok && stacks(err) == 1 {
+		m.Count(err)
+	}
+
+	return err
+}
+
+// Describe implements prometheus.Collector.
+func (m *Metrics) Describe(ch chan<- *prometheus.Desc) {
+	m.errors.Describe(ch)
+	m.ignored.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (m *Metrics) Collect(ch chan<- prometheus.Metric) {
+	m.errors.Collect(ch)
+	m.ignored.Collect(ch)
+}
+
+// Package returns the import path of the package where the deepest stack trace
+// of the chain was recorded.
+// If the chain carries no stack trace, an empty string will be returned.
+func Package(err error) string {
+	stack, ok := errors.StackOf(err)
+	if !ok {
+		return ""
+	}
+
+	return stack[0].Package()
+}
+
+func stacks(err error) int {
+	n := 0
+
+	for depth := 0; err != nil && depth < errors.MaxChainDepth(); depth++ {
+		if _, ok := err.(stackTracer); ok {
+			n++
+		}
+
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+
+		err = cause.Cause()
+	}
+
+	return n
+}
@@ -0,0 +1,82 @@
+package promx
+
+import (
+	"io"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/hexbee-net/errors/internal/nostack"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCount(t *testing.T) {
+	m := New(prometheus.CounterOpts{})
+
+	m.Count(nil)
+	m.Count(errors.New("boom"))
+	m.Count(errors.WithCode(errors.WithKind(errors.New("boom"), errors.KindNotFound), "user.missing"))
+	m.Count(errors.WithCode(errors.WithKind(errors.New("boom"), errors.KindNotFound), "user.missing"))
+	m.Count(io.EOF)
+
+	const pkg = "github.com/hexbee-net/errors/promx"
+
+	assert.Equal(t, 3, testutil.CollectAndCount(m))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.errors.WithLabelValues("", "unknown", pkg)))
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.errors.WithLabelValues("user.missing", "not_found", pkg)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.errors.WithLabelValues("", "unknown", "")))
+}
+
+func TestHook(t *testing.T) {
+	m := New(prometheus.CounterOpts{})
+
+	assert.Equal(t, io.EOF, m.Hook(io.EOF))
+
+	err := errors.New("boom")
+	assert.Equal(t, err, m.Hook(err))
+
+	_ = m.Hook(errors.Wrap(io.EOF, "read error"))
+	_ = m.Hook(errors.Wrap(err, "read error"))
+	_ = m.Hook(errors.WithField(err, "key", "value"))
+
+	assert.Equal(t, 2.0, testutil.ToFloat64(m.errors.WithLabelValues("", "unknown", "github.com/hexbee-net/errors/promx")))
+}
+
+func TestWatch(t *testing.T) {
+	m := New(prometheus.CounterOpts{Namespace: "app"})
+	sub := errors.Subscribe(16)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		m.Watch(sub)
+	}()
+
+	assert.Nil(t, errors.Ignore(errors.New("boom")))
+	assert.Nil(t, errors.Ignore(errors.WithKind(errors.New("boom"), errors.KindNotFound)))
+	errors.Report(errors.New("boom"))
+	m.CountIgnored(nil)
+
+	sub.Close()
+	<-done
+
+	const pkg = "github.com/hexbee-net/errors/promx"
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.ignored.WithLabelValues("", "unknown", pkg)))
+	assert.Equal(t, 1.0, testutil.ToFloat64(m.ignored.WithLabelValues("", "not_found", pkg)))
+	assert.Equal(t, 0, testutil.CollectAndCount(m.errors))
+	assert.Equal(t, 2, testutil.CollectAndCount(m, "app_errors_ignored_total"))
+}
+
+func TestPackage(t *testing.T) {
+	assert.Equal(t, "", Package(nil))
+	assert.Equal(t, "", Package(io.EOF))
+
+	if nostack.Enabled {
+		t.Skip("stack traces are compiled out by the nostack build tag")
+	}
+
+	assert.Equal(t, "github.com/hexbee-net/errors/promx", Package(errors.Wrap(io.EOF, "read error")))
+}
@@ -0,0 +1,75 @@
+package errors
+
+// Filter returns err without the leaves for which match returns true, such
+// as the context.Canceled errors of the tasks of a fan-out canceled after the
+// first failure:
+//
+//     err := errors.Filter(g.Wait(), errors.IsCanceled)
+//
+// The aggregates are found down the chain of err and of their members, like
+// in Flatten, match only being called with their leaves.
+// An aggregate losing some of its members is replaced by a MultiError holding
+// the others, which keeps the stack trace of the aggregate if it was a
+// MultiError, the annotations of the layers wrapping it being lost; err is
+// returned as is if nothing matched.
+// If every leaf matches, Filter returns nil.
+func Filter(err error, match func(error) bool) error {
+	if err == nil {
+		return nil
+	}
+
+	filtered, _ := filterTree(err, match, callers(), 0)
+
+	return filtered
+}
+
+// filterTree returns err without the leaves matching match, and whether any
+// leaf matched, st being the stack trace of the rebuilt aggregates that
+// have none and depth the number of aggregates above err.
+func filterTree(err error, match func(error) bool, st stack, depth int) (error, bool) {
+	for e, layer := err, 0; e != nil && layer < MaxChainDepth(); e, layer = causeOf(e), layer+1 {
+		ms, ok := aggregated(e)
+		if !ok {
+			continue
+		}
+
+		if depth == MaxChainDepth() {
+			return err, false
+		}
+
+		kept := make([]error, 0, len(ms))
+		changed := false
+
+		for _, m := range ms {
+			if m == nil {
+				continue
+			}
+
+			f, c := filterTree(m, match, st, depth+1)
+			changed = changed || c
+
+			if f != nil {
+				kept = append(kept, f)
+			}
+		}
+
+		switch {
+		case !changed:
+			return err, false
+		case len(kept) == 0:
+			return nil, true
+		}
+
+		if m, ok := e.(*MultiError); ok && !m.stack.isZero() {
+			st = m.stack
+		}
+
+		return &MultiError{errors: kept, stack: st}, true
+	}
+
+	if match(err) {
+		return nil, true
+	}
+
+	return err, false
+}
@@ -0,0 +1,51 @@
+package errors
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilter(t *testing.T) {
+	canceled := WrapContext(context.Background(), context.Canceled)
+
+	assert.Nil(t, Filter(nil, IsCanceled))
+	assert.Nil(t, Filter(canceled, IsCanceled))
+	assert.Equal(t, io.EOF, Filter(io.EOF, IsCanceled))
+
+	all := Append(nil, canceled, WithStack(context.Canceled))
+	assert.Nil(t, Filter(all, IsCanceled))
+
+	unchanged := Append(nil, io.EOF, io.ErrUnexpectedEOF)
+	assert.Equal(t, unchanged, Filter(unchanged, IsCanceled))
+
+	err := Append(nil, io.EOF, canceled, errors.Join(canceled, io.ErrClosedPipe))
+	filtered := Filter(err, IsCanceled)
+
+	m, ok := filtered.(*MultiError)
+	if assert.True(t, ok) {
+		assert.Equal(t, err.(*MultiError).stack, m.stack)
+		assert.Len(t, m.Errors(), 2)
+		assert.Equal(t, io.EOF, m.Errors()[0])
+
+		inner, ok := m.Errors()[1].(*MultiError)
+		if assert.True(t, ok) {
+			assert.Equal(t, []error{io.ErrClosedPipe}, inner.Errors())
+		}
+	}
+
+	assert.Len(t, err.(*MultiError).Errors(), 3, "the filtered error is not modified")
+}
+
+func TestFilterWrappedAggregate(t *testing.T) {
+	err := Wrap(errors.Join(io.EOF, context.Canceled), "closing")
+
+	filtered := Filter(err, IsCanceled)
+	assert.Equal(t, []error{io.EOF}, filtered.(*MultiError).Errors())
+
+	_, hasStack := StackOf(filtered)
+	assert.Equal(t, stacksCompiled, hasStack)
+}
@@ -0,0 +1,113 @@
+package errors
+
+import (
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+)
+
+// Record is a snapshot of an error kept by a Recorder.
+type Record struct {
+	Time    time.Time
+	Err     error
+	Message string
+	Fields  Fields
+	Stack   errors.StackTrace
+}
+
+// Recorder keeps the last reported errors in a ring buffer.
+type Recorder struct {
+	mu      sync.Mutex
+	records []Record
+	next    int
+	full    bool
+
+	sub  *Subscription
+	done chan struct{}
+}
+
+// NewRecorder returns a Recorder keeping the last size errors published
+// with Report.
+// The recorder must be closed once it is not needed anymore.
+func NewRecorder(size int) *Recorder {
+	if size < 1 {
+		size = 1
+	}
+
+	r := &Recorder{
+		records: make([]Record, size),
+		sub:     Subscribe(size),
+		done:    make(chan struct{}),
+	}
+
+	go r.listen()
+
+	return r
+}
+
+func (r *Recorder) listen() {
+	defer close(r.done)
+
+	for e := range r.sub.C {
+		if e.Type == EventReported {
+			r.add(e.Time, e.Err)
+		}
+	}
+}
+
+// Close stops recording reported errors.
+// Records already kept remain available through Recent.
+func (r *Recorder) Close() {
+	r.sub.Close()
+	<-r.done
+}
+
+// Add records err directly, without going through Report.
+// If err is nil, Add does nothing.
+func (r *Recorder) Add(err error) {
+	if err == nil {
+		return
+	}
+
+	r.add(time.Now(), err)
+}
+
+func (r *Recorder) add(t time.Time, err error) {
+	rec := Record{
+		Time:    t,
+		Err:     err,
+		Message: err.Error(),
+		Fields:  GetFields(err),
+		Stack:   deepestStack(err),
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.records[r.next] = rec
+	r.next = (r.next + 1) % len(r.records)
+
+	if r.next == 0 {
+		r.full = true
+	}
+}
+
+// Recent returns the kept records, most recent first.
+func (r *Recorder) Recent() []Record {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	n := r.next
+	if r.full {
+		n = len(r.records)
+	}
+
+	recent := make([]Record, 0, n)
+
+	for i := 1; i <= n; i++ {
+		recent = append(recent, r.records[(r.next-i+len(r.records))%len(r.records)])
+	}
+
+	return recent
+}
@@ -0,0 +1,60 @@
+package errors
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorderAdd(t *testing.T) {
+	r := NewRecorder(2)
+	defer r.Close()
+
+	assert.Empty(t, r.Recent())
+
+	first := New("first")
+	second := WithField(Wrap(io.EOF, "second"), "key", "value")
+	third := io.EOF
+
+	r.Add(nil)
+	r.Add(first)
+	assert.Len(t, r.Recent(), 1)
+
+	r.Add(second)
+	r.Add(third)
+
+	recent := r.Recent()
+	assert.Len(t, recent, 2)
+
+	assert.Equal(t, third, recent[0].Err)
+	assert.Equal(t, "EOF", recent[0].Message)
+	assert.Empty(t, recent[0].Fields)
+	assert.Nil(t, recent[0].Stack)
+
+	assert.Equal(t, second, recent[1].Err)
+	assert.Equal(t, "second: EOF", recent[1].Message)
+	assert.Equal(t, Fields{"key": "value"}, recent[1].Fields)
+	assert.Equal(t, stacksCompiled, len(recent[1].Stack) > 0)
+	assert.False(t, recent[1].Time.IsZero())
+}
+
+func TestRecorderReport(t *testing.T) {
+	r := NewRecorder(10)
+
+	_ = New("not reported")
+
+	Report(io.EOF)
+	Report(io.ErrUnexpectedEOF)
+
+	assert.Eventually(t, func() bool { return len(r.Recent()) == 2 }, time.Second, time.Millisecond)
+
+	r.Close()
+	Report(io.ErrClosedPipe)
+
+	recent := r.Recent()
+	assert.Len(t, recent, 2)
+	assert.Equal(t, io.ErrUnexpectedEOF, recent[0].Err)
+	assert.Equal(t, io.EOF, recent[1].Err)
+}
@@ -0,0 +1,60 @@
+package errors
+
+import "fmt"
+
+// PanicValueField is the field holding the value of the panics recovered by
+// Recover.
+const PanicValueField = "panic.value"
+
+// Recover recovers the panic of the function deferring it, if any, and sets
+// the error that function returns to an internal error describing the panic:
+//
+//     func handle(req Request) (err error) {
+//            defer errors.Recover(&err)
+//
+//            ...
+//     }
+//
+// The error wraps the panic value if it is an error, carries the value as
+// PanicValueField and the stack trace at the point the panic occurred,
+// replacing the error returned so far, if any.
+// Recover must be deferred directly for the panic to be recovered.
+func Recover(errp *error) {
+	v := recover()
+	if v == nil {
+		return
+	}
+
+	*errp = recovered(v, trimPanicFrames(callers()))
+}
+
+// PanicValue returns the value of the panic recovered by Recover as err.
+// If err does not describe a recovered panic, PanicValue returns false.
+func PanicValue(err error) (interface{}, bool) {
+	v, ok := GetFields(err)[PanicValueField]
+
+	return v, ok
+}
+
+// runTask calls f and returns its error, or the one describing its panic.
+func runTask(f func() error) (err error) {
+	defer Recover(&err)
+
+	return f()
+}
+
+// recovered returns the error describing the recovered panic value v, with
+// the stack trace st.
+func recovered(v interface{}, st stack) error {
+	var err error
+
+	if cause, ok := v.(error); ok {
+		err = wrapStack(cause, "panic", st)
+	} else {
+		err = runHooks(&fundamental{msg: fmt.Sprintf("panic: %v", v), stack: st}, nil)
+
+		publishCreated(err, nil)
+	}
+
+	return WithFields(WithKind(err, KindInternal), Fields{PanicValueField: v})
+}
@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func panicking(v interface{}) (err error) {
+	defer Recover(&err)
+
+	panic(v)
+}
+
+func dereferencing(p *int) (n int, err error) {
+	defer Recover(&err)
+
+	return *p, nil
+}
+
+func TestRecover(t *testing.T) {
+	err := panicking("boom")
+
+	assert.Equal(t, "panic: boom", err.Error())
+	assert.Equal(t, KindInternal, GetKind(err))
+
+	v, ok := PanicValue(err)
+	assert.True(t, ok)
+	assert.Equal(t, "boom", v)
+
+	st, ok := StackOf(err)
+	if stacksCompiled && assert.True(t, ok) {
+		assert.Equal(t, "github.com/hexbee-net/errors.panicking", st[0].Function())
+	}
+
+	err = panicking(io.EOF)
+
+	assert.Equal(t, "panic: EOF", err.Error())
+	assert.Equal(t, io.EOF, Cause(err))
+
+	_, ok = PanicValue(io.EOF)
+	assert.False(t, ok)
+}
+
+func TestRecoverRuntimeError(t *testing.T) {
+	_, err := dereferencing(nil)
+
+	assert.Contains(t, err.Error(), "nil pointer dereference")
+
+	st, ok := StackOf(err)
+	if stacksCompiled && assert.True(t, ok) {
+		assert.Equal(t, "github.com/hexbee-net/errors.dereferencing", st[0].Function())
+	}
+
+	v, _ := PanicValue(err)
+	assert.Implements(t, (*error)(nil), v)
+}
+
+func TestRecoverNoPanic(t *testing.T) {
+	err := func() (err error) {
+		defer Recover(&err)
+
+		return io.EOF
+	}()
+
+	assert.Equal(t, io.EOF, err)
+}
@@ -0,0 +1,184 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+)
+
+// RemoteError is a layer of an error chain decoded from its structured
+// representation, typically received from another process.
+// It keeps the message, annotations and stack trace of the original layer.
+type RemoteError struct {
+	msg         string
+	kind        Kind
+	code        string
+	userMessage string
+	status      int
+	fields      Fields
+	stack       []StackFrame
+	cause       *RemoteError
+}
+
+// FromDocument rebuilds the error chain described by doc.
+// If doc has no layers, FromDocument returns nil.
+func FromDocument(doc Document) error {
+	var cause *RemoteError
+
+	for i := len(doc.Layers) - 1; i >= 0; i-- {
+		l := doc.Layers[i]
+		cause = &RemoteError{
+			msg:         l.Message,
+			kind:        l.Kind,
+			code:        l.Code,
+			userMessage: l.UserMessage,
+			status:      l.Status,
+			fields:      l.Fields,
+			stack:       l.Stack,
+			cause:       cause,
+		}
+	}
+
+	if cause == nil {
+		return nil
+	}
+
+	return cause
+}
+
+// FromJSON rebuilds the error chain encoded by ToJSON.
+// If data does not hold a valid document, the decoding error is returned.
+// If the document has no layers, FromJSON returns nil.
+func FromJSON(data []byte) error {
+	var doc Document
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return Wrap(err, "decoding remote error")
+	}
+
+	return FromDocument(doc)
+}
+
+func (r *RemoteError) Error() string {
+	if r.cause == nil {
+		return r.msg
+	}
+
+	return r.msg + ": " + r.cause.Error()
+}
+
+func (r *RemoteError) Cause() error {
+	if r.cause == nil {
+		return nil
+	}
+
+	return r.cause
+}
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (r *RemoteError) Unwrap() error {
+	return r.Cause()
+}
+
+// Is reports whether target carries the same code as this layer, so that
+// remote errors can be matched against local sentinels with errors.Is.
+func (r *RemoteError) Is(target error) bool {
+	return r.code != "" && r.code == GetCode(target)
+}
+
+func (r *RemoteError) Fields() Fields {
+	return r.fields
+}
+
+func (r *RemoteError) Kind() Kind {
+	return r.kind
+}
+
+func (r *RemoteError) Code() string {
+	return r.code
+}
+
+func (r *RemoteError) UserMessage() string {
+	return r.userMessage
+}
+
+func (r *RemoteError) HTTPStatus() int {
+	return r.status
+}
+
+// Stack returns the frames of the stack trace recorded by the remote process.
+func (r *RemoteError) Stack() []StackFrame {
+	return r.stack
+}
+
+// MarshalJSON implements json.Marshaler.
+func (r *RemoteError) MarshalJSON() ([]byte, error) { return ToJSON(r) }
+
+func (r *RemoteError) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			formatGoString(s, r)
+
+			return
+		}
+
+		if s.Flag('+') {
+			if customFormat(s, r) {
+				return
+			}
+
+			if r.cause != nil {
+				formatCause(s, r.cause)
+				_, _ = io.WriteString(s, "\n")
+			}
+
+			formatMessage(s, r.msg)
+
+			for _, f := range r.stack {
+				formatLocation(s, f.Function, f.File, f.Line)
+			}
+
+			r.formatAnnotations(s)
+
+			return
+		}
+
+		fallthrough
+	case 's':
+		_, _ = io.WriteString(s, r.Error())
+	case 'q':
+		_, _ = fmt.Fprintf(s, "%q", r.Error())
+	}
+}
+
+// formatAnnotations prints the annotations decoded with the layer, like the
+// annotation layers of a local chain do.
+func (r *RemoteError) formatAnnotations(w io.Writer) {
+	if r.kind == "" && r.code == "" && r.userMessage == "" && r.status == 0 && len(r.fields) == 0 {
+		return
+	}
+
+	_, _ = io.WriteString(w, "\n")
+
+	if r.kind != "" {
+		formatAnnotation(w, "kind", r.kind.String())
+	}
+
+	if r.code != "" {
+		formatAnnotation(w, "code", r.code)
+	}
+
+	if r.userMessage != "" {
+		formatAnnotation(w, "user message", r.userMessage)
+	}
+
+	if r.status != 0 {
+		formatAnnotation(w, "status", strconv.Itoa(r.status))
+	}
+
+	limits := GetFieldLimits()
+	for _, k := range r.fields.keys() {
+		formatAnnotation(w, k, FormatField(k, limits.Limit(k, r.fields[k])))
+	}
+}
@@ -0,0 +1,92 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromJSON(t *testing.T) {
+	err := WithCode(
+		WithKind(
+			WithField(Wrap(WithField(New("boom"), "inner", "1"), "read error"), "outer", "2"),
+			KindNotFound,
+		),
+		"file.missing",
+	)
+
+	data, e := ToJSON(err)
+	assert.NoError(t, e)
+
+	remote := FromJSON(data)
+
+	assert.IsType(t, &RemoteError{}, remote)
+	assert.Equal(t, "read error: boom", remote.Error())
+	assert.Equal(t, Fields{"inner": "1", "outer": "2"}, GetFields(remote))
+	assert.Equal(t, KindNotFound, GetKind(remote))
+	assert.Equal(t, "file.missing", GetCode(remote))
+
+	if stacksCompiled {
+		verbose := fmt.Sprintf("%+v", remote)
+		assert.Contains(t, verbose, "boom\ngithub.com/hexbee-net/errors.TestFromJSON\n\t")
+		assert.Contains(t, verbose, "\nread error\ngithub.com/hexbee-net/errors.TestFromJSON\n\t")
+	}
+
+	again, e := ToJSON(remote)
+	assert.NoError(t, e)
+	assert.JSONEq(t, string(data), string(again))
+}
+
+func TestFromJSONEmpty(t *testing.T) {
+	assert.Nil(t, FromJSON([]byte(`{"message": "", "layers": []}`)))
+	assert.Nil(t, FromJSON([]byte(`null`)))
+
+	data, e := ToJSON(nil)
+	assert.NoError(t, e)
+	assert.Nil(t, FromJSON(data))
+}
+
+func TestFromJSONInvalid(t *testing.T) {
+	err := FromJSON([]byte(`{`))
+
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "decoding remote error")
+}
+
+func TestRemoteErrorIs(t *testing.T) {
+	sentinel := WithCode(New("not found"), "user.not_found")
+
+	data, e := ToJSON(Wrap(WithCode(io.EOF, "user.not_found"), "lookup"))
+	assert.NoError(t, e)
+
+	remote := FromJSON(data)
+
+	assert.True(t, errors.Is(remote, sentinel))
+	assert.False(t, errors.Is(remote, WithCode(New("other"), "user.exists")))
+	assert.False(t, errors.Is(remote, io.EOF))
+}
+
+func TestRemoteErrorFormat(t *testing.T) {
+	remote := FromDocument(Document{Layers: []Layer{{Message: "outer"}, {Message: "inner"}}})
+
+	assert.Equal(t, "outer: inner", fmt.Sprintf("%s", remote))
+	assert.Equal(t, "outer: inner", fmt.Sprintf("%v", remote))
+	assert.Equal(t, `"outer: inner"`, fmt.Sprintf("%q", remote))
+	assert.Equal(t, "inner\nouter", fmt.Sprintf("%+v", remote))
+}
+
+func TestRemoteErrorFormatAnnotations(t *testing.T) {
+	remote := FromDocument(Document{Layers: []Layer{
+		{Message: "outer", Kind: KindNotFound, Code: "user.missing", Fields: Fields{"id": 42, "b": "x"}},
+		{Message: "inner", Status: 503, UserMessage: "try again"},
+	}})
+
+	assert.Equal(t, "outer: inner", fmt.Sprintf("%v", remote))
+	assert.Equal(t,
+		"inner\n  user message: try again\n  status: 503\n\nouter\n  kind: not_found\n  code: user.missing\n  b: x\n  id: 42\n",
+		fmt.Sprintf("%+v", remote),
+	)
+}
@@ -0,0 +1,43 @@
+package errors
+
+// IsRetryable reports whether the operation that failed with err may succeed
+// if attempted again.
+// The outermost layer of the chain implementing the following interface
+// decides:
+//
+//     type retryabler interface {
+//            Retryable() bool
+//     }
+//
+// Otherwise, errors are retryable when their kind denotes a transient failure:
+// KindUnavailable, KindResourceExhausted, KindAborted or KindDeadlineExceeded.
+// If err is nil, IsRetryable returns false.
+func IsRetryable(err error) bool {
+	type retryabler interface {
+		Retryable() bool
+	}
+
+	if err == nil {
+		return false
+	}
+
+	for e, depth := err, 0; e != nil && depth < MaxChainDepth(); depth++ {
+		if r, ok := e.(retryabler); ok {
+			return r.Retryable()
+		}
+
+		cause, ok := e.(causer)
+		if !ok {
+			break
+		}
+
+		e = cause.Cause()
+	}
+
+	switch GetKind(err) { //nolint:exhaustive // other kinds are permanent
+	case KindUnavailable, KindResourceExhausted, KindAborted, KindDeadlineExceeded:
+		return true
+	default:
+		return false
+	}
+}
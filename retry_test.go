@@ -0,0 +1,34 @@
+package errors
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type retryable bool
+
+func (r retryable) Error() string   { return "retryable" }
+func (r retryable) Retryable() bool { return bool(r) }
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{nil, false},
+		{io.EOF, false},
+		{WithKind(io.EOF, KindUnavailable), true},
+		{Wrap(WithKind(io.EOF, KindResourceExhausted), "calling api"), true},
+		{WithKind(io.EOF, KindAborted), true},
+		{WithKind(io.EOF, KindDeadlineExceeded), true},
+		{WithKind(io.EOF, KindNotFound), false},
+		{WithKind(retryable(false), KindUnavailable), false},
+		{WithKind(Wrap(retryable(true), "calling api"), KindInvalidArgument), true},
+	}
+
+	for i, tt := range tests {
+		assert.Equal(t, tt.want, IsRetryable(tt.err), "test %d", i+1)
+	}
+}
@@ -0,0 +1,147 @@
+// Package rollbarx converts errors into Rollbar item payloads.
+//
+// Each layer of the chain becomes one trace of the trace_chain, so the
+// structure built with Wrap and WithMessage survives into Rollbar:
+//
+//     item := map[string]interface{}{
+//         "access_token": token,
+//         "data":         rollbarx.NewData(err),
+//     }
+package rollbarx
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/hexbee-net/errors"
+)
+
+type causer interface {
+	Cause() error
+}
+
+// Frame is a single stack frame of a Rollbar trace.
+type Frame struct {
+	Filename string `json:"filename"`
+	Lineno   int    `json:"lineno"`
+	Method   string `json:"method"`
+}
+
+// Exception describes the error of a Rollbar trace.
+type Exception struct {
+	Class   string `json:"class"`
+	Message string `json:"message"`
+}
+
+// Trace is one element of a Rollbar trace_chain.
+type Trace struct {
+	Frames    []Frame   `json:"frames"`
+	Exception Exception `json:"exception"`
+}
+
+// Body is the body of a Rollbar item.
+type Body struct {
+	TraceChain []Trace `json:"trace_chain"`
+}
+
+// Data is the data member of a Rollbar item.
+type Data struct {
+	Body   Body                   `json:"body"`
+	Custom map[string]interface{} `json:"custom,omitempty"`
+}
+
+// NewData returns the data member of a Rollbar item describing err,
+// with the fields of the chain as custom data.
+func NewData(err error) Data {
+	return Data{
+		Body:   Body{TraceChain: TraceChain(err)},
+		Custom: Custom(err),
+	}
+}
+
+// TraceChain returns one trace per layer of the chain, outermost first.
+// A layer starts at a wrapper adding a message (or at the root cause) and
+// includes the wrappers directly above it. The deepest stack trace of the
+// chain, as returned by errors.StackOf, is reported by the trace of the layer
+// recording it.
+// If err is nil, an empty slice will be returned.
+func TraceChain(err error) []Trace {
+	chain := make([]Trace, 0)
+
+	var (
+		top   error
+		stack errors.StackTrace
+		last  = -1
+	)
+
+	for depth := 0; err != nil && depth < errors.MaxChainDepth(); depth++ {
+		if top == nil {
+			top = err
+		}
+
+		cause, ok := err.(causer)
+		if !ok || cause.Cause() == nil || err.Error() != cause.Cause().Error() {
+			if st, found := errors.StackOf(top); found {
+				stack, last = st, len(chain)
+			}
+
+			chain = append(chain, Trace{
+				Frames: make([]Frame, 0),
+				Exception: Exception{
+					Class:   fmt.Sprintf("%T", top),
+					Message: message(err),
+				},
+			})
+
+			top = nil
+		}
+
+		if !ok {
+			break
+		}
+
+		err = cause.Cause()
+	}
+
+	if last >= 0 {
+		chain[last].Frames = frames(stack)
+	}
+
+	return chain
+}
+
+// Custom returns the fields of the chain as Rollbar custom data.
+// If the chain carries no field, Custom returns nil.
+func Custom(err error) map[string]interface{} {
+	fields := errors.GetFields(err)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	return fields
+}
+
+// message returns the message added by err on top of its cause.
+func message(err error) string {
+	if cause, ok := err.(causer); ok && cause.Cause() != nil {
+		return strings.TrimSuffix(err.Error(), ": "+cause.Cause().Error())
+	}
+
+	return err.Error()
+}
+
+// frames converts stack into Rollbar frames, ordered with the most recent call last.
+func frames(stack errors.StackTrace) []Frame {
+	rf := stack.Frames()
+	out := make([]Frame, len(rf))
+
+	for i, f := range rf {
+		out[len(rf)-1-i] = Frame{
+			Filename: f.File,
+			Lineno:   f.Line,
+			Method:   f.Function,
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,114 @@
+package rollbarx
+
+import (
+	"encoding/json"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/hexbee-net/errors/internal/nostack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceChain(t *testing.T) {
+	tests := []struct {
+		err        error
+		classes    []string
+		messages   []string
+		withFrames []bool
+	}{
+		{
+			err:        nil,
+			classes:    []string{},
+			messages:   []string{},
+			withFrames: []bool{},
+		},
+		{
+			err:        io.EOF,
+			classes:    []string{"*errors.errorString"},
+			messages:   []string{"EOF"},
+			withFrames: []bool{false},
+		},
+		{
+			err:        errors.Wrap(io.EOF, "read error"),
+			classes:    []string{"*errors.withStack", "*errors.errorString"},
+			messages:   []string{"read error", "EOF"},
+			withFrames: []bool{true, false},
+		},
+		{
+			err:        errors.WithField(errors.Wrap(errors.New("inner"), "outer"), "key", "value"),
+			classes:    []string{"*errors.withFields", "*errors.fundamental"},
+			messages:   []string{"outer", "inner"},
+			withFrames: []bool{false, true},
+		},
+		{
+			err:        errors.WithMessage(errors.WithMessage(io.EOF, "inner"), "outer"),
+			classes:    []string{"*errors.withMessage", "*errors.withMessage", "*errors.errorString"},
+			messages:   []string{"outer", "inner", "EOF"},
+			withFrames: []bool{false, false, false},
+		},
+	}
+
+	for i, tt := range tests {
+		chain := TraceChain(tt.err)
+
+		classes := make([]string, len(chain))
+		messages := make([]string, len(chain))
+		withFrames := make([]bool, len(chain))
+
+		for j, trace := range chain {
+			classes[j] = trace.Exception.Class
+			messages[j] = trace.Exception.Message
+			withFrames[j] = len(trace.Frames) > 0
+		}
+
+		assert.Equal(t, tt.classes, classes, "test %d", i+1)
+		assert.Equal(t, tt.messages, messages, "test %d", i+1)
+
+		if nostack.Enabled {
+			tt.withFrames = make([]bool, len(chain))
+		}
+
+		assert.Equal(t, tt.withFrames, withFrames, "test %d", i+1)
+	}
+}
+
+func TestTraceChainFrames(t *testing.T) {
+	if nostack.Enabled {
+		t.Skip("stack traces are compiled out by the nostack build tag")
+	}
+
+	chain := TraceChain(errors.New("boom"))
+
+	assert.Len(t, chain, 1)
+
+	frames := chain[0].Frames
+	last := frames[len(frames)-1]
+
+	assert.True(t, strings.HasSuffix(last.Filename, "rollbar_test.go"))
+	assert.Equal(t, "github.com/hexbee-net/errors/rollbarx.TestTraceChainFrames", last.Method)
+	assert.NotZero(t, last.Lineno)
+}
+
+func TestCustom(t *testing.T) {
+	assert.Nil(t, Custom(io.EOF))
+	assert.Equal(t,
+		map[string]interface{}{"key": "value"},
+		Custom(errors.WithField(io.EOF, "key", "value")),
+	)
+}
+
+func TestNewDataJSON(t *testing.T) {
+	b, err := json.Marshal(NewData(errors.WithMessage(io.EOF, "read error")))
+
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"body": {
+			"trace_chain": [
+				{"frames": [], "exception": {"class": "*errors.withMessage", "message": "read error"}},
+				{"frames": [], "exception": {"class": "*errors.errorString", "message": "EOF"}}
+			]
+		}
+	}`, string(b))
+}
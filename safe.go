@@ -0,0 +1,91 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// FormatField returns the default format of the value of the field key.
+// If formatting the value panics, as a String method of a nil pointer may,
+// a placeholder naming the field is returned instead, so that rendering an
+// error never brings the program down.
+func FormatField(key string, value interface{}) (s string) {
+	defer func() {
+		if recover() != nil {
+			s = panicPlaceholder(key)
+		}
+	}()
+
+	// The fmt package recovers from the panics of the methods it calls,
+	// reporting them inline.
+	s = fmt.Sprint(value)
+	if strings.Contains(s, "%!v(PANIC=") {
+		return panicPlaceholder(key)
+	}
+
+	return s
+}
+
+func panicPlaceholder(key string) string {
+	return fmt.Sprintf("<panic rendering field %q>", key)
+}
+
+// jsonEncodable reports whether v can be encoded in JSON, and whether
+// encoding it panicked.
+func jsonEncodable(v interface{}) (ok, panicked bool) {
+	defer func() {
+		if recover() != nil {
+			ok, panicked = false, true
+		}
+	}()
+
+	_, err := json.Marshal(v)
+
+	return err == nil, false
+}
+
+// safeField defers the rendering of a field value to a logging handler,
+// guarding it against panics.
+type safeField struct {
+	key   string
+	value interface{}
+}
+
+func (f safeField) String() string {
+	return FormatField(f.key, f.value)
+}
+
+// MarshalJSON implements json.Marshaler.
+// Values that cannot be encoded are replaced by their default format.
+func (f safeField) MarshalJSON() ([]byte, error) {
+	ok, panicked := jsonEncodable(f.value)
+
+	switch {
+	case ok:
+		return json.Marshal(f.value)
+	case panicked:
+		return json.Marshal(panicPlaceholder(f.key))
+	default:
+		return json.Marshal(FormatField(f.key, f.value))
+	}
+}
+
+// safeFields returns fields with their values capped by the field limits and
+// the values that are not of a basic type guarded against panics when logged.
+func safeFields(fields Fields) Fields {
+	limits := GetFieldLimits()
+	out := make(Fields, len(fields))
+
+	for k, v := range fields {
+		v = limits.Limit(k, v)
+
+		if isGobBasic(v) {
+			out[k] = v
+		} else {
+			out[k] = safeField{k, v}
+		}
+	}
+
+	return out
+}
@@ -0,0 +1,45 @@
+package errors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type panicky struct{}
+
+func (panicky) String() string               { panic("boom") }
+func (panicky) MarshalJSON() ([]byte, error) { panic("boom") }
+
+func TestFormatField(t *testing.T) {
+	assert.Equal(t, "42", FormatField("size", 42))
+	assert.Equal(t, `<panic rendering field "bad">`, FormatField("bad", panicky{}))
+}
+
+func TestPanickyField(t *testing.T) {
+	err := WithFieldList(WithField(io.EOF, "bad", panicky{}), Any("worse", panicky{}))
+
+	assert.Equal(t, "EOF\n  bad: <panic rendering field \"bad\">\n\n  worse: <panic rendering field \"worse\">\n", fmt.Sprintf("%+v", err))
+
+	b, jerr := json.Marshal(err)
+	assert.NoError(t, jerr)
+
+	var doc Document
+
+	assert.NoError(t, json.Unmarshal(b, &doc))
+	assert.Equal(t, `<panic rendering field "bad">`, doc.Layers[0].Fields["bad"])
+
+	fields := GetFields(err).Fields()
+	assert.Equal(t, `<panic rendering field "bad">`, fmt.Sprint(fields["bad"]))
+
+	b, jerr = json.Marshal(fields)
+	assert.NoError(t, jerr)
+	assert.JSONEq(t, `{"bad":"<panic rendering field \"bad\">","worse":"<panic rendering field \"worse\">"}`, string(b))
+
+	b, jerr = json.Marshal(Fields{"ch": make(chan int), "n": 1}.Fields())
+	assert.NoError(t, jerr)
+	assert.Contains(t, string(b), `"n":1`)
+}
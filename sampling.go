@@ -0,0 +1,84 @@
+package errors
+
+import (
+	"math/rand"
+	"sync"
+	"sync/atomic"
+)
+
+// StackSampledField is the field recording whether the full stack trace of an
+// error was kept when stack sampling is enabled.
+const StackSampledField = "stack.sampled"
+
+// StackSampling configures the sampling of stack traces, for programs where
+// capturing the stack of every error is too costly: the errors left out of the
+// sample only record the frame they are created or wrapped at.
+// The zero StackSampling disables sampling.
+type StackSampling struct {
+	// Every keeps the full stack traces of one error out of Every per call
+	// site, starting with the first one.
+	Every int
+	// Rate is the probability, between 0 and 1, for an error to keep its full
+	// stack trace. It is only used when Every is zero.
+	Rate float64
+}
+
+//nolint:gochecknoglobals // the sampling is configured process-wide.
+var (
+	stackSampling atomic.Value // StackSampling
+	siteCounts    sync.Map     // uintptr -> *uint64
+)
+
+// SetStackSampling sets the sampling of the stack traces captured from then
+// on. The decision is recorded in the StackSampledField field of the layer.
+// Stack traces are not sampled by default.
+func SetStackSampling(s StackSampling) {
+	stackSampling.Store(s)
+}
+
+func getStackSampling() StackSampling {
+	s, _ := stackSampling.Load().(StackSampling)
+
+	return s
+}
+
+// Decisions of the sampling of a stack.
+const (
+	notSampled int8 = iota
+	sampleKept
+	sampleSkipped
+)
+
+// keep reports whether the stack of the error created at the call site pc is
+// part of the sample.
+func (s StackSampling) keep(pc uintptr) bool {
+	if s.Every > 0 {
+		v, _ := siteCounts.LoadOrStore(pc, new(uint64))
+
+		return (atomic.AddUint64(v.(*uint64), 1)-1)%uint64(s.Every) == 0
+	}
+
+	return rand.Float64() < s.Rate //nolint:gosec // sampling needs no secure randomness.
+}
+
+// Fields returns the fields recording the sampling of the stack trace, if
+// enabled with SetStackSampling.
+func (f *fundamental) Fields() Fields {
+	return f.stack.fields()
+}
+
+// Fields returns the fields recording the sampling of the stack trace, if
+// enabled with SetStackSampling.
+func (w *withStack) Fields() Fields {
+	return w.stack.fields()
+}
+
+// Fields returns the fields recording the sampling of the stack trace, if
+// enabled with SetStackSampling.
+func (m *MultiError) Fields() Fields {
+	if m.stack.isZero() {
+		return nil
+	}
+
+	return m.stack.fields()
+}
@@ -0,0 +1,57 @@
+package errors
+
+import (
+	"io"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStackSamplingEvery(t *testing.T) {
+	requireStacks(t)
+
+	SetStackSampling(StackSampling{Every: 3})
+	defer SetStackSampling(StackSampling{})
+
+	var kept []bool
+
+	for i := 0; i < 6; i++ {
+		err := Wrap(io.EOF, "sampled")
+
+		st, _ := StackOf(err)
+		sampled, _ := GetFields(err)[StackSampledField].(bool)
+		kept = append(kept, sampled)
+
+		if sampled {
+			assert.Greater(t, len(st), 1)
+		} else {
+			assert.Len(t, st, 1)
+		}
+
+		assert.Equal(t, "TestStackSamplingEvery", st[0].Func())
+	}
+
+	assert.Equal(t, []bool{true, false, false, true, false, false}, kept)
+}
+
+func TestStackSamplingRate(t *testing.T) {
+	requireStacks(t)
+
+	SetStackSampling(StackSampling{Rate: 1})
+
+	assert.Equal(t, true, GetFields(New("kept"))[StackSampledField])
+
+	SetStackSampling(StackSampling{Rate: math.SmallestNonzeroFloat64})
+	defer SetStackSampling(StackSampling{})
+
+	err := New("skipped")
+	st, _ := StackOf(err)
+
+	assert.Len(t, st, 1)
+	assert.Equal(t, false, GetFields(err)[StackSampledField])
+}
+
+func TestStackSamplingDisabled(t *testing.T) {
+	assert.Empty(t, GetFields(New("boom")))
+}
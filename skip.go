@@ -0,0 +1,61 @@
+package errors
+
+// NewSkip returns an error with the supplied message, like New, except that
+// the recorded stack trace starts skip frames above the caller of NewSkip.
+// It lets helpers building errors leave their own frames out of the stack:
+// NewSkip(0, msg) is equivalent to New(msg), and a helper calling NewSkip
+// directly passes 1.
+func NewSkip(skip int, message string) error {
+	err := runHooks(&fundamental{
+		msg:   message,
+		stack: callersSkip(skip),
+	}, nil)
+
+	publishCreated(err, nil)
+
+	return err
+}
+
+// WrapSkip returns an error annotating err with the supplied message, like
+// Wrap, except that the recorded stack trace starts skip frames above the
+// caller of WrapSkip.
+// If err is nil, WrapSkip returns nil.
+func WrapSkip(skip int, err error, message string) error {
+	if err == nil {
+		return nil
+	}
+
+	return wrapStack(err, message, callersSkip(skip))
+}
+
+// WithStackIf annotates err with a stack trace at the point WithStackIf is
+// called, unless a layer of its chain already carries one.
+// It avoids recording a second, mostly identical, stack trace when wrapping
+// errors created by this package.
+// If err is nil, WithStackIf returns nil.
+func WithStackIf(err error) error {
+	if err == nil || hasStack(err) {
+		return err
+	}
+
+	return withStackOn(err, err, callers())
+}
+
+// WrapIf returns an error annotating err with the supplied message, and with
+// a stack trace at the point WrapIf is called unless a layer of its chain
+// already carries one.
+// If err is nil, WrapIf returns nil.
+func WrapIf(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+
+	if hasStack(err) {
+		return runHooks(&withMessage{
+			cause: err,
+			msg:   message,
+		}, err)
+	}
+
+	return wrapStack(err, message, callers())
+}
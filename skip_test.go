@@ -0,0 +1,89 @@
+package errors
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func notFound(what string) error {
+	return NewSkip(1, what+" not found")
+}
+
+func wrapQuery(err error) error {
+	return WrapSkip(1, err, "querying")
+}
+
+func TestNewSkip(t *testing.T) {
+	requireStacks(t)
+
+	err := notFound("user")
+
+	assert.Equal(t, "user not found", err.Error())
+
+	frame, _ := GetCaller(err)
+	assert.Equal(t, "github.com/hexbee-net/errors.TestNewSkip", frame.Function)
+
+	frame, _ = GetCaller(NewSkip(0, "boom"))
+	assert.Equal(t, "github.com/hexbee-net/errors.TestNewSkip", frame.Function)
+}
+
+func TestWrapSkip(t *testing.T) {
+	requireStacks(t)
+
+	assert.Nil(t, WrapSkip(1, nil, "querying"))
+
+	err := wrapQuery(io.EOF)
+
+	assert.Equal(t, "querying: EOF", err.Error())
+
+	frame, _ := GetCaller(err)
+	assert.Equal(t, "github.com/hexbee-net/errors.TestWrapSkip", frame.Function)
+}
+
+func countStacks(err error) int {
+	n := 0
+
+	for err != nil {
+		if _, ok := err.(stackTracer); ok {
+			n++
+		}
+
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+
+		err = cause.Cause()
+	}
+
+	return n
+}
+
+func TestWithStackIf(t *testing.T) {
+	requireStacks(t)
+
+	assert.Nil(t, WithStackIf(nil))
+
+	err := WithStackIf(io.EOF)
+	assert.Equal(t, 1, countStacks(err))
+
+	frame, _ := GetCaller(err)
+	assert.Equal(t, "github.com/hexbee-net/errors.TestWithStackIf", frame.Function)
+
+	assert.Equal(t, err, WithStackIf(err))
+	assert.Equal(t, 1, countStacks(WithStackIf(WithKind(err, KindInternal))))
+}
+
+func TestWrapIf(t *testing.T) {
+	assert.Nil(t, WrapIf(nil, "querying"))
+
+	err := WrapIf(io.EOF, "querying")
+	assert.Equal(t, "querying: EOF", err.Error())
+	assert.Equal(t, 1, countStacks(err))
+
+	err = WrapIf(err, "loading")
+	assert.Equal(t, "loading: querying: EOF", err.Error())
+	assert.Equal(t, 1, countStacks(err))
+}
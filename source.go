@@ -0,0 +1,78 @@
+package errors
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+)
+
+//nolint:gochecknoglobals // the source context is configured process-wide.
+var sourceContext int32
+
+// ShowSource enables the printing of the source code around every application
+// frame, as told by ApplicationFrames, when formatting the stack traces of
+// errors with the %+v verb: the line of the frame is printed along with lines
+// lines before and after it, provided the source file can be read.
+// A zero or negative lines disables it, which is the default.
+func ShowSource(lines int) {
+	if lines < 0 {
+		lines = 0
+	}
+
+	atomic.StoreInt32(&sourceContext, int32(lines))
+	formatOptionsChanged()
+}
+
+// formatSource prints the source code around the line of f, if enabled.
+func formatSource(w io.Writer, f Frame) {
+	n := int(atomic.LoadInt32(&sourceContext))
+	if n == 0 || !ApplicationFrames(f) {
+		return
+	}
+
+	line := f.Line()
+
+	lines, err := readLines(f.File(), line-n, line+n)
+	if err != nil {
+		return
+	}
+
+	start := line - n
+	if start < 1 {
+		start = 1
+	}
+
+	width := len(fmt.Sprint(start + len(lines) - 1))
+
+	for i, text := range lines {
+		marker := " "
+		if start+i == line {
+			marker = ">"
+		}
+
+		_, _ = fmt.Fprint(w, strings.TrimRight(fmt.Sprintf("\n\t%s %*d | %s", marker, width, start+i, text), " "))
+	}
+}
+
+// readLines returns the lines from first to last, both included, of file.
+func readLines(file string, first, last int) ([]string, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var lines []string
+
+	s := bufio.NewScanner(f)
+	for i := 1; i <= last && s.Scan(); i++ {
+		if i >= first {
+			lines = append(lines, strings.TrimRight(s.Text(), " \t\r"))
+		}
+	}
+
+	return lines, s.Err()
+}
@@ -0,0 +1,34 @@
+package errors
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestShowSource(t *testing.T) {
+	requireStacks(t)
+
+	err := New("boom") // the line shown
+
+	assert.NotContains(t, fmt.Sprintf("%+v", err), "the line shown")
+
+	ShowSource(1)
+	defer ShowSource(0)
+
+	got := fmt.Sprintf("%+v", err)
+	assert.Contains(t, got, "\n\t  13 |\n")
+	assert.Contains(t, got, "\n\t> 14 | \terr := New(\"boom\") // the line shown\n")
+	assert.True(t, strings.HasSuffix(got, "\n\t  15 |"), got)
+}
+
+func TestReadLines(t *testing.T) {
+	lines, err := readLines("source_test.go", 0, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"package errors"}, lines)
+
+	_, err = readLines("missing.go", 1, 2)
+	assert.Error(t, err)
+}
@@ -0,0 +1,274 @@
+// Package sqlx translates database errors into error chains classified by
+// kind, so that repositories do not need to match driver messages:
+//
+//     if err := row.Scan(&user.Name); err != nil {
+//         return sqlx.Translate(err)
+//     }
+//
+//     // later on
+//     if errors.GetKind(err) == errors.KindAlreadyExists {
+//         ...
+//     }
+//
+// The errors of database/sql are recognized, along with the errors of the
+// lib/pq, pgx and go-sql-driver/mysql drivers. The package does not depend on
+// the drivers: their errors are recognized by their SQLState method or, for
+// MySQL, by the shape of the error structure.
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	stderrors "errors"
+	"reflect"
+	"strings"
+
+	"github.com/hexbee-net/errors"
+)
+
+// Fields added by Translate.
+const (
+	StateField      = "sql.state"
+	ConstraintField = "sql.constraint"
+	ErrnoField      = "sql.errno"
+)
+
+// SQLSTATE codes and classes recognized by Translate.
+const (
+	StateUniqueViolation      = "23505"
+	StateForeignKeyViolation  = "23503"
+	StateNotNullViolation     = "23502"
+	StateCheckViolation       = "23514"
+	StateSerializationFailure = "40001"
+	StateDeadlockDetected     = "40P01"
+	StateQueryCanceled        = "57014"
+	StateInsufficientPrivs    = "42501"
+
+	ClassConnectionException  = "08"
+	ClassDataException        = "22"
+	ClassInvalidAuthorization = "28"
+	ClassInsufficientResource = "53"
+)
+
+// MySQL error numbers recognized by Translate.
+const (
+	ErrnoDuplicateEntry    = 1062
+	ErrnoRowIsReferenced   = 1451
+	ErrnoNoReferencedRow   = 1452
+	ErrnoLockWaitTimeout   = 1205
+	ErrnoLockDeadlock      = 1213
+	ErrnoAccessDenied      = 1045
+	ErrnoTableAccessDenied = 1142
+	ErrnoBadNull           = 1048
+	ErrnoCheckConstraint   = 3819
+)
+
+// Translate annotates err with the kind matching the database failure it
+// describes, along with the SQLSTATE, constraint name and MySQL error number
+// reported by the driver as fields.
+// Errors that are not recognized are returned with a stack trace only.
+// If err is nil, Translate returns nil.
+func Translate(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	kind, fields := Classify(err)
+
+	err = errors.WithStack(err)
+	if len(fields) > 0 {
+		err = errors.WithFields(err, fields)
+	}
+
+	if kind != errors.KindUnknown {
+		err = errors.WithKind(err, kind)
+	}
+
+	return err
+}
+
+// Classify returns the kind of the database failure described by err and
+// the fields describing the driver error.
+// If err is not recognized, KindUnknown will be returned.
+func Classify(err error) (errors.Kind, errors.Fields) {
+	fields := make(errors.Fields)
+
+	switch {
+	case stderrors.Is(err, sql.ErrNoRows):
+		return errors.KindNotFound, fields
+	case stderrors.Is(err, sql.ErrTxDone):
+		return errors.KindFailedPrecondition, fields
+	case stderrors.Is(err, sql.ErrConnDone), stderrors.Is(err, driver.ErrBadConn):
+		return errors.KindUnavailable, fields
+	case stderrors.Is(err, context.Canceled):
+		return errors.KindCanceled, fields
+	case stderrors.Is(err, context.DeadlineExceeded):
+		return errors.KindDeadlineExceeded, fields
+	}
+
+	for e, depth := err, 0; e != nil && depth < errors.MaxChainDepth(); e, depth = next(e), depth+1 {
+		if state, ok := sqlState(e); ok {
+			fields[StateField] = state
+
+			if c := stringField(e, "ConstraintName", "Constraint"); c != "" {
+				fields[ConstraintField] = c
+			}
+
+			return StateKind(state), fields
+		}
+
+		if errno, state, ok := mysqlError(e); ok {
+			fields[ErrnoField] = errno
+			if state != "" {
+				fields[StateField] = state
+			}
+
+			if kind := ErrnoKind(errno); kind != errors.KindUnknown {
+				return kind, fields
+			}
+
+			return StateKind(state), fields
+		}
+	}
+
+	return errors.KindUnknown, fields
+}
+
+// State returns the SQLSTATE reported by the driver error in the chain of err.
+// If there is none, an empty string will be returned.
+func State(err error) string {
+	_, fields := Classify(err)
+	state, _ := fields[StateField].(string)
+
+	return state
+}
+
+// StateKind returns the kind matching the SQLSTATE code state.
+func StateKind(state string) errors.Kind {
+	switch state {
+	case StateUniqueViolation:
+		return errors.KindAlreadyExists
+	case StateForeignKeyViolation:
+		return errors.KindFailedPrecondition
+	case StateNotNullViolation, StateCheckViolation:
+		return errors.KindInvalidArgument
+	case StateSerializationFailure, StateDeadlockDetected:
+		return errors.KindAborted
+	case StateQueryCanceled:
+		return errors.KindCanceled
+	case StateInsufficientPrivs:
+		return errors.KindPermissionDenied
+	}
+
+	switch {
+	case strings.HasPrefix(state, ClassConnectionException):
+		return errors.KindUnavailable
+	case strings.HasPrefix(state, ClassDataException):
+		return errors.KindInvalidArgument
+	case strings.HasPrefix(state, ClassInvalidAuthorization):
+		return errors.KindUnauthenticated
+	case strings.HasPrefix(state, ClassInsufficientResource):
+		return errors.KindResourceExhausted
+	}
+
+	return errors.KindUnknown
+}
+
+// ErrnoKind returns the kind matching the MySQL error number errno.
+func ErrnoKind(errno int) errors.Kind {
+	switch errno {
+	case ErrnoDuplicateEntry:
+		return errors.KindAlreadyExists
+	case ErrnoRowIsReferenced, ErrnoNoReferencedRow:
+		return errors.KindFailedPrecondition
+	case ErrnoBadNull, ErrnoCheckConstraint:
+		return errors.KindInvalidArgument
+	case ErrnoLockWaitTimeout, ErrnoLockDeadlock:
+		return errors.KindAborted
+	case ErrnoAccessDenied:
+		return errors.KindUnauthenticated
+	case ErrnoTableAccessDenied:
+		return errors.KindPermissionDenied
+	}
+
+	return errors.KindUnknown
+}
+
+// next returns the error wrapped by err.
+func next(err error) error {
+	switch v := err.(type) {
+	case interface{ Cause() error }:
+		return v.Cause()
+	case interface{ Unwrap() error }:
+		return v.Unwrap()
+	}
+
+	return nil
+}
+
+// sqlState returns the SQLSTATE of the lib/pq and pgx errors.
+func sqlState(err error) (string, bool) {
+	if s, ok := err.(interface{ SQLState() string }); ok {
+		return s.SQLState(), true
+	}
+
+	return "", false
+}
+
+// mysqlError returns the error number and SQLSTATE of the errors shaped like
+// mysql.MySQLError.
+func mysqlError(err error) (int, string, bool) {
+	v := structValue(err)
+	if !v.IsValid() {
+		return 0, "", false
+	}
+
+	number := v.FieldByName("Number")
+	if !number.IsValid() || number.Kind() != reflect.Uint16 {
+		return 0, "", false
+	}
+
+	var state string
+
+	if s := v.FieldByName("SQLState"); s.IsValid() && s.Kind() == reflect.Array && s.Type().Elem().Kind() == reflect.Uint8 {
+		b := make([]byte, s.Len())
+		for i := range b {
+			b[i] = byte(s.Index(i).Uint())
+		}
+
+		state = strings.TrimRight(string(b), "\x00")
+	}
+
+	return int(number.Uint()), state, true
+}
+
+// stringField returns the first non-empty string field of err among names.
+func stringField(err error, names ...string) string {
+	v := structValue(err)
+	if !v.IsValid() {
+		return ""
+	}
+
+	for _, name := range names {
+		if f := v.FieldByName(name); f.IsValid() && f.Kind() == reflect.String && f.String() != "" {
+			return f.String()
+		}
+	}
+
+	return ""
+}
+
+// structValue returns the structure err points to, if any.
+func structValue(err error) reflect.Value {
+	v := reflect.ValueOf(err)
+	if v.Kind() == reflect.Ptr {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return reflect.Value{}
+	}
+
+	return v
+}
@@ -0,0 +1,152 @@
+package sqlx
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	stderrors "errors"
+	"fmt"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+// pqError is shaped like pq.Error.
+type pqError struct {
+	Code       string
+	Message    string
+	Constraint string
+}
+
+func (e *pqError) Error() string    { return "pq: " + e.Message }
+func (e *pqError) SQLState() string { return e.Code }
+
+// pgError is shaped like pgconn.PgError.
+type pgError struct {
+	Code           string
+	Message        string
+	ConstraintName string
+}
+
+func (e *pgError) Error() string    { return "ERROR: " + e.Message + " (SQLSTATE " + e.Code + ")" }
+func (e *pgError) SQLState() string { return e.Code }
+
+// mysqlErr is shaped like mysql.MySQLError.
+type mysqlErr struct {
+	Number   uint16
+	SQLState [5]byte
+	Message  string
+}
+
+func (e *mysqlErr) Error() string { return fmt.Sprintf("Error %d: %s", e.Number, e.Message) }
+
+func TestTranslateNil(t *testing.T) {
+	assert.Nil(t, Translate(nil))
+}
+
+func TestTranslate(t *testing.T) {
+	tests := []struct {
+		name   string
+		err    error
+		kind   errors.Kind
+		fields errors.Fields
+	}{
+		{"no rows", sql.ErrNoRows, errors.KindNotFound, errors.Fields{}},
+		{"wrapped no rows", fmt.Errorf("scan: %w", sql.ErrNoRows), errors.KindNotFound, errors.Fields{}},
+		{"tx done", sql.ErrTxDone, errors.KindFailedPrecondition, errors.Fields{}},
+		{"bad conn", driver.ErrBadConn, errors.KindUnavailable, errors.Fields{}},
+		{"canceled", context.Canceled, errors.KindCanceled, errors.Fields{}},
+		{
+			"pq unique",
+			&pqError{Code: "23505", Message: "duplicate key", Constraint: "users_email_key"},
+			errors.KindAlreadyExists,
+			errors.Fields{StateField: "23505", ConstraintField: "users_email_key"},
+		},
+		{
+			"pgx foreign key",
+			errors.Wrap(&pgError{Code: "23503", Message: "violates foreign key", ConstraintName: "orders_user_fk"}, "insert"),
+			errors.KindFailedPrecondition,
+			errors.Fields{StateField: "23503", ConstraintField: "orders_user_fk"},
+		},
+		{
+			"pgx serialization",
+			fmt.Errorf("commit: %w", &pgError{Code: "40001", Message: "could not serialize"}),
+			errors.KindAborted,
+			errors.Fields{StateField: "40001"},
+		},
+		{
+			"pq connection",
+			&pqError{Code: "08006", Message: "connection failure"},
+			errors.KindUnavailable,
+			errors.Fields{StateField: "08006"},
+		},
+		{
+			"pq unknown state",
+			&pqError{Code: "42P01", Message: "relation does not exist"},
+			errors.KindUnknown,
+			errors.Fields{StateField: "42P01"},
+		},
+		{
+			"mysql duplicate",
+			&mysqlErr{Number: 1062, SQLState: [5]byte{'2', '3', '0', '0', '0'}, Message: "Duplicate entry"},
+			errors.KindAlreadyExists,
+			errors.Fields{ErrnoField: 1062, StateField: "23000"},
+		},
+		{
+			"mysql deadlock",
+			&mysqlErr{Number: 1213, SQLState: [5]byte{'4', '0', '0', '0', '1'}, Message: "Deadlock found"},
+			errors.KindAborted,
+			errors.Fields{ErrnoField: 1213, StateField: "40001"},
+		},
+		{
+			"mysql by state",
+			&mysqlErr{Number: 1292, SQLState: [5]byte{'2', '2', '0', '0', '7'}, Message: "Incorrect datetime value"},
+			errors.KindInvalidArgument,
+			errors.Fields{ErrnoField: 1292, StateField: "22007"},
+		},
+		{"unknown", stderrors.New("boom"), errors.KindUnknown, errors.Fields{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Translate(tt.err)
+
+			assert.Equal(t, tt.err.Error(), err.Error())
+			assert.Equal(t, tt.kind, errors.GetKind(err))
+			assert.Equal(t, tt.fields, errors.GetFields(err))
+			assert.True(t, stderrors.Is(err, tt.err))
+		})
+	}
+}
+
+func TestState(t *testing.T) {
+	assert.Equal(t, "23505", State(errors.Wrap(&pqError{Code: "23505"}, "insert")))
+	assert.Equal(t, "", State(sql.ErrNoRows))
+}
+
+func TestStateKind(t *testing.T) {
+	tests := []struct {
+		state string
+		want  errors.Kind
+	}{
+		{StateNotNullViolation, errors.KindInvalidArgument},
+		{StateDeadlockDetected, errors.KindAborted},
+		{StateQueryCanceled, errors.KindCanceled},
+		{StateInsufficientPrivs, errors.KindPermissionDenied},
+		{"28P01", errors.KindUnauthenticated},
+		{"53100", errors.KindResourceExhausted},
+		{"", errors.KindUnknown},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, StateKind(tt.state), tt.state)
+	}
+}
+
+func TestErrnoKind(t *testing.T) {
+	assert.Equal(t, errors.KindFailedPrecondition, ErrnoKind(ErrnoNoReferencedRow))
+	assert.Equal(t, errors.KindUnauthenticated, ErrnoKind(ErrnoAccessDenied))
+	assert.Equal(t, errors.KindPermissionDenied, ErrnoKind(ErrnoTableAccessDenied))
+	assert.Equal(t, errors.KindUnknown, ErrnoKind(1))
+}
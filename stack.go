@@ -2,43 +2,145 @@ package errors
 
 import (
 	"fmt"
+	"io"
 	"runtime"
+	"sync/atomic"
 
 	"github.com/pkg/errors"
 )
 
-// stack represents a stack of program counters.
-type stack []uintptr
+//nolint:gochecknoglobals // incremented by the setters of the formatting options.
+var formatGeneration uint64
 
-func (s *stack) Format(st fmt.State, verb rune) {
-	if verb == 'v' && st.Flag('+') {
-		for _, pc := range *s {
-			f := errors.Frame(pc)
-			_, _ = fmt.Fprintf(st, "\n%+v", f)
+// formatOptionsChanged discards the renderings of the stacks made with the
+// previous formatting options.
+func formatOptionsChanged() {
+	atomic.AddUint64(&formatGeneration, 1)
+}
+
+// formatFrame prints f on its own lines, unless it is hidden by the stack
+// filter.
+func formatFrame(w io.Writer, f Frame) {
+	if !showFrame(f) {
+		return
+	}
+
+	formatLocation(w, f.Function(), f.File(), f.Line())
+	formatSource(w, f)
+}
+
+// formatLocation prints the function and the location of a frame on their own
+// lines, or as a single line starting with the location in clickable mode.
+func formatLocation(w io.Writer, function, file string, line int) {
+	file = displayPath(function, file)
+
+	if executeTemplate(w, getTemplates().Frame, FrameData{Function: function, File: file, Line: line}) {
+		return
+	}
+
+	if clickable() {
+		_, _ = fmt.Fprintf(w, "\n%s:%d: %s", file, line, function)
+
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "\n%s\n\t%s:%d", function, file, line)
+}
+
+type stackTracer interface {
+	StackTrace() errors.StackTrace
+}
+
+// hasStack reports whether any layer of the chain carries a stack trace.
+func hasStack(err error) bool {
+	for depth := 0; err != nil && depth < MaxChainDepth(); depth++ {
+		if _, ok := err.(stackTracer); ok {
+			return true
+		}
+
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+
+		err = cause.Cause()
+	}
+
+	return false
+}
+
+// deepestStackBelow returns the first stack trace found in the chain of err,
+// err included.
+func deepestStackBelow(err error) errors.StackTrace {
+	for depth := 0; err != nil && depth < MaxChainDepth(); depth++ {
+		if t, ok := err.(stackTracer); ok {
+			return t.StackTrace()
+		}
+
+		cause, ok := err.(causer)
+		if !ok {
+			break
 		}
+
+		err = cause.Cause()
 	}
+
+	return nil
 }
 
-func (s *stack) StackTrace() errors.StackTrace {
-	f := make([]errors.Frame, len(*s))
-	for i := 0; i < len(f); i++ {
-		f[i] = errors.Frame((*s)[i])
+// deepestStack returns the stack trace recorded closest to the origin of the chain.
+func deepestStack(err error) errors.StackTrace {
+	var st errors.StackTrace
+
+	for depth := 0; err != nil && depth < MaxChainDepth(); depth++ {
+		if t, ok := err.(stackTracer); ok {
+			st = t.StackTrace()
+		}
+
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+
+		err = cause.Cause()
 	}
 
-	return f
+	return st
+}
+
+// StackFrame is a resolved stack frame.
+type StackFrame struct {
+	Function string `json:"function" yaml:"function"`
+	File     string `json:"file" yaml:"file"`
+	Line     int    `json:"line" yaml:"line"`
 }
 
-func callers() *stack {
-	const (
-		skipCallers = 3
-		depth       = 32
-	)
+// resolve converts st into resolved frames.
+func resolve(st errors.StackTrace) []StackFrame {
+	if len(st) == 0 {
+		return nil
+	}
+
+	pcs := make([]uintptr, len(st))
+	for i, f := range st {
+		pcs[i] = uintptr(f)
+	}
 
-	var pcs [depth]uintptr
+	out := make([]StackFrame, 0, len(pcs))
+	frames := runtime.CallersFrames(pcs)
 
-	n := runtime.Callers(skipCallers, pcs[:])
+	for {
+		f, more := frames.Next()
+		out = append(out, StackFrame{
+			Function: f.Function,
+			File:     f.File,
+			Line:     f.Line,
+		})
 
-	var st stack = pcs[0:n]
+		if !more {
+			break
+		}
+	}
 
-	return &st
+	return out
 }
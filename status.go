@@ -0,0 +1,139 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+)
+
+// HTTPStatus returns the HTTP status code describing err.
+// The outermost status set with WithHTTPStatus is used first; otherwise the
+// status is derived from the kind of the error, and defaults to
+// http.StatusInternalServerError.
+// If err is nil, http.StatusOK will be returned.
+func HTTPStatus(err error) int {
+	type statuser interface {
+		HTTPStatus() int
+	}
+
+	if err == nil {
+		return http.StatusOK
+	}
+
+	for e, depth := err, 0; e != nil && depth < MaxChainDepth(); depth++ {
+		if s, ok := e.(statuser); ok && s.HTTPStatus() != 0 {
+			return s.HTTPStatus()
+		}
+
+		cause, ok := e.(causer)
+		if !ok {
+			break
+		}
+
+		e = cause.Cause()
+	}
+
+	return KindHTTPStatus(GetKind(err))
+}
+
+// KindHTTPStatus returns the HTTP status code conventionally associated with k.
+func KindHTTPStatus(k Kind) int {
+	switch k {
+	case KindCanceled:
+		return statusClientClosedRequest
+	case KindInvalidArgument, KindOutOfRange:
+		return http.StatusBadRequest
+	case KindDeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case KindNotFound:
+		return http.StatusNotFound
+	case KindAlreadyExists, KindAborted:
+		return http.StatusConflict
+	case KindPermissionDenied:
+		return http.StatusForbidden
+	case KindUnauthenticated:
+		return http.StatusUnauthorized
+	case KindResourceExhausted:
+		return http.StatusTooManyRequests
+	case KindFailedPrecondition:
+		return http.StatusPreconditionFailed
+	case KindUnimplemented:
+		return http.StatusNotImplemented
+	case KindUnavailable:
+		return http.StatusServiceUnavailable
+	case KindUnknown, KindInternal, KindDataLoss:
+		return http.StatusInternalServerError
+	}
+
+	return http.StatusInternalServerError
+}
+
+// statusClientClosedRequest is the non standard status used by nginx when
+// the client closes the connection before the response is sent.
+const statusClientClosedRequest = 499
+
+// /////////////////////////////////////////////////////////////////////////////
+
+type withHTTPStatus struct {
+	cause  error
+	status int
+}
+
+// WithHTTPStatus annotates err with the HTTP status code to respond with,
+// overriding the status derived from its kind.
+// If err is nil, WithHTTPStatus returns nil.
+func WithHTTPStatus(err error, status int) error {
+	if err == nil {
+		return nil
+	}
+
+	return runHooks(&withHTTPStatus{
+		cause:  err,
+		status: status,
+	}, err)
+}
+
+func (w *withHTTPStatus) Error() string {
+	return w.cause.Error()
+}
+
+func (w *withHTTPStatus) Cause() error {
+	return w.cause
+}
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withHTTPStatus) Unwrap() error {
+	return w.cause
+}
+
+func (w *withHTTPStatus) HTTPStatus() int {
+	return w.status
+}
+
+func (w *withHTTPStatus) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			formatGoString(s, w)
+
+			return
+		}
+
+		if s.Flag('+') {
+			if customFormat(s, w) {
+				return
+			}
+
+			formatCause(s, w.Cause())
+			_, _ = io.WriteString(s, "\n")
+			formatAnnotation(s, "status", strconv.Itoa(w.status))
+
+			return
+		}
+
+		fallthrough
+	case 's', 'q':
+		_, _ = io.WriteString(s, w.Error())
+	}
+}
@@ -0,0 +1,65 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithHTTPStatusNil(t *testing.T) {
+	assert.Nil(t, WithHTTPStatus(nil, http.StatusTeapot))
+}
+
+func TestHTTPStatus(t *testing.T) {
+	tests := []struct {
+		err  error
+		want int
+	}{
+		{nil, http.StatusOK},
+		{io.EOF, http.StatusInternalServerError},
+		{WithKind(io.EOF, KindNotFound), http.StatusNotFound},
+		{Wrap(WithKind(io.EOF, KindUnauthenticated), "login"), http.StatusUnauthorized},
+		{WithHTTPStatus(io.EOF, http.StatusTeapot), http.StatusTeapot},
+		{WithKind(WithHTTPStatus(io.EOF, http.StatusTeapot), KindNotFound), http.StatusTeapot},
+		{WithHTTPStatus(WithHTTPStatus(io.EOF, http.StatusTeapot), http.StatusGone), http.StatusGone},
+		{WithHTTPStatus(WithKind(io.EOF, KindAborted), 0), http.StatusConflict},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, HTTPStatus(tt.err))
+	}
+}
+
+func TestKindHTTPStatus(t *testing.T) {
+	tests := []struct {
+		kind Kind
+		want int
+	}{
+		{KindUnknown, http.StatusInternalServerError},
+		{KindCanceled, 499},
+		{KindInvalidArgument, http.StatusBadRequest},
+		{KindDeadlineExceeded, http.StatusGatewayTimeout},
+		{KindPermissionDenied, http.StatusForbidden},
+		{KindResourceExhausted, http.StatusTooManyRequests},
+		{KindFailedPrecondition, http.StatusPreconditionFailed},
+		{KindUnimplemented, http.StatusNotImplemented},
+		{KindUnavailable, http.StatusServiceUnavailable},
+		{Kind("custom"), http.StatusInternalServerError},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, KindHTTPStatus(tt.kind), tt.kind)
+	}
+}
+
+func TestHTTPStatusFormat(t *testing.T) {
+	err := WithHTTPStatus(io.EOF, http.StatusTeapot)
+
+	assert.Equal(t, "EOF", err.Error())
+	assert.Equal(t, "EOF", fmt.Sprintf("%v", err))
+	assert.Equal(t, "EOF\n  status: 418\n", fmt.Sprintf("%+v", err))
+	assert.Equal(t, []error{err}, Unpack(err))
+}
@@ -0,0 +1,88 @@
+package errors
+
+import "sync"
+
+// TaskField is the field annotating the errors of the tasks of a TaskGroup
+// with the index of the task, in the order the tasks were started.
+const TaskField = "task"
+
+// TaskGroup runs tasks concurrently like the Group of
+// golang.org/x/sync/errgroup, but collects the errors of every task instead
+// of only the first one:
+//
+//     var g errors.TaskGroup
+//
+//     g.SetLimit(4)
+//
+//     for _, url := range urls {
+//            url := url
+//
+//            g.Go(func() error { return fetch(url) })
+//     }
+//
+//     return g.Wait()
+//
+// The panics of the tasks are recovered and reported as their errors.
+// The zero TaskGroup is ready to use, with no limit on the number of tasks
+// running at once.
+type TaskGroup struct {
+	wg    sync.WaitGroup
+	sem   chan struct{}
+	mu    sync.Mutex
+	tasks int
+	errs  Collector
+}
+
+// NewTaskGroup returns a TaskGroup with no limit on the number of tasks
+// running at once.
+func NewTaskGroup() *TaskGroup {
+	return &TaskGroup{}
+}
+
+// SetLimit limits the number of tasks running at once to n, Go blocking until
+// a task returns when the limit is reached.
+// If n is not positive, the number of tasks is not limited.
+// SetLimit must not be called while tasks are running.
+func (g *TaskGroup) SetLimit(n int) {
+	if n <= 0 {
+		g.sem = nil
+
+		return
+	}
+
+	g.sem = make(chan struct{}, n)
+}
+
+// Go runs f in a new goroutine, annotating its error, or the one describing
+// its panic, with the index of the task as TaskField.
+func (g *TaskGroup) Go(f func() error) {
+	if g.sem != nil {
+		g.sem <- struct{}{}
+	}
+
+	g.mu.Lock()
+	task := g.tasks
+	g.tasks++
+	g.mu.Unlock()
+
+	g.wg.Add(1)
+
+	go func() {
+		defer g.wg.Done()
+
+		if g.sem != nil {
+			defer func() { <-g.sem }()
+		}
+
+		g.errs.AddWithFields(runTask(f), Fields{TaskField: task})
+	}()
+}
+
+// Wait waits for all the tasks started with Go to return, and returns a
+// MultiError holding their errors, in the order they were reported.
+// If no task failed, Wait returns nil.
+func (g *TaskGroup) Wait() error {
+	g.wg.Wait()
+
+	return g.errs.Err()
+}
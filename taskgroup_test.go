@@ -0,0 +1,77 @@
+package errors
+
+import (
+	"io"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTaskGroup(t *testing.T) {
+	var g TaskGroup
+
+	g.Go(func() error { return nil })
+	g.Go(func() error { return io.EOF })
+	g.Go(func() error { panic("boom") })
+
+	err := g.Wait()
+
+	m, ok := err.(*MultiError)
+	if !assert.True(t, ok) {
+		return
+	}
+
+	assert.Len(t, m.Errors(), 2)
+
+	byTask := make(map[interface{}]error)
+	for _, e := range m.Errors() {
+		byTask[GetFields(e)[TaskField]] = e
+	}
+
+	assert.Equal(t, io.EOF, Cause(byTask[1]))
+
+	if assert.NotNil(t, byTask[2]) {
+		assert.Equal(t, "panic: boom", byTask[2].Error())
+		assert.Equal(t, KindInternal, GetKind(byTask[2]))
+	}
+}
+
+func TestTaskGroupNoError(t *testing.T) {
+	g := NewTaskGroup()
+
+	g.Go(func() error { return nil })
+
+	assert.Nil(t, g.Wait())
+	assert.Nil(t, NewTaskGroup().Wait())
+}
+
+func TestTaskGroupLimit(t *testing.T) {
+	var (
+		g             TaskGroup
+		running, peak int32
+	)
+
+	const limit = 2
+
+	g.SetLimit(limit)
+
+	for i := 0; i < 10; i++ {
+		g.Go(func() error {
+			n := atomic.AddInt32(&running, 1)
+			defer atomic.AddInt32(&running, -1)
+
+			for {
+				p := atomic.LoadInt32(&peak)
+				if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+					break
+				}
+			}
+
+			return nil
+		})
+	}
+
+	assert.Nil(t, g.Wait())
+	assert.LessOrEqual(t, atomic.LoadInt32(&peak), int32(limit))
+}
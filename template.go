@@ -0,0 +1,103 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"sync/atomic"
+	"text/template"
+)
+
+// Templates customize how the parts of an error chain are rendered by the %+v
+// verb, so that every program of an organization can print errors the same
+// way. A nil template keeps the default rendering of its part.
+// When a template fails to execute, the part is rendered the default way.
+type Templates struct {
+	// Message renders the message of a layer, given a MessageData.
+	// The default is `{{.Message}}`.
+	Message *template.Template
+	// Annotation renders a field, kind, code, HTTP status or user message of
+	// a layer, given an AnnotationData. The default, ending with a newline, is
+	// `  {{.Key}}: {{.Value}}` followed by a newline.
+	Annotation *template.Template
+	// Frame renders a frame of a stack trace, given a FrameData. The default,
+	// starting with a newline, is a newline followed by
+	// `{{.Function}}`, a newline, a tab and `{{.File}}:{{.Line}}`.
+	Frame *template.Template
+}
+
+// MessageData is the data of the Message template.
+type MessageData struct {
+	Message string
+}
+
+// AnnotationData is the data of the Annotation template.
+type AnnotationData struct {
+	// Key is the key of the field, or "kind", "code", "status" and
+	// "user message" for the other annotations.
+	Key string
+	// Value is the formatted value, capped by the limits set by
+	// SetFieldLimits.
+	Value string
+}
+
+// FrameData is the data of the Frame template.
+type FrameData struct {
+	// Function is the fully qualified name of the function.
+	Function string
+	// File is the path of the file, shortened by SetPathPrefix or
+	// RelativePaths if enabled.
+	File string
+	Line int
+}
+
+//nolint:gochecknoglobals // the templates are configured process-wide.
+var templates atomic.Value // Templates
+
+// SetTemplates sets the templates used by the %+v verb.
+// The default rendering is restored by setting the zero Templates.
+func SetTemplates(t Templates) {
+	templates.Store(t)
+	formatOptionsChanged()
+}
+
+func getTemplates() Templates {
+	t, _ := templates.Load().(Templates)
+
+	return t
+}
+
+// formatMessage prints the message of a layer.
+func formatMessage(w io.Writer, msg string) {
+	if executeTemplate(w, getTemplates().Message, MessageData{Message: msg}) {
+		return
+	}
+
+	_, _ = io.WriteString(w, msg)
+}
+
+// formatAnnotation prints an annotation of a layer on its own line.
+func formatAnnotation(w io.Writer, key, value string) {
+	if executeTemplate(w, getTemplates().Annotation, AnnotationData{Key: key, Value: value}) {
+		return
+	}
+
+	_, _ = fmt.Fprintf(w, "  %s: %s\n", key, value)
+}
+
+// executeTemplate writes the output of t executed with data to w and reports
+// whether it succeeded.
+func executeTemplate(w io.Writer, t *template.Template, data interface{}) bool {
+	if t == nil {
+		return false
+	}
+
+	var b bytes.Buffer
+	if err := t.Execute(&b, data); err != nil {
+		return false
+	}
+
+	_, _ = w.Write(b.Bytes())
+
+	return true
+}
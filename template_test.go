@@ -0,0 +1,45 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+	"text/template"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetTemplates(t *testing.T) {
+	defer SetTemplates(Templates{})
+
+	err := WithKind(WithFields(Wrap(io.EOF, "reading"), Fields{"file": "a.txt"}), KindNotFound)
+
+	SetTemplates(Templates{
+		Message:    template.Must(template.New("message").Parse("[{{.Message}}]")),
+		Annotation: template.Must(template.New("annotation").Parse("  {{.Key}}={{.Value}}\n")),
+		Frame:      template.Must(template.New("frame").Parse("\n  at {{.Function}} ({{.Line}})")),
+	})
+
+	got := fmt.Sprintf("%+v", err)
+	assert.Contains(t, got, "EOF\n[reading]\n")
+
+	if stacksCompiled {
+		assert.Contains(t, got, "\n[reading]\n  at github.com/hexbee-net/errors.TestSetTemplates (")
+	}
+
+	assert.Contains(t, got, "\n  file=a.txt\n")
+	assert.Contains(t, got, "\n  kind=not_found\n")
+
+	SetTemplates(Templates{})
+	assert.Contains(t, fmt.Sprintf("%+v", err), "\n  kind: not_found\n")
+}
+
+func TestSetTemplatesFailure(t *testing.T) {
+	defer SetTemplates(Templates{})
+
+	SetTemplates(Templates{
+		Message: template.Must(template.New("message").Parse("{{.Missing}}")),
+	})
+
+	assert.Equal(t, "boom", fmt.Sprintf("%+v", WithMessage(io.EOF, "boom"))[len("EOF\n"):])
+}
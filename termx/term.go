@@ -0,0 +1,131 @@
+// Package termx prints error chains for people reading them in a terminal,
+// such as the users of command-line tools:
+//
+//     if err := run(); err != nil {
+//         _ = termx.Fprint(os.Stderr, err)
+//         os.Exit(1)
+//     }
+//
+// Messages, fields and frames are colorized with ANSI escape sequences when
+// the output is a terminal, unless the NO_COLOR environment variable is set
+// (https://no-color.org) or TERM is "dumb".
+package termx
+
+import (
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/hexbee-net/errors"
+)
+
+// ANSI escape sequences.
+const (
+	reset = "\x1b[0m"
+	bold  = "\x1b[1m"
+	dim   = "\x1b[2m"
+	red   = "\x1b[31m"
+	cyan  = "\x1b[36m"
+)
+
+// Printer prints error chains, one layer per line followed by its fields,
+// then the frames of the stack trace recorded closest to the origin of the
+// chain, filtered like by the %+v verb.
+type Printer struct {
+	// Color enables the ANSI escape sequences.
+	Color bool
+	// HideStack leaves the stack trace out.
+	HideStack bool
+}
+
+// NewPrinter returns a Printer colorizing its output when ColorEnabled for w.
+func NewPrinter(w io.Writer) Printer {
+	return Printer{Color: ColorEnabled(w)}
+}
+
+// Fprint prints err to w with the Printer returned by NewPrinter.
+func Fprint(w io.Writer, err error) error {
+	return NewPrinter(w).Fprint(w, err)
+}
+
+// ColorEnabled reports whether w is a terminal accepting colors: a character
+// device while the NO_COLOR environment variable is empty and TERM is not
+// "dumb".
+func ColorEnabled(w io.Writer) bool {
+	if os.Getenv("NO_COLOR") != "" || os.Getenv("TERM") == "dumb" {
+		return false
+	}
+
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+
+	info, err := f.Stat()
+
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// Fprint prints err to w.
+// If err is nil, nothing is printed.
+func (p Printer) Fprint(w io.Writer, err error) error {
+	if err == nil {
+		return nil
+	}
+
+	var b strings.Builder
+
+	for i, layer := range errors.GetFieldsByLayer(err) {
+		if i == 0 {
+			p.write(&b, bold+red, layer.Message)
+		} else {
+			b.WriteString("caused by: ")
+			p.write(&b, red, layer.Message)
+		}
+
+		b.WriteByte('\n')
+
+		keys := make([]string, 0, len(layer.Fields))
+		for k := range layer.Fields {
+			keys = append(keys, k)
+		}
+
+		sort.Strings(keys)
+
+		for _, k := range keys {
+			b.WriteString("    ")
+			p.write(&b, dim, k+"="+errors.FormatField(k, layer.Fields[k]))
+			b.WriteByte('\n')
+		}
+	}
+
+	if st, ok := errors.StackOf(err); ok && !p.HideStack {
+		for _, f := range st.Visible() {
+			b.WriteString("  at ")
+			p.write(&b, bold+cyan, f.Function())
+			b.WriteString("\n     ")
+			p.write(&b, dim, f.File()+":"+strconv.Itoa(f.Line()))
+			b.WriteByte('\n')
+		}
+	}
+
+	_, werr := io.WriteString(w, b.String())
+
+	return werr
+}
+
+// write writes s to b, wrapped in the escape sequences of style when p is
+// colorized.
+func (p Printer) write(b *strings.Builder, style, s string) {
+	if !p.Color {
+		b.WriteString(s)
+
+		return
+	}
+
+	b.WriteString(style)
+	b.WriteString(s)
+	b.WriteString(reset)
+}
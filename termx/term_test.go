@@ -0,0 +1,60 @@
+package termx
+
+import (
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/hexbee-net/errors/internal/nostack"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrinterFprint(t *testing.T) {
+	err := errors.WithFields(errors.Wrap(errors.WithFields(io.EOF, errors.Fields{"file": "a.txt"}), "loading"),
+		errors.Fields{"user": 42, "attempt": 2})
+
+	var b strings.Builder
+
+	assert.NoError(t, Printer{HideStack: true}.Fprint(&b, err))
+	assert.Equal(t, "loading\n    attempt=2\n    user=42\ncaused by: EOF\n    file=a.txt\n", b.String())
+
+	b.Reset()
+	assert.NoError(t, Printer{}.Fprint(&b, err))
+
+	if !nostack.Enabled {
+		assert.Contains(t, b.String(), "\n  at github.com/hexbee-net/errors/termx.TestPrinterFprint\n     ")
+	}
+
+	assert.NotContains(t, b.String(), "\x1b[")
+
+	b.Reset()
+	assert.NoError(t, Printer{Color: true}.Fprint(&b, err))
+	assert.True(t, strings.HasPrefix(b.String(), bold+red+"loading"+reset+"\n    "+dim+"attempt=2"+reset+"\n"))
+	assert.Contains(t, b.String(), "caused by: "+red+"EOF"+reset)
+
+	if !nostack.Enabled {
+		assert.Contains(t, b.String(), "  at "+bold+cyan+"github.com/hexbee-net/errors/termx.TestPrinterFprint"+reset)
+	}
+
+	b.Reset()
+	assert.NoError(t, Printer{}.Fprint(&b, nil))
+	assert.Empty(t, b.String())
+}
+
+func TestColorEnabled(t *testing.T) {
+	var b strings.Builder
+
+	assert.False(t, ColorEnabled(&b))
+
+	f, err := os.CreateTemp(t.TempDir(), "out")
+	if assert.NoError(t, err) {
+		defer f.Close()
+
+		assert.False(t, ColorEnabled(f))
+	}
+
+	t.Setenv("NO_COLOR", "1")
+	assert.False(t, ColorEnabled(os.Stdout))
+}
@@ -0,0 +1,66 @@
+package errors
+
+// appendText appends the message of err, without stack trace, to b.
+func appendText(b []byte, err error) ([]byte, error) {
+	return append(b, err.Error()...), nil
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (f *fundamental) MarshalText() ([]byte, error) { return appendText(nil, f) }
+
+// AppendText implements encoding.TextAppender.
+func (f *fundamental) AppendText(b []byte) ([]byte, error) { return appendText(b, f) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (w *withStack) MarshalText() ([]byte, error) { return appendText(nil, w) }
+
+// AppendText implements encoding.TextAppender.
+func (w *withStack) AppendText(b []byte) ([]byte, error) { return appendText(b, w) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (w *withMessage) MarshalText() ([]byte, error) { return appendText(nil, w) }
+
+// AppendText implements encoding.TextAppender.
+func (w *withMessage) AppendText(b []byte) ([]byte, error) { return appendText(b, w) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (w *withFields) MarshalText() ([]byte, error) { return appendText(nil, w) }
+
+// AppendText implements encoding.TextAppender.
+func (w *withFields) AppendText(b []byte) ([]byte, error) { return appendText(b, w) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (w *withFieldList) MarshalText() ([]byte, error) { return appendText(nil, w) }
+
+// AppendText implements encoding.TextAppender.
+func (w *withFieldList) AppendText(b []byte) ([]byte, error) { return appendText(b, w) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (w *withKind) MarshalText() ([]byte, error) { return appendText(nil, w) }
+
+// AppendText implements encoding.TextAppender.
+func (w *withKind) AppendText(b []byte) ([]byte, error) { return appendText(b, w) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (w *withCode) MarshalText() ([]byte, error) { return appendText(nil, w) }
+
+// AppendText implements encoding.TextAppender.
+func (w *withCode) AppendText(b []byte) ([]byte, error) { return appendText(b, w) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (w *withUserMessage) MarshalText() ([]byte, error) { return appendText(nil, w) }
+
+// AppendText implements encoding.TextAppender.
+func (w *withUserMessage) AppendText(b []byte) ([]byte, error) { return appendText(b, w) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (w *withHTTPStatus) MarshalText() ([]byte, error) { return appendText(nil, w) }
+
+// AppendText implements encoding.TextAppender.
+func (w *withHTTPStatus) AppendText(b []byte) ([]byte, error) { return appendText(b, w) }
+
+// MarshalText implements encoding.TextMarshaler.
+func (r *RemoteError) MarshalText() ([]byte, error) { return appendText(nil, r) }
+
+// AppendText implements encoding.TextAppender.
+func (r *RemoteError) AppendText(b []byte) ([]byte, error) { return appendText(b, r) }
@@ -0,0 +1,54 @@
+package errors
+
+import (
+	"encoding"
+	"encoding/json"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMarshalText(t *testing.T) {
+	errs := []error{
+		New("boom"),
+		WithStack(io.EOF),
+		Wrap(io.EOF, "read error"),
+		WithField(WithMessage(io.EOF, "read error"), "key", "value"),
+		WithKind(io.EOF, KindInternal),
+		WithCode(io.EOF, "code"),
+		WithUserMessage(io.EOF, "message"),
+		WithHTTPStatus(io.EOF, http.StatusTeapot),
+		FromDocument(Document{Layers: []Layer{{Message: "remote"}}}),
+	}
+
+	type textAppender interface {
+		AppendText(b []byte) ([]byte, error)
+	}
+
+	for _, err := range errs {
+		m, ok := err.(encoding.TextMarshaler)
+		assert.True(t, ok)
+
+		text, e := m.MarshalText()
+		assert.NoError(t, e)
+		assert.Equal(t, err.Error(), string(text))
+
+		a, ok := err.(textAppender)
+		assert.True(t, ok)
+
+		text, e = a.AppendText([]byte("error="))
+		assert.NoError(t, e)
+		assert.Equal(t, "error="+err.Error(), string(text))
+	}
+}
+
+func TestMarshalTextMapKey(t *testing.T) {
+	b, err := json.Marshal(map[*withMessage]int{
+		WithMessage(io.EOF, "read error").(*withMessage): 1,
+	})
+
+	assert.NoError(t, err)
+	assert.Equal(t, `{"read error: EOF":1}`, string(b))
+}
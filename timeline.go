@@ -0,0 +1,90 @@
+package errors
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+//nolint:gochecknoglobals // the recording of times is enabled process-wide.
+var recordTimes int32
+
+// RecordTimes enables or disables the recording of the wall time at which
+// every error with a stack trace is created or wrapped, such as by New, Wrap
+// or WithStack, for Timeline to show how long a failure took to travel up
+// the layers of a program.
+// Times are not recorded by default, and never with the nostack build tag,
+// which compiles out the stacks holding them.
+func RecordTimes(enabled bool) {
+	var v int32
+	if enabled {
+		v = 1
+	}
+
+	atomic.StoreInt32(&recordTimes, v)
+}
+
+func recordingTimes() bool {
+	return atomic.LoadInt32(&recordTimes) == 1
+}
+
+// WithTime annotates err with a stack trace and the wall time at the point
+// WithTime is called, whether RecordTimes is enabled or not, unless built
+// with the nostack build tag.
+// If err is nil, WithTime returns nil.
+func WithTime(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return withStackOn(err, err, callers().timed())
+}
+
+// TimelineEntry is the wall time at which a layer of an error chain was
+// created, along with the message of the layer.
+type TimelineEntry struct {
+	Time    time.Time `json:"time" yaml:"time"`
+	Message string    `json:"message" yaml:"message"`
+}
+
+// Timeline returns the times recorded along the chain of err, from the origin
+// of the error to its outermost layer.
+// Layers are split like in NewDocument, and a layer appears when a time was
+// recorded by one of its errors, the innermost time winning.
+// If no time was recorded, an empty slice will be returned.
+func Timeline(err error) []TimelineEntry {
+	type timer interface {
+		Time() time.Time
+	}
+
+	entries := make([]TimelineEntry, 0)
+
+	var at time.Time
+
+	for depth := 0; err != nil && depth < MaxChainDepth(); depth++ {
+		if t, ok := err.(timer); ok && !t.Time().IsZero() {
+			at = t.Time()
+		}
+
+		if msg, ok := ownMessage(err); ok {
+			if !at.IsZero() {
+				entries = append(entries, TimelineEntry{Time: at, Message: msg})
+			}
+
+			at = time.Time{}
+		}
+
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+
+		err = cause.Cause()
+	}
+
+	for i := len(entries)/2 - 1; i >= 0; i-- {
+		opp := len(entries) - 1 - i
+		entries[i], entries[opp] = entries[opp], entries[i]
+	}
+
+	return entries
+}
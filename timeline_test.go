@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"io"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func withRecordTimes(t *testing.T) {
+	t.Helper()
+
+	RecordTimes(true)
+	t.Cleanup(func() { RecordTimes(false) })
+}
+
+func messagesOf(entries []TimelineEntry) []string {
+	out := make([]string, len(entries))
+	for i, e := range entries {
+		out[i] = e.Message
+	}
+
+	return out
+}
+
+func TestTimeline(t *testing.T) {
+	requireStacks(t)
+
+	assert.Empty(t, Timeline(Wrap(New("boom"), "saving")))
+
+	withRecordTimes(t)
+
+	start := time.Now()
+	err := New("boom")
+	err = WithField(Wrap(err, "saving"), "key", "value")
+	err = WithMessage(err, "handling")
+	err = Wrapf(err, "serving %s", "request")
+
+	timeline := Timeline(err)
+
+	assert.Equal(t, []string{"boom", "saving", "serving request"}, messagesOf(timeline))
+
+	for i, e := range timeline {
+		assert.False(t, e.Time.Before(start), "entry %d", i+1)
+
+		if i > 0 {
+			assert.False(t, e.Time.Before(timeline[i-1].Time), "entry %d", i+1)
+		}
+	}
+}
+
+func TestWithTime(t *testing.T) {
+	requireStacks(t)
+
+	assert.Nil(t, WithTime(nil))
+
+	before := time.Now()
+	err := WithMessage(WithTime(io.EOF), "reading")
+
+	timeline := Timeline(err)
+	if assert.Len(t, timeline, 1) {
+		assert.Equal(t, "EOF", timeline[0].Message)
+		assert.False(t, timeline[0].Time.Before(before))
+	}
+
+	assert.Equal(t, "reading: EOF", err.Error())
+}
@@ -0,0 +1,74 @@
+package errors
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Branch markers of the trees of joined errors.
+const (
+	treeBranch     = "├─ "
+	treeLastBranch = "└─ "
+	treeIndent     = "│  "
+	treeLastIndent = "   "
+)
+
+// joined returns the errors joined by err, such as by errors.Join or by
+// fmt.Errorf with several %w verbs, if any.
+func joined(err error) []error {
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		return j.Unwrap()
+	}
+
+	return nil
+}
+
+// formatTree prints err, joining the errors members, with the %+v verb as an
+// indented tree with a branch per member, every member rendered with its
+// own fields and stack trace.
+func formatTree(w verboseState, err error, members []error) {
+	msgs := make([]string, 0, len(members))
+	branches := make([]error, 0, len(members))
+
+	for _, m := range members {
+		if m != nil {
+			msgs = append(msgs, m.Error())
+			branches = append(branches, m)
+		}
+	}
+
+	// Derive a title when the message only repeats the ones of the members,
+	// as with errors.Join.
+	if msg := err.Error(); msg != strings.Join(msgs, "\n") {
+		_, _ = io.WriteString(w, msg)
+	} else {
+		_, _ = fmt.Fprintf(w, "%d errors", len(branches))
+	}
+
+	formatBranches(w, branches)
+}
+
+// formatBranches prints the branches of a tree of errors, one per member.
+func formatBranches(w verboseState, branches []error) {
+	for i, m := range branches {
+		branch, indent := treeBranch, treeIndent
+		if i == len(branches)-1 {
+			branch, indent = treeLastBranch, treeLastIndent
+		}
+
+		var b bytes.Buffer
+
+		formatCause(verboseState{w: &b, depth: w.depth}, m)
+
+		for j, line := range strings.Split(strings.TrimRight(b.String(), "\n"), "\n") {
+			prefix := indent
+			if j == 0 {
+				prefix = branch
+			}
+
+			_, _ = io.WriteString(w, "\n"+prefix+line)
+		}
+	}
+}
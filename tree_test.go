@@ -0,0 +1,35 @@
+package errors
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFormatTree(t *testing.T) {
+	requireStacks(t)
+
+	err := WithMessage(errors.Join(
+		WithFields(WithStack(io.EOF), Fields{"file": "a.txt"}),
+		New("second"),
+	), "closing")
+
+	got := fmt.Sprintf("%+v", err)
+	lines := strings.Split(got, "\n")
+
+	assert.Equal(t, "2 errors", lines[0])
+	assert.Equal(t, "├─ EOF", lines[1])
+	assert.Equal(t, "│  github.com/hexbee-net/errors.TestFormatTree", lines[2])
+	assert.Contains(t, got, "\n│    file: a.txt\n└─ second\n   github.com/hexbee-net/errors.TestFormatTree\n")
+	assert.True(t, strings.HasSuffix(got, "\nclosing"), got)
+}
+
+func TestFormatTreeTitle(t *testing.T) {
+	err := WithMessage(fmt.Errorf("a: %w, b: %w", io.EOF, io.ErrUnexpectedEOF), "reading")
+
+	assert.Equal(t, "a: EOF, b: unexpected EOF\n├─ EOF\n└─ unexpected EOF\nreading", fmt.Sprintf("%+v", err))
+}
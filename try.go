@@ -0,0 +1,97 @@
+package errors
+
+// Scope collects the first error of a sequence of calls that can all fail the
+// same way, such as the reads of a decoder, so that they are not each
+// followed by an error check:
+//
+//     s := errors.Scope{Kind: errors.KindInvalidArgument, Message: "decoding header"}
+//
+//     version := errors.Try1(readUint16(r))(&s)
+//     name := errors.Try1(readString(r))(&s)
+//     size, flags := errors.Try2(readSize(r))(&s)
+//
+//     if err := s.Err(); err != nil {
+//            return Header{}, err
+//     }
+//
+// The calls are still made after a failure: they must only be chained while
+// calling them with invalid inputs is harmless, as with a reader that keeps
+// failing once it failed.
+// The zero Scope is ready to use, no kind or message being added.
+// A Scope is not safe for concurrent use.
+type Scope struct {
+	// Kind classifies the error of the scope, unless empty.
+	Kind Kind
+	// Message annotates the error of the scope, unless empty.
+	Message string
+
+	err     error
+	stack   stack
+	wrapped error
+}
+
+// Try1 returns a function recording err in the scope it is given, if it is
+// the first error of the scope, along with the stack trace at the point the
+// function is called, and returning v.
+// The function form lets the results of a call be passed as is:
+//
+//     version := errors.Try1(readUint16(r))(&s)
+func Try1[T any](v T, err error) func(*Scope) T {
+	return func(s *Scope) T {
+		if err != nil && s.err == nil {
+			s.err, s.stack = err, callers()
+		}
+
+		return v
+	}
+}
+
+// Try2 returns a function recording err like Try1 does, for the calls
+// returning two values and an error.
+func Try2[T, U any](v T, u U, err error) func(*Scope) (T, U) {
+	return func(s *Scope) (T, U) {
+		if err != nil && s.err == nil {
+			s.err, s.stack = err, callers()
+		}
+
+		return v, u
+	}
+}
+
+// Check records err in s like the function returned by Try1 does, for the
+// calls returning only an error, and reports whether s has not failed.
+func (s *Scope) Check(err error) bool {
+	if err != nil && s.err == nil {
+		s.err, s.stack = err, callers()
+	}
+
+	return s.err == nil
+}
+
+// Failed reports whether an error was recorded in s.
+func (s *Scope) Failed() bool {
+	return s.err != nil
+}
+
+// Err returns the first error recorded in s, annotated with the stack trace
+// of the call that failed, the message and the kind of s.
+// The error is built by the first call, the next ones returning the same
+// error.
+// If no error was recorded, Err returns nil.
+func (s *Scope) Err() error {
+	if s.err == nil || s.wrapped != nil {
+		return s.wrapped
+	}
+
+	if s.Message != "" {
+		s.wrapped = wrapStack(s.err, s.Message, s.stack)
+	} else {
+		s.wrapped = withStackOn(s.err, s.err, s.stack)
+	}
+
+	if s.Kind != "" {
+		s.wrapped = WithKind(s.wrapped, s.Kind)
+	}
+
+	return s.wrapped
+}
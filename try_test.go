@@ -0,0 +1,69 @@
+package errors
+
+import (
+	"io"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScope(t *testing.T) {
+	var s Scope
+
+	assert.Equal(t, 1, Try1(strconv.Atoi("1"))(&s))
+	assert.True(t, s.Check(nil))
+	assert.False(t, s.Failed())
+	assert.Nil(t, s.Err())
+
+	s = Scope{Kind: KindInvalidArgument, Message: "decoding header"}
+
+	version := Try1(strconv.Atoi("2"))(&s)
+	name := Try1("", io.ErrUnexpectedEOF)(&s)
+	size, flags := Try2(0, 0, io.EOF)(&s)
+
+	assert.Equal(t, 2, version)
+	assert.Equal(t, "", name)
+	assert.Equal(t, 0, size+flags)
+	assert.True(t, s.Failed())
+	assert.False(t, s.Check(nil))
+
+	err := s.Err()
+
+	assert.Same(t, err, s.Err())
+	assert.Equal(t, "decoding header: unexpected EOF", err.Error())
+	assert.Equal(t, io.ErrUnexpectedEOF, Cause(err))
+	assert.Equal(t, KindInvalidArgument, GetKind(err))
+
+	st, ok := StackOf(err)
+	if stacksCompiled && assert.True(t, ok) {
+		assert.Equal(t, "github.com/hexbee-net/errors.TestScope", st[0].Function())
+		assert.Equal(t, 23, st[0].Line())
+	}
+}
+
+func TestScopeWithoutMessage(t *testing.T) {
+	var s Scope
+
+	s.Check(io.EOF)
+
+	err := s.Err()
+	assert.Equal(t, "EOF", err.Error())
+	assert.Equal(t, KindUnknown, GetKind(err))
+}
+
+func TestScopeHooks(t *testing.T) {
+	var calls int32
+
+	withHooks(t, func(err error) error {
+		atomic.AddInt32(&calls, 1)
+		return nil
+	})
+
+	s := Scope{Kind: KindInvalidArgument}
+	s.Check(io.EOF)
+
+	assert.Same(t, s.Err(), s.Err())
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls), "the stack and the kind are hooked once")
+}
@@ -0,0 +1,19 @@
+module github.com/hexbee-net/errors/twirpx
+
+go 1.20
+
+require (
+	github.com/hexbee-net/errors v0.0.0
+	github.com/stretchr/testify v1.6.1
+	github.com/twitchtv/twirp v8.1.3+incompatible
+)
+
+require (
+	github.com/apex/log v1.9.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/pkg/errors v0.9.1 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+replace github.com/hexbee-net/errors => ../
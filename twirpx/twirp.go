@@ -0,0 +1,162 @@
+// Package twirpx converts error chains to and from Twirp errors, so that
+// Twirp services and clients keep the kind, code and fields of their errors.
+//
+// It lives in its own module so that the core package does not depend on
+// Twirp.
+package twirpx
+
+import (
+	"fmt"
+
+	"github.com/hexbee-net/errors"
+	"github.com/twitchtv/twirp"
+)
+
+// CodeMetaKey is the metadata key holding the code of the error, as opposed
+// to the Twirp error code derived from its kind.
+const CodeMetaKey = "error_code"
+
+// ToTwirp returns the Twirp error describing err.
+// The Twirp code is derived from the kind of the chain, the message is its
+// user message or, without one, its complete message, and the fields are
+// added as metadata along with the code.
+// The returned error wraps err.
+// If err is nil, ToTwirp returns nil.
+func ToTwirp(err error) twirp.Error {
+	if err == nil {
+		return nil
+	}
+
+	msg := errors.GetUserMessage(err)
+	if msg == "" {
+		msg = err.Error()
+	}
+
+	twerr := twirp.NewError(ErrorCode(errors.GetKind(err)), msg)
+
+	for k, v := range errors.GetFields(err) {
+		twerr = twerr.WithMeta(k, fmt.Sprint(v))
+	}
+
+	if code := errors.GetCode(err); code != "" {
+		twerr = twerr.WithMeta(CodeMetaKey, code)
+	}
+
+	return twirp.WrapError(twerr, err)
+}
+
+// FromTwirp rebuilds an error from twerr, typically received by a Twirp
+// client.
+// The returned error carries the kind matching the Twirp code, the message
+// of twerr as message and user message, the code stored in the metadata, and
+// the rest of the metadata as fields. It matches local errors with the same
+// code.
+// If twerr is nil, FromTwirp returns nil.
+func FromTwirp(twerr twirp.Error) error {
+	if twerr == nil {
+		return nil
+	}
+
+	l := errors.Layer{
+		Message:     twerr.Msg(),
+		Kind:        Kind(twerr.Code()),
+		UserMessage: twerr.Msg(),
+	}
+
+	for k, v := range twerr.MetaMap() {
+		if k == CodeMetaKey {
+			l.Code = v
+
+			continue
+		}
+
+		if l.Fields == nil {
+			l.Fields = make(errors.Fields)
+		}
+
+		l.Fields[k] = v
+	}
+
+	return errors.FromDocument(errors.Document{Layers: []errors.Layer{l}})
+}
+
+// ErrorCode returns the Twirp error code matching k.
+func ErrorCode(k errors.Kind) twirp.ErrorCode {
+	switch k {
+	case errors.KindCanceled:
+		return twirp.Canceled
+	case errors.KindInvalidArgument:
+		return twirp.InvalidArgument
+	case errors.KindDeadlineExceeded:
+		return twirp.DeadlineExceeded
+	case errors.KindNotFound:
+		return twirp.NotFound
+	case errors.KindAlreadyExists:
+		return twirp.AlreadyExists
+	case errors.KindPermissionDenied:
+		return twirp.PermissionDenied
+	case errors.KindResourceExhausted:
+		return twirp.ResourceExhausted
+	case errors.KindFailedPrecondition:
+		return twirp.FailedPrecondition
+	case errors.KindAborted:
+		return twirp.Aborted
+	case errors.KindOutOfRange:
+		return twirp.OutOfRange
+	case errors.KindUnimplemented:
+		return twirp.Unimplemented
+	case errors.KindInternal:
+		return twirp.Internal
+	case errors.KindUnavailable:
+		return twirp.Unavailable
+	case errors.KindDataLoss:
+		return twirp.DataLoss
+	case errors.KindUnauthenticated:
+		return twirp.Unauthenticated
+	case errors.KindUnknown:
+		return twirp.Unknown
+	}
+
+	return twirp.Unknown
+}
+
+// Kind returns the kind matching the Twirp error code c.
+// The codes specific to Twirp are mapped to the closest kind.
+func Kind(c twirp.ErrorCode) errors.Kind {
+	switch c {
+	case twirp.Canceled:
+		return errors.KindCanceled
+	case twirp.InvalidArgument, twirp.Malformed:
+		return errors.KindInvalidArgument
+	case twirp.DeadlineExceeded:
+		return errors.KindDeadlineExceeded
+	case twirp.NotFound, twirp.BadRoute:
+		return errors.KindNotFound
+	case twirp.AlreadyExists:
+		return errors.KindAlreadyExists
+	case twirp.PermissionDenied:
+		return errors.KindPermissionDenied
+	case twirp.Unauthenticated:
+		return errors.KindUnauthenticated
+	case twirp.ResourceExhausted:
+		return errors.KindResourceExhausted
+	case twirp.FailedPrecondition:
+		return errors.KindFailedPrecondition
+	case twirp.Aborted:
+		return errors.KindAborted
+	case twirp.OutOfRange:
+		return errors.KindOutOfRange
+	case twirp.Unimplemented:
+		return errors.KindUnimplemented
+	case twirp.Internal:
+		return errors.KindInternal
+	case twirp.Unavailable:
+		return errors.KindUnavailable
+	case twirp.DataLoss:
+		return errors.KindDataLoss
+	case twirp.Unknown, twirp.NoError:
+		return errors.KindUnknown
+	}
+
+	return errors.KindUnknown
+}
@@ -0,0 +1,80 @@
+package twirpx
+
+import (
+	stderrors "errors"
+	"io"
+	"testing"
+
+	"github.com/hexbee-net/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/twitchtv/twirp"
+)
+
+func TestToTwirpNil(t *testing.T) {
+	assert.Nil(t, ToTwirp(nil))
+	assert.Nil(t, FromTwirp(nil))
+}
+
+func TestToTwirp(t *testing.T) {
+	err := errors.WithCode(
+		errors.WithKind(errors.WithField(errors.Wrap(io.EOF, "read error"), "id", 42), errors.KindNotFound),
+		"user.not_found",
+	)
+
+	twerr := ToTwirp(err)
+
+	assert.Equal(t, twirp.NotFound, twerr.Code())
+	assert.Equal(t, "read error: EOF", twerr.Msg())
+	assert.Equal(t, map[string]string{"id": "42", CodeMetaKey: "user.not_found"}, twerr.MetaMap())
+	assert.True(t, stderrors.Is(twerr, io.EOF))
+
+	twerr = ToTwirp(errors.WithUserMessage(err, "The user does not exist."))
+	assert.Equal(t, "The user does not exist.", twerr.Msg())
+
+	assert.Equal(t, twirp.Unknown, ToTwirp(io.EOF).Code())
+}
+
+func TestFromTwirp(t *testing.T) {
+	twerr := twirp.NewError(twirp.NotFound, "The user does not exist.").
+		WithMeta("id", "42").
+		WithMeta(CodeMetaKey, "user.not_found")
+
+	err := FromTwirp(twerr)
+
+	assert.Equal(t, "The user does not exist.", err.Error())
+	assert.Equal(t, "The user does not exist.", errors.GetUserMessage(err))
+	assert.Equal(t, errors.KindNotFound, errors.GetKind(err))
+	assert.Equal(t, "user.not_found", errors.GetCode(err))
+	assert.Equal(t, errors.Fields{"id": "42"}, errors.GetFields(err))
+	assert.True(t, stderrors.Is(err, errors.WithCode(io.EOF, "user.not_found")))
+}
+
+func TestKindRoundTrip(t *testing.T) {
+	kinds := []errors.Kind{
+		errors.KindUnknown,
+		errors.KindCanceled,
+		errors.KindInvalidArgument,
+		errors.KindDeadlineExceeded,
+		errors.KindNotFound,
+		errors.KindAlreadyExists,
+		errors.KindPermissionDenied,
+		errors.KindResourceExhausted,
+		errors.KindFailedPrecondition,
+		errors.KindAborted,
+		errors.KindOutOfRange,
+		errors.KindUnimplemented,
+		errors.KindInternal,
+		errors.KindUnavailable,
+		errors.KindDataLoss,
+		errors.KindUnauthenticated,
+	}
+
+	for _, k := range kinds {
+		assert.True(t, twirp.IsValidErrorCode(ErrorCode(k)), k)
+		assert.Equal(t, k, Kind(ErrorCode(k)))
+	}
+
+	assert.Equal(t, twirp.Unknown, ErrorCode("custom"))
+	assert.Equal(t, errors.KindInvalidArgument, Kind(twirp.Malformed))
+	assert.Equal(t, errors.KindNotFound, Kind(twirp.BadRoute))
+}
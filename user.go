@@ -0,0 +1,97 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+)
+
+// GetUserMessage returns the outermost user message annotating the error stack.
+// Unlike the message returned by Error, the user message is meant to be shown
+// to the end users of the application.
+// Layers reporting an empty user message are skipped.
+// If no user message is found, an empty string will be returned.
+func GetUserMessage(err error) string {
+	type userMessager interface {
+		UserMessage() string
+	}
+
+	for depth := 0; err != nil && depth < MaxChainDepth(); depth++ {
+		if u, ok := err.(userMessager); ok && u.UserMessage() != "" {
+			return u.UserMessage()
+		}
+
+		cause, ok := err.(causer)
+		if !ok {
+			break
+		}
+
+		err = cause.Cause()
+	}
+
+	return ""
+}
+
+// /////////////////////////////////////////////////////////////////////////////
+
+type withUserMessage struct {
+	cause error
+	msg   string
+}
+
+// WithUserMessage annotates err with a message safe to show to end users.
+// The message returned by Error is left unchanged.
+// If err is nil, WithUserMessage returns nil.
+func WithUserMessage(err error, message string) error {
+	if err == nil {
+		return nil
+	}
+
+	return runHooks(&withUserMessage{
+		cause: err,
+		msg:   message,
+	}, err)
+}
+
+func (w *withUserMessage) Error() string {
+	return w.cause.Error()
+}
+
+func (w *withUserMessage) Cause() error {
+	return w.cause
+}
+
+// Unwrap provides compatibility for Go 1.13 error chains.
+func (w *withUserMessage) Unwrap() error {
+	return w.cause
+}
+
+func (w *withUserMessage) UserMessage() string {
+	return w.msg
+}
+
+func (w *withUserMessage) Format(s fmt.State, verb rune) {
+	switch verb {
+	case 'v':
+		if s.Flag('#') {
+			formatGoString(s, w)
+
+			return
+		}
+
+		if s.Flag('+') {
+			if customFormat(s, w) {
+				return
+			}
+
+			formatCause(s, w.Cause())
+			_, _ = io.WriteString(s, "\n")
+			formatAnnotation(s, "user message", w.msg)
+
+			return
+		}
+
+		fallthrough
+	case 's', 'q':
+		_, _ = io.WriteString(s, w.Error())
+	}
+}
@@ -0,0 +1,49 @@
+package errors
+
+import (
+	"fmt"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithUserMessageNil(t *testing.T) {
+	assert.Nil(t, WithUserMessage(nil, "message"))
+}
+
+func TestGetUserMessage(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{nil, ""},
+		{io.EOF, ""},
+		{WithUserMessage(io.EOF, "Please retry."), "Please retry."},
+		{Wrap(WithUserMessage(io.EOF, "Please retry."), "read error"), "Please retry."},
+		{WithUserMessage(WithUserMessage(io.EOF, "inner"), "outer"), "outer"},
+		{WithUserMessage(WithUserMessage(io.EOF, "inner"), ""), "inner"},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, GetUserMessage(tt.err))
+	}
+}
+
+func TestUserMessageFormat(t *testing.T) {
+	err := WithUserMessage(io.EOF, "Please retry.")
+
+	assert.Equal(t, "EOF", err.Error())
+	assert.Equal(t, "EOF", fmt.Sprintf("%v", err))
+	assert.Equal(t, "EOF\n  user message: Please retry.\n", fmt.Sprintf("%+v", err))
+	assert.Equal(t, []error{err}, Unpack(err))
+}
+
+func TestUserMessageRemote(t *testing.T) {
+	err := Wrap(WithUserMessage(New("boom"), "Please retry."), "read error")
+
+	data, e := ToJSON(err)
+	assert.NoError(t, e)
+	assert.Contains(t, string(data), `"user_message":"Please retry."`)
+	assert.Equal(t, "Please retry.", GetUserMessage(FromJSON(data)))
+}
@@ -0,0 +1,160 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"strings"
+)
+
+// ViolationsField is the field holding the violations of a Validation, so
+// that they are logged and become an extension member of its Problem Details.
+const ViolationsField = "violations"
+
+// validationMessage is the message of a Validation before its violations.
+const validationMessage = "validation failed"
+
+// Violation is a rule that a field of an input does not follow.
+type Violation struct {
+	// Path locates the field, such as "address.street" or "items[2].name".
+	Path    string      `json:"path" yaml:"path"`
+	Rule    string      `json:"rule" yaml:"rule"`
+	Message string      `json:"message" yaml:"message"`
+	Value   interface{} `json:"value,omitempty" yaml:"value,omitempty"`
+}
+
+// Validation accumulates the violations found while validating an input,
+// such as the body of a request:
+//
+//     v := errors.NewValidation()
+//
+//     if u.Email == "" {
+//            v.Add("email", "required", "must be set", u.Email)
+//     }
+//
+//     v.Merge("address", u.Address.Validate())
+//
+//     return v.ErrorOrNil()
+//
+// A Validation is of the KindInvalidArgument kind, with the HTTP status 422
+// Unprocessable Entity, and its messages are meant for the end users.
+// A Validation is not safe for concurrent use.
+type Validation struct {
+	violations []Violation
+}
+
+// NewValidation returns an empty Validation.
+func NewValidation() *Validation {
+	return &Validation{}
+}
+
+// Add records that the field at path breaks rule, described by message, with
+// the invalid value.
+func (v *Validation) Add(path, rule, message string, value interface{}) {
+	v.violations = append(v.violations, Violation{Path: path, Rule: rule, Message: message, Value: value})
+}
+
+// Merge records the violations of the first Validation found in the chain of
+// err, under prefix, such as the name of a nested struct.
+// If err is nil or carries no Validation, Merge does nothing.
+func (v *Validation) Merge(prefix string, err error) {
+	for depth := 0; err != nil && depth < MaxChainDepth(); depth++ {
+		if nested, ok := err.(*Validation); ok {
+			for _, violation := range nested.violations {
+				violation.Path = joinPath(prefix, violation.Path)
+				v.violations = append(v.violations, violation)
+			}
+
+			return
+		}
+
+		err = causeOf(err)
+	}
+}
+
+// joinPath returns the path of a field of the value at prefix.
+func joinPath(prefix, path string) string {
+	switch {
+	case prefix == "":
+		return path
+	case path == "", strings.HasPrefix(path, "["):
+		return prefix + path
+	default:
+		return prefix + "." + path
+	}
+}
+
+// Violations returns the recorded violations, which must not be modified.
+func (v *Validation) Violations() []Violation {
+	return v.violations
+}
+
+// ErrorOrNil returns v, or nil if v is nil or holds no violations.
+func (v *Validation) ErrorOrNil() error {
+	if v == nil || len(v.violations) == 0 {
+		return nil
+	}
+
+	return v
+}
+
+// Error returns the violations, without their values.
+func (v *Validation) Error() string {
+	var b strings.Builder
+
+	b.WriteString(validationMessage)
+
+	for i, violation := range v.violations {
+		sep := "; "
+		if i == 0 {
+			sep = ": "
+		}
+
+		b.WriteString(sep)
+
+		if violation.Path != "" {
+			b.WriteString(violation.Path + ": ")
+		}
+
+		b.WriteString(violation.Message)
+	}
+
+	return b.String()
+}
+
+func (v *Validation) Kind() Kind {
+	return KindInvalidArgument
+}
+
+func (v *Validation) HTTPStatus() int {
+	return http.StatusUnprocessableEntity
+}
+
+// UserMessage returns the message of v, made of the messages of the
+// violations only.
+func (v *Validation) UserMessage() string {
+	return v.Error()
+}
+
+// Fields returns the violations as ViolationsField.
+func (v *Validation) Fields() Fields {
+	if len(v.violations) == 0 {
+		return nil
+	}
+
+	return Fields{ViolationsField: v.violations}
+}
+
+// MarshalJSON encodes v as an object holding its message and violations:
+//
+//     {"message": "validation failed", "violations": [{"path": "email", ...}]}
+func (v *Validation) MarshalJSON() ([]byte, error) {
+	violations := v.violations
+	if violations == nil {
+		violations = make([]Violation, 0)
+	}
+
+	return json.Marshal(struct {
+		Message    string      `json:"message"`
+		Violations []Violation `json:"violations"`
+	}{validationMessage, violations})
+}
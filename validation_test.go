@@ -0,0 +1,68 @@
+package errors
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidation(t *testing.T) {
+	v := NewValidation()
+
+	assert.Nil(t, v.ErrorOrNil())
+	assert.Nil(t, (*Validation)(nil).ErrorOrNil())
+
+	v.Add("email", "required", "must be set", "")
+
+	address := NewValidation()
+	address.Add("street", "required", "must be set", nil)
+	address.Add("zip", "pattern", "must be 5 digits", "12ab")
+
+	items := NewValidation()
+	items.Add("[2].name", "max", "must be at most 10 characters", "a very long name")
+
+	v.Merge("address", WithStack(address))
+	v.Merge("items", items)
+	v.Merge("ignored", New("not a validation"))
+	v.Merge("ignored", nil)
+
+	err := Wrap(v.ErrorOrNil(), "creating user")
+
+	assert.Equal(t, []Violation{
+		{Path: "email", Rule: "required", Message: "must be set", Value: ""},
+		{Path: "address.street", Rule: "required", Message: "must be set"},
+		{Path: "address.zip", Rule: "pattern", Message: "must be 5 digits", Value: "12ab"},
+		{Path: "items[2].name", Rule: "max", Message: "must be at most 10 characters", Value: "a very long name"},
+	}, v.Violations())
+
+	assert.Equal(t, "validation failed: email: must be set; address.street: must be set; "+
+		"address.zip: must be 5 digits; items[2].name: must be at most 10 characters", v.Error())
+	assert.Equal(t, KindInvalidArgument, GetKind(err))
+	assert.Equal(t, http.StatusUnprocessableEntity, HTTPStatus(err))
+	assert.Equal(t, v.Error(), GetUserMessage(err))
+
+	p := ToProblem(err)
+	assert.Equal(t, http.StatusUnprocessableEntity, p.Status)
+	assert.Equal(t, v.Violations(), p.Extensions[ViolationsField])
+}
+
+func TestValidationMarshalJSON(t *testing.T) {
+	v := NewValidation()
+
+	data, err := json.Marshal(v)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"message": "validation failed", "violations": []}`, string(data))
+
+	v.Add("age", "min", "must be positive", -1)
+	v.Add("", "exclusive", "only one of a or b may be set", nil)
+
+	data, err = json.Marshal(v)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"message": "validation failed", "violations": [
+		{"path": "age", "rule": "min", "message": "must be positive", "value": -1},
+		{"path": "", "rule": "exclusive", "message": "only one of a or b may be set"}
+	]}`, string(data))
+	assert.Equal(t, "validation failed: age: must be positive; only one of a or b may be set", v.Error())
+}
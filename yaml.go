@@ -0,0 +1,59 @@
+package errors
+
+import (
+	"bytes"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ToYAML returns the structured representation of err as a YAML document,
+// for command line tools rendering failures in YAML.
+// Field values are capped by the limits set by SetFieldLimits, and the ones
+// that cannot be encoded are replaced by their default format.
+func ToYAML(err error) ([]byte, error) {
+	const indent = 2
+
+	doc := NewDocument(err)
+
+	limits := GetFieldLimits()
+
+	for i := range doc.Layers {
+		for k, v := range doc.Layers[i].Fields {
+			v = limits.Limit(k, v)
+			doc.Layers[i].Fields[k] = v
+
+			if !yamlEncodable(v) {
+				doc.Layers[i].Fields[k] = FormatField(k, v)
+			}
+		}
+	}
+
+	var buf bytes.Buffer
+
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(indent)
+
+	if err := enc.Encode(doc); err != nil {
+		return nil, err
+	}
+
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// yamlEncodable reports whether v can be encoded, the encoder panicking on
+// unsupported types.
+func yamlEncodable(v interface{}) (ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+
+	_, err := yaml.Marshal(v)
+
+	return err == nil
+}
@@ -0,0 +1,56 @@
+package errors
+
+import (
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"gopkg.in/yaml.v3"
+)
+
+type unencodable struct {
+	C chan int
+}
+
+func (unencodable) String() string { return "unencodable" }
+
+func TestToYAML(t *testing.T) {
+	err := WithKind(WithFields(WithMessage(io.EOF, "read error"), Fields{
+		"file":    "data.txt",
+		"handler": unencodable{},
+	}), KindDataLoss)
+
+	b, e := ToYAML(err)
+	assert.NoError(t, e)
+
+	assert.Equal(t, `message: 'read error: EOF'
+layers:
+  - message: read error
+    kind: data_loss
+    fields:
+      file: data.txt
+      handler: unencodable
+  - message: EOF
+`, string(b))
+}
+
+func TestToYAMLStack(t *testing.T) {
+	requireStacks(t)
+
+	b, e := ToYAML(Wrap(io.EOF, "read error"))
+	assert.NoError(t, e)
+
+	var doc Document
+
+	assert.NoError(t, yaml.Unmarshal(b, &doc))
+	assert.Len(t, doc.Layers, 2)
+	assert.NotEmpty(t, doc.Layers[0].Stack)
+	assert.Equal(t, "github.com/hexbee-net/errors.TestToYAMLStack", doc.Layers[0].Stack[0].Function)
+	assert.NotZero(t, doc.Layers[0].Stack[0].Line)
+}
+
+func TestToYAMLNil(t *testing.T) {
+	b, e := ToYAML(nil)
+	assert.NoError(t, e)
+	assert.Equal(t, "message: \"\"\nlayers: []\n", string(b))
+}